@@ -1,15 +1,30 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/config"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/expression"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/bolt"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/cache"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/postgres"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/sqlite"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/discount"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
 )
 
 func main() {
@@ -17,14 +32,174 @@ func main() {
 	logger := zap.Must(zap.NewProduction()).Sugar()
 	defer logger.Sync()
 
-	repo := memory.New()
-	svc := service.New(repo)
+	if err := checkVolatileState(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	opts := []service.Option{
+		service.WithCodePolicy(cfg.CodePolicy),
+		service.WithWallet(memory.NewWallet()),
+		service.WithPromotions(memory.NewPromotions()),
+		service.WithTriggers(memory.NewTriggers()),
+		service.WithReferrals(memory.NewReferrals(), cfg.ReferralProgram),
+		service.WithRedemptions(memory.NewRedemptions()),
+		// Packages registering custom strategies with discount.Register
+		// only need to be imported here.
+		service.WithDiscounts(discount.Default()),
+	}
+	if cfg.CodeCheckDigit {
+		luhn, err := couponcode.NewLuhn(cfg.CodeAlphabet)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, service.WithCheckDigit(luhn))
+	}
+
+	engine, err := expression.New(cfg.ExpressionCostLimit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts = append(opts, service.WithExpressions(engine))
+
+	if cfg.TokenKeys != "" {
+		opt, err := tokenOption(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, opt)
+	}
+
+	repo, closeRepo, err := newRepository(cfg, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeRepo()
+
+	// The bolt file also keeps the redemption history, overriding the memory
+	// one above, and can be backed up online.
+	if repo, ok := repo.(*bolt.Repository); ok {
+		opts = append(opts, service.WithRedemptions(repo.Redemptions()), service.WithBackups(repo))
+	}
+
+	// Wrapping the repository hides its type, so the cache comes last.
+	if cfg.CacheSize > 0 {
+		cached := cache.New(repo, cache.Options{
+			Size:        cfg.CacheSize,
+			TTL:         cfg.CacheTTL,
+			NegativeTTL: cfg.CacheNegativeTTL,
+		})
+		repo = cached
+		opts = append(opts, service.WithCache(cached))
+	}
+
+	svc := service.New(repo, opts...)
+	if err := svc.CheckReferrals(); err != nil {
+		log.Fatal(err)
+	}
 
 	app := api.New(cfg, logger, svc)
 
 	router := app.Mount(gin.DebugMode)
 
-	if err := app.Run(router); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = app.Run(ctx, router)
+	// Generation jobs write to the repository, so they are stopped before
+	// it is closed.
+	svc.Close()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// newRepository opens the coupon repository selected in the config and
+// returns a function closing it.
+func newRepository(cfg config.Config, logger *zap.SugaredLogger) (service.Repository, func() error, error) {
+	switch cfg.Repository {
+	case "memory":
+		if cfg.MemoryDataDir == "" {
+			return memory.New(), func() error { return nil }, nil
+		}
+
+		repo, err := memory.OpenDurable(cfg.MemoryDataDir, memory.DurableOptions{
+			Sync:         cfg.MemorySync,
+			SyncInterval: cfg.MemorySyncInterval,
+			CompactAfter: cfg.MemoryCompactAfter,
+			Logger:       logger,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, repo.Close, nil
+	case "sqlite":
+		repo, err := sqlite.Open(context.Background(), cfg.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, repo.Close, nil
+	case "bolt":
+		repo, err := bolt.Open(cfg.BoltPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, repo.Close, nil
+	case "postgres":
+		poolConfig, err := pgxpool.ParseConfig(cfg.PostgresURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cfg.PostgresMaxConns > 0 {
+			poolConfig.MaxConns = int32(cfg.PostgresMaxConns)
+		}
+
+		repo, err := postgres.Open(context.Background(), poolConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		return repo, func() error { repo.Close(); return nil }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown repository %q", cfg.Repository)
+	}
+}
+
+// checkVolatileState refuses a persistent coupon repository next to the
+// in-memory wallet, promotions, triggers, referrals and, outside bolt,
+// redemptions unless losing those on restart is accepted. Coupons surviving
+// a restart without them would lose their activations and usage history,
+// and rewards could be issued twice.
+func checkVolatileState(cfg config.Config) error {
+	persistent := cfg.Repository != "memory" || cfg.MemoryDataDir != ""
+	if !persistent || cfg.VolatileState {
+		return nil
+	}
+
+	volatile := "wallet, promotions, triggers, referrals and redemptions"
+	if cfg.Repository == "bolt" {
+		volatile = "wallet, promotions, triggers and referrals"
+	}
+	return fmt.Errorf("the %s repository persists coupons, but the %s are kept in memory; "+
+		"set VOLATILE_STATE=true to accept losing them on restart", cfg.Repository, volatile)
+}
+
+func tokenOption(cfg config.Config) (service.Option, error) {
+	keys, err := token.ParseKeys(cfg.TokenKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring := token.NewKeyring(keys...)
+
+	var signer token.Signer
+	if cfg.TokenSigningKey != "" {
+		key, ok := keyring.Key(cfg.TokenSigningKey)
+		if !ok {
+			return nil, fmt.Errorf("token signing key %q not found", cfg.TokenSigningKey)
+		}
+		if signer, ok = key.(token.Signer); !ok {
+			return nil, fmt.Errorf("token key %q cannot sign", cfg.TokenSigningKey)
+		}
+	}
+
+	return service.WithTokens(signer, keyring), nil
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errAdminRequired = errors.New("admin token required")
+
+// requireAdmin rejects requests without the admin token as bearer token.
+func (app *Application) requireAdmin(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(app.config.AdminToken)) != 1 {
+		c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+		app.writeJSONError(c, http.StatusUnauthorized, errAdminRequired)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
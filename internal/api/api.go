@@ -2,10 +2,12 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -13,18 +15,32 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/config"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 type responseWriter struct {
 	gin.ResponseWriter
 	body *bytes.Buffer
 }
 
 func (rw *responseWriter) Write(data []byte) (int, error) {
-	rw.body.Write(data)
+	// Only JSON responses are logged. Downloads, such as backups and
+	// generated codes, are too large and hold data that must not end up in
+	// the logs.
+	if strings.HasPrefix(rw.Header().Get("Content-Type"), "application/json") {
+		rw.body.Write(data)
+	}
 	return rw.ResponseWriter.Write(data)
 }
 
+// Unwrap lets http.ResponseController reach the connection, e.g. to lift
+// the write deadline for long downloads.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 type Application struct {
 	config  config.Config
 	logger  *zap.SugaredLogger
@@ -82,7 +98,12 @@ func (app *Application) Mount(mode string) http.Handler {
 	gin.SetMode(mode)
 	router := gin.New()
 	router.Use(app.requestLoggerMiddleware)
-	router.Use(cors.Default())
+	// Conditional writes need If-Match sent and ETag readable cross-origin.
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AddAllowHeaders("If-Match")
+	corsConfig.AddExposeHeaders("ETag")
+	router.Use(cors.New(corsConfig))
 
 	v1 := router.Group("/v1")
 
@@ -90,13 +111,72 @@ func (app *Application) Mount(mode string) http.Handler {
 	{
 		coupons.POST("", app.Create)
 		coupons.GET("", app.Get)
+		coupons.GET("/:code", app.GetCoupon)
+		coupons.PATCH("/:code", app.Update)
+		coupons.DELETE("/:code", app.Delete)
 		coupons.POST("/basket", app.Apply)
+		coupons.POST("/explain", app.Explain)
+		coupons.POST("/redeem", app.Redeem)
+		coupons.POST("/generate", app.Generate)
+	}
+
+	customers := v1.Group("/customers")
+	{
+		customers.GET("/:id/coupons", app.GetCustomerCoupons)
+		customers.GET("/:id/wallet", app.GetWallet)
+		customers.PUT("/:id/wallet/:code", app.ActivateCoupon)
+		customers.DELETE("/:id/wallet/:code", app.DeactivateCoupon)
+		customers.PUT("/:id/referral", app.GetReferralCode)
+		customers.GET("/:id/referral/stats", app.GetReferralStats)
+	}
+
+	v1.POST("/referrals/:code/redeem", app.RedeemReferral)
+
+	// Tokens are accepted offline and cannot be revoked, so only admins can
+	// issue them, while anyone can verify them.
+	tokens := v1.Group("/tokens")
+	{
+		if app.config.AdminToken != "" {
+			tokens.POST("", app.requireAdmin, app.IssueToken)
+		}
+		tokens.POST("/verify", app.VerifyToken)
+	}
+
+	promotions := v1.Group("/promotions")
+	{
+		promotions.POST("", app.CreatePromotion)
+		promotions.GET("", app.GetPromotions)
+	}
+
+	v1.POST("/pricing", app.Price)
+
+	triggers := v1.Group("/triggers")
+	{
+		triggers.POST("", app.CreateTrigger)
+		triggers.GET("", app.GetTriggers)
+	}
+
+	v1.POST("/events", app.HandleEvent)
+
+	jobs := v1.Group("/jobs")
+	{
+		jobs.GET("/:id", app.GetJob)
+		jobs.GET("/:id/download", app.DownloadJob)
+	}
+
+	// Backups hold every coupon and redemption, so they are served to
+	// admins only, and not at all without an admin token.
+	if app.config.AdminToken != "" {
+		v1.GET("/backup", app.requireAdmin, app.Backup)
 	}
+	v1.GET("/cache", app.GetCacheStats)
 
 	return router
 }
 
-func (app *Application) Run(mux http.Handler) error {
+// Run serves mux until it fails or ctx is done, in which case the requests
+// in flight are given shutdownTimeout to complete.
+func (app *Application) Run(ctx context.Context, mux http.Handler) error {
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", app.config.Addr),
 		Handler:      mux,
@@ -109,12 +189,23 @@ func (app *Application) Run(mux http.Handler) error {
 		zap.String("on", srv.Addr),
 	)
 
-	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
 		app.logger.Errorw("start http server failed", "error", err)
 		return err
+	case <-ctx.Done():
 	}
 
-	return nil
+	app.logger.Info("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
 }
 
 func (app *Application) writeJSONResponse(c *gin.Context, status int, data any) {
@@ -122,5 +213,10 @@ func (app *Application) writeJSONResponse(c *gin.Context, status int, data any)
 }
 
 func (app *Application) writeJSONError(c *gin.Context, status int, err error) {
+	// Storage outages reach every handler as unexpected errors. They are
+	// reported as 503 so clients know to retry.
+	if status == http.StatusInternalServerError && errors.Is(err, service.ErrUnavailable) {
+		status = http.StatusServiceUnavailable
+	}
 	c.JSON(status, gin.H{"error": err.Error()})
 }
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+// backupChecksumTrailer is sent after a complete backup, so clients can tell
+// it apart from one cut off by a failure.
+const backupChecksumTrailer = "X-Backup-SHA256"
+
+// backupWriter sends the response headers with the first chunk of the
+// backup, so errors before it can still be reported with a status code. It
+// hashes the backup for the checksum trailer.
+type backupWriter struct {
+	c       *gin.Context
+	hash    hash.Hash
+	started bool
+}
+
+func (w *backupWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.start()
+	}
+	n, err := w.c.Writer.Write(p)
+	w.hash.Write(p[:n])
+	return n, err
+}
+
+func (w *backupWriter) start() {
+	w.started = true
+	name := fmt.Sprintf("coupons-%s.bolt", time.Now().UTC().Format("20060102T150405Z"))
+	w.c.Header("Content-Type", "application/octet-stream")
+	w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	w.c.Header("Trailer", backupChecksumTrailer)
+	w.c.Status(http.StatusOK)
+	w.c.Writer.WriteHeaderNow()
+}
+
+func (app *Application) Backup(c *gin.Context) {
+	// Large backups take longer than the server's write timeout.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		app.logger.Warnw("backup is subject to the write timeout", "error", err)
+	}
+
+	w := &backupWriter{c: c, hash: sha256.New()}
+
+	if err := app.service.Backup(c.Request.Context(), w); err != nil {
+		app.logger.Errorw("error occurred while writing backup", "error", err)
+		if w.started {
+			// The status is sent already; aborting leaves the client with a
+			// truncated body and without the checksum trailer.
+			c.Abort()
+			return
+		}
+
+		switch err {
+		case service.ErrBackupsDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if !w.started {
+		// An empty backup still declares the trailer.
+		w.start()
+	}
+	c.Writer.Header().Set(backupChecksumTrailer, hex.EncodeToString(w.hash.Sum(nil)))
+}
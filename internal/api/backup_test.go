@@ -0,0 +1,192 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/config"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestBackup(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestBackup in long mode.")
+	}
+
+	writeBackup := func(args mock.Arguments) {
+		_, _ = io.WriteString(args.Get(1).(io.Writer), "backup")
+	}
+
+	type testCase struct {
+		name           string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		wantBody       string
+		wantChecksum   string
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful backup",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("Backup", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Run(writeBackup).
+					Return(nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "backup",
+			wantChecksum:   "54d00d867758cef816bc4685f58e327b949712b07ebd17c3485f3ffc9e9f5133",
+		},
+		{
+			name: "Backups disabled",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("Backup", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(service.ErrBackupsDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+		{
+			name: "Failure before streaming",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("Backup", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(errors.New("error")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+		{
+			name: "Failure while streaming",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("Backup", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Run(writeBackup).
+					Return(errors.New("error")).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "backup",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/backup", app.Backup)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/backup", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantBody != "" {
+				assert.Equal(t, tc.wantBody, w.Body.String())
+				assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+				assert.Equal(t, tc.wantChecksum, w.Result().Trailer.Get("X-Backup-SHA256"), "expected only complete backups to carry a checksum")
+			}
+		})
+	}
+}
+
+func TestBackupRequiresAdmin(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestBackupRequiresAdmin in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		adminToken     string
+		authorization  string
+		wantStatusCode int
+	}
+
+	tests := []testCase{
+		{name: "No admin token configured", authorization: "Bearer ", wantStatusCode: http.StatusNotFound},
+		{name: "Missing token", adminToken: "secret", wantStatusCode: http.StatusUnauthorized},
+		{name: "Wrong token", adminToken: "secret", authorization: "Bearer wrong", wantStatusCode: http.StatusUnauthorized},
+		{name: "Wrong scheme", adminToken: "secret", authorization: "Basic secret", wantStatusCode: http.StatusUnauthorized},
+		{name: "Admin token", adminToken: "secret", authorization: "Bearer secret", wantStatusCode: http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			if tc.wantStatusCode == http.StatusOK {
+				srv.On("Backup", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil).
+					Once()
+			}
+			defer srv.AssertExpectations(t)
+
+			app := api.New(config.Config{AdminToken: tc.adminToken}, zap.NewNop().Sugar(), srv)
+			router := app.Mount(gin.TestMode)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/backup", nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+		})
+	}
+}
+
+func TestBackupOutlastsWriteTimeout(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestBackupOutlastsWriteTimeout in long mode.")
+	}
+
+	srv := mocks.NewService(t)
+	srv.On("Backup", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+		Run(func(args mock.Arguments) {
+			w := args.Get(1).(io.Writer)
+			_, _ = io.WriteString(w, "back")
+			time.Sleep(200 * time.Millisecond)
+			_, _ = io.WriteString(w, "up")
+		}).
+		Return(nil).
+		Once()
+
+	app := api.New(config.Config{AdminToken: "secret"}, zap.NewNop().Sugar(), srv)
+	server := httptest.NewUnstartedServer(app.Mount(gin.TestMode))
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/backup", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err, "expected the backup not to be cut off by the write timeout")
+	assert.Equal(t, "backup", string(body))
+	assert.Equal(t, "54d00d867758cef816bc4685f58e327b949712b07ebd17c3485f3ffc9e9f5133", resp.Trailer.Get("X-Backup-SHA256"))
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type CacheStats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hitRatio"`
+	Entries  int     `json:"entries"`
+}
+
+func newCacheStats(stats *domain.CacheStats) CacheStats {
+	var ratio float64
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		ratio = float64(stats.Hits) / float64(lookups)
+	}
+
+	return CacheStats{
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+		HitRatio: ratio,
+		Entries:  stats.Entries,
+	}
+}
+
+func (app *Application) GetCacheStats(c *gin.Context) {
+	stats, err := app.service.CacheStats(c.Request.Context())
+	if err != nil {
+		app.logger.Errorw("error occurred while getting cache stats", "error", err)
+		switch err {
+		case service.ErrCacheDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, newCacheStats(stats))
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestGetCacheStats(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetCacheStats in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		wantBody       string
+	}
+
+	tests := []testCase{
+		{
+			name: "Cache stats",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CacheStats", mock.MatchedBy(func(_ context.Context) bool { return true })).
+					Return(&domain.CacheStats{Hits: 3, Misses: 1, Entries: 2}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"data":{"hits":3,"misses":1,"hitRatio":0.75,"entries":2}}`,
+		},
+		{
+			name: "No lookups yet",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CacheStats", mock.MatchedBy(func(_ context.Context) bool { return true })).
+					Return(&domain.CacheStats{}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"data":{"hits":0,"misses":0,"hitRatio":0,"entries":0}}`,
+		},
+		{
+			name: "Cache disabled",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CacheStats", mock.MatchedBy(func(_ context.Context) bool { return true })).
+					Return(nil, service.ErrCacheDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+			wantBody:       `{"error":"coupon cache not configured"}`,
+		},
+		{
+			name: "Internal error",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CacheStats", mock.MatchedBy(func(_ context.Context) bool { return true })).
+					Return(nil, errors.New("error")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       `{"error":"error"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/cache", app.GetCacheStats)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/cache", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			assert.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
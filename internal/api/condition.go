@@ -0,0 +1,72 @@
+package api
+
+import "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+
+// Condition is a requirement on the basket lines. Type is one of "all",
+// "any", "minQuantity", "containsSku" or "minValue"; all and any combine the
+// nested conditions.
+type Condition struct {
+	Type       string      `json:"type"`
+	Conditions []Condition `json:"conditions,omitempty"`
+	Category   string      `json:"category,omitempty"`
+	SKU        string      `json:"sku,omitempty"`
+	Min        int         `json:"min,omitempty"`
+}
+
+// Line is a basket line. Deposit lines never count toward coupon
+// conditions.
+type Line struct {
+	SKU       string `json:"sku"`
+	Category  string `json:"category,omitempty"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice int    `json:"unitPrice"`
+	Deposit   bool   `json:"deposit,omitempty"`
+}
+
+func parseConditions(conditions []Condition) []domain.Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	parsed := make([]domain.Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		parsed = append(parsed, domain.Condition{
+			Type:       domain.ConditionType(condition.Type),
+			Conditions: parseConditions(condition.Conditions),
+			Category:   condition.Category,
+			SKU:        condition.SKU,
+			Min:        condition.Min,
+		})
+	}
+	return parsed
+}
+
+func newConditions(conditions []domain.Condition) []Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	resp := make([]Condition, 0, len(conditions))
+	for _, condition := range conditions {
+		resp = append(resp, Condition{
+			Type:       string(condition.Type),
+			Conditions: newConditions(condition.Conditions),
+			Category:   condition.Category,
+			SKU:        condition.SKU,
+			Min:        condition.Min,
+		})
+	}
+	return resp
+}
+
+func parseLines(lines []Line) []domain.Line {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	parsed := make([]domain.Line, 0, len(lines))
+	for _, line := range lines {
+		parsed = append(parsed, domain.Line(line))
+	}
+	return parsed
+}
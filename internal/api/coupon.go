@@ -1,7 +1,9 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -12,9 +14,21 @@ import (
 )
 
 type CreateCouponReq struct {
-	Code           string `json:"code" binding:"required"`
-	Discount       int    `json:"discount" binding:"required"`
-	MinBasketValue int    `json:"minBasketValue" binding:"required"`
+	Code               string          `json:"code" binding:"required"`
+	Discount           int             `json:"discount" binding:"required"`
+	MinBasketValue     int             `json:"minBasketValue" binding:"required"`
+	CustomerIDs        []string        `json:"customerIds,omitempty"`
+	Segments           []string        `json:"segments,omitempty"`
+	RequiresActivation bool            `json:"requiresActivation,omitempty"`
+	Schedule           *Schedule       `json:"schedule,omitempty"`
+	Channels           *Restriction    `json:"channels,omitempty"`
+	Stores             *Restriction    `json:"stores,omitempty"`
+	Regions            *Restriction    `json:"regions,omitempty"`
+	UsageLimits        []UsageLimit    `json:"usageLimits,omitempty"`
+	Conditions         []Condition     `json:"conditions,omitempty"`
+	Expression         string          `json:"expression,omitempty"`
+	Strategy           string          `json:"strategy,omitempty"`
+	Params             json.RawMessage `json:"params,omitempty"`
 }
 
 func (app *Application) Create(c *gin.Context) {
@@ -28,13 +42,53 @@ func (app *Application) Create(c *gin.Context) {
 		return
 	}
 
-	err := app.service.CreateCoupon(c.Request.Context(), body.Discount, body.Code, body.MinBasketValue)
+	schedule, err := parseSchedule(body.Schedule)
+	if err != nil {
+		app.logger.Errorw("error occurred while parsing schedule", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	err = app.service.CreateCoupon(c.Request.Context(), domain.Coupon{
+		Code:           body.Code,
+		Discount:       body.Discount,
+		MinBasketValue: body.MinBasketValue,
+		Assignment: domain.Assignment{
+			CustomerIDs: body.CustomerIDs,
+			Segments:    body.Segments,
+		},
+		RequiresActivation: body.RequiresActivation,
+		Schedule:           schedule,
+		Eligibility: domain.Eligibility{
+			Channels: parseRestriction(body.Channels),
+			Stores:   parseRestriction(body.Stores),
+			Regions:  parseRestriction(body.Regions),
+		},
+		UsageLimits: parseUsageLimits(body.UsageLimits),
+		Conditions:  parseConditions(body.Conditions),
+		Expression:  body.Expression,
+		Strategy:    body.Strategy,
+		Params:      body.Params,
+	})
 	if err != nil {
 		app.logger.Errorw("error occurred while creating coupon", "error", err)
+		if errors.Is(err, service.ErrInvalidExpression) {
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		}
 		switch err {
-		case service.ErrInvalidCode, service.ErrInvalidDiscount, service.ErrInvalidMinBasketValue:
+		case service.ErrInvalidCode, service.ErrMalformedCode, service.ErrInvalidCodeFormat, service.ErrInvalidDiscount,
+			service.ErrInvalidMinBasketValue, service.ErrInvalidAssignment, service.ErrInvalidSchedule,
+			service.ErrInvalidEligibility, service.ErrInvalidUsageLimit, service.ErrInvalidCondition,
+			service.ErrInvalidStrategy, service.ErrInvalidDiscountParams:
 			app.writeJSONError(c, http.StatusBadRequest, err)
 			return
+		case service.ErrCouponExists:
+			app.writeJSONError(c, http.StatusConflict, err)
+			return
+		case service.ErrRedemptionsDisabled, service.ErrExpressionsDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
 		default:
 			app.writeJSONError(c, http.StatusInternalServerError, err)
 			return
@@ -45,11 +99,211 @@ func (app *Application) Create(c *gin.Context) {
 }
 
 type Coupon struct {
-	Code           string `json:"code"`
-	Discount       int    `json:"discount"`
-	MinBasketValue int    `json:"minBasketValue"`
+	Code               string          `json:"code"`
+	Discount           int             `json:"discount"`
+	MinBasketValue     int             `json:"minBasketValue"`
+	CustomerIDs        []string        `json:"customerIds,omitempty"`
+	Segments           []string        `json:"segments,omitempty"`
+	RequiresActivation bool            `json:"requiresActivation,omitempty"`
+	Schedule           *Schedule       `json:"schedule,omitempty"`
+	Channels           *Restriction    `json:"channels,omitempty"`
+	Stores             *Restriction    `json:"stores,omitempty"`
+	Regions            *Restriction    `json:"regions,omitempty"`
+	UsageLimits        []UsageLimit    `json:"usageLimits,omitempty"`
+	Conditions         []Condition     `json:"conditions,omitempty"`
+	Expression         string          `json:"expression,omitempty"`
+	Strategy           string          `json:"strategy,omitempty"`
+	Params             json.RawMessage `json:"params,omitempty"`
+	Version            int             `json:"version"`
+}
+
+func newCoupon(coupon domain.Coupon) Coupon {
+	return Coupon{
+		Code:               coupon.Code,
+		Discount:           coupon.Discount,
+		MinBasketValue:     coupon.MinBasketValue,
+		CustomerIDs:        coupon.Assignment.CustomerIDs,
+		Segments:           coupon.Assignment.Segments,
+		RequiresActivation: coupon.RequiresActivation,
+		Schedule:           newSchedule(coupon.Schedule),
+		Channels:           newRestriction(coupon.Eligibility.Channels),
+		Stores:             newRestriction(coupon.Eligibility.Stores),
+		Regions:            newRestriction(coupon.Eligibility.Regions),
+		UsageLimits:        newUsageLimits(coupon.UsageLimits),
+		Conditions:         newConditions(coupon.Conditions),
+		Expression:         coupon.Expression,
+		Strategy:           coupon.Strategy,
+		Params:             coupon.Params,
+		Version:            coupon.Version,
+	}
+}
+
+// GetCoupon returns a single coupon, with its version as the ETag to send
+// back in If-Match when changing it.
+func (app *Application) GetCoupon(c *gin.Context) {
+	coupon, err := app.service.GetCoupon(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		app.logger.Errorw("error occurred while getting coupon", "error", err)
+		switch err {
+		case service.ErrInvalidCode, service.ErrMalformedCode:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+		case service.ErrNotFound:
+			app.writeJSONError(c, http.StatusNotFound, err)
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.Header("ETag", etag(coupon.Version))
+	app.writeJSONResponse(c, http.StatusOK, newCoupon(*coupon))
+}
+
+// UpdateCouponReq changes the fields it sets and keeps the others. Set
+// fields replace the stored ones as a whole, e.g. an empty list of usage
+// limits removes them all.
+type UpdateCouponReq struct {
+	Discount           *int             `json:"discount,omitempty"`
+	MinBasketValue     *int             `json:"minBasketValue,omitempty"`
+	CustomerIDs        *[]string        `json:"customerIds,omitempty"`
+	Segments           *[]string        `json:"segments,omitempty"`
+	RequiresActivation *bool            `json:"requiresActivation,omitempty"`
+	Schedule           *Schedule        `json:"schedule,omitempty"`
+	Channels           *Restriction     `json:"channels,omitempty"`
+	Stores             *Restriction     `json:"stores,omitempty"`
+	Regions            *Restriction     `json:"regions,omitempty"`
+	UsageLimits        *[]UsageLimit    `json:"usageLimits,omitempty"`
+	Conditions         *[]Condition     `json:"conditions,omitempty"`
+	Expression         *string          `json:"expression,omitempty"`
+	Strategy           *string          `json:"strategy,omitempty"`
+	Params             *json.RawMessage `json:"params,omitempty"`
+}
+
+func (app *Application) Update(c *gin.Context) {
+	var body UpdateCouponReq
+
+	version, ok := app.requireVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	patch := domain.CouponPatch{
+		Discount:           body.Discount,
+		MinBasketValue:     body.MinBasketValue,
+		CustomerIDs:        body.CustomerIDs,
+		Segments:           body.Segments,
+		RequiresActivation: body.RequiresActivation,
+		Expression:         body.Expression,
+		Strategy:           body.Strategy,
+		Params:             body.Params,
+	}
+	if body.Channels != nil {
+		channels := parseRestriction(body.Channels)
+		patch.Channels = &channels
+	}
+	if body.Stores != nil {
+		stores := parseRestriction(body.Stores)
+		patch.Stores = &stores
+	}
+	if body.Regions != nil {
+		regions := parseRestriction(body.Regions)
+		patch.Regions = &regions
+	}
+	if body.UsageLimits != nil {
+		limits := parseUsageLimits(*body.UsageLimits)
+		patch.UsageLimits = &limits
+	}
+	if body.Conditions != nil {
+		conditions := parseConditions(*body.Conditions)
+		patch.Conditions = &conditions
+	}
+	if body.Schedule != nil {
+		schedule, err := parseSchedule(body.Schedule)
+		if err != nil {
+			app.logger.Errorw("error occurred while parsing schedule", "error", err)
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		}
+		patch.Schedule = &schedule
+	}
+
+	coupon, err := app.service.UpdateCoupon(c.Request.Context(), c.Param("code"), version, patch)
+	if err != nil {
+		app.logger.Errorw("error occurred while updating coupon", "error", err)
+		if errors.Is(err, service.ErrInvalidExpression) {
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		}
+		switch err {
+		case service.ErrInvalidCode, service.ErrMalformedCode, service.ErrInvalidDiscount,
+			service.ErrInvalidMinBasketValue, service.ErrInvalidAssignment, service.ErrInvalidSchedule,
+			service.ErrInvalidEligibility, service.ErrInvalidUsageLimit, service.ErrInvalidCondition,
+			service.ErrInvalidStrategy, service.ErrInvalidDiscountParams:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+		case service.ErrNotFound:
+			app.writeJSONError(c, http.StatusNotFound, err)
+		case service.ErrVersionMismatch:
+			app.writeJSONError(c, http.StatusPreconditionFailed, err)
+		case service.ErrRedemptionsDisabled, service.ErrExpressionsDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.Header("ETag", etag(coupon.Version))
+	app.writeJSONResponse(c, http.StatusOK, newCoupon(*coupon))
+}
+
+func (app *Application) Delete(c *gin.Context) {
+	version, ok := app.requireVersion(c)
+	if !ok {
+		return
+	}
+
+	if err := app.service.DeleteCoupon(c.Request.Context(), c.Param("code"), version); err != nil {
+		app.logger.Errorw("error occurred while deleting coupon", "error", err)
+		switch err {
+		case service.ErrInvalidCode, service.ErrMalformedCode:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+		case service.ErrNotFound:
+			app.writeJSONError(c, http.StatusNotFound, err)
+		case service.ErrVersionMismatch:
+			app.writeJSONError(c, http.StatusPreconditionFailed, err)
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
+// requireVersion reads the version a write is conditional on, writing the
+// error response if there is none.
+func (app *Application) requireVersion(c *gin.Context) (int, bool) {
+	version, err := ifMatch(c)
+	switch err {
+	case nil:
+		return version, true
+	case errPreconditionRequired:
+		app.writeJSONError(c, http.StatusPreconditionRequired, err)
+	default:
+		app.writeJSONError(c, http.StatusBadRequest, err)
+	}
+	return 0, false
+}
+
+// maxLookupCodes bounds the distinct codes of a single GET /v1/coupons.
+const maxLookupCodes = 100
+
 func (app *Application) Get(c *gin.Context) {
 	var (
 		resp []Coupon
@@ -63,22 +317,29 @@ func (app *Application) Get(c *gin.Context) {
 		return
 	}
 
-	codes := strings.Split(rawCodes, ",")
+	codes := uniqueCodes(strings.Split(rawCodes, ","))
+	if len(codes) > maxLookupCodes {
+		app.logger.Errorw("error occurred while getting coupons, too many codes", "count", len(codes))
+		app.writeJSONError(c, http.StatusBadRequest, fmt.Errorf("at most %d codes can be looked up at once", maxLookupCodes))
+		return
+	}
 
 	coupons, err := app.service.GetCoupons(c.Request.Context(), codes)
 	if err != nil {
 		app.logger.Errorw("error occurred while getting coupons", "error", err)
-		app.writeJSONError(c, http.StatusInternalServerError, err)
-		return
+		switch err {
+		case service.ErrMalformedCode:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
 	}
 
 	resp = make([]Coupon, 0, len(coupons))
 	for _, coupon := range coupons {
-		resp = append(resp, Coupon{
-			Code:           coupon.Code,
-			Discount:       coupon.Discount,
-			MinBasketValue: coupon.MinBasketValue,
-		})
+		resp = append(resp, newCoupon(coupon))
 	}
 
 	if len(resp) == 0 {
@@ -90,14 +351,69 @@ func (app *Application) Get(c *gin.Context) {
 	app.writeJSONResponse(c, http.StatusOK, resp)
 }
 
+// uniqueCodes drops repeated codes, keeping the first of each.
+func uniqueCodes(codes []string) []string {
+	unique := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		unique = append(unique, code)
+	}
+	return unique
+}
+
 type Basket struct {
-	Value           int `json:"value" binding:"required"`
-	AppliedDiscount int `json:"appliedDiscount"`
+	Value           int    `json:"value" binding:"required"`
+	AppliedDiscount int    `json:"appliedDiscount"`
+	Lines           []Line `json:"lines,omitempty"`
+}
+
+// Customer identifies the customer a request acts for. It is taken as given,
+// so only trusted callers that authenticated the customer may send it.
+type Customer struct {
+	ID       string   `json:"id"`
+	Segments []string `json:"segments,omitempty"`
+	Tier     string   `json:"tier,omitempty"`
+}
+
+// Origin describes where a basket is checked out. Coupon schedules, usage
+// limits and expressions are evaluated in TimeZone, an IANA time zone these
+// coupons require, and coupon eligibility against the channel, store and
+// region.
+type Origin struct {
+	TimeZone string `json:"timeZone,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	StoreID  string `json:"storeId,omitempty"`
+	Region   string `json:"region,omitempty"`
 }
 
 type ApplyReq struct {
-	Basket Basket `json:"basket" binding:"required"`
-	Code   string `json:"code" binding:"required"`
+	Basket   Basket    `json:"basket" binding:"required"`
+	Code     string    `json:"code" binding:"required"`
+	Customer *Customer `json:"customer,omitempty"`
+	Origin
+}
+
+func newBasket(body Basket, customer *Customer, origin Origin) domain.Basket {
+	basket := domain.Basket{
+		Value:    body.Value,
+		TimeZone: origin.TimeZone,
+		Channel:  origin.Channel,
+		StoreID:  origin.StoreID,
+		Region:   origin.Region,
+		Lines:    parseLines(body.Lines),
+	}
+	if customer != nil {
+		basket.Customer = domain.Customer{
+			ID:       customer.ID,
+			Segments: customer.Segments,
+			Tier:     customer.Tier,
+		}
+	}
+	return basket
 }
 
 func (app *Application) Apply(c *gin.Context) {
@@ -109,21 +425,39 @@ func (app *Application) Apply(c *gin.Context) {
 		return
 	}
 
-	basket := &domain.Basket{
-		Value: body.Basket.Value,
+	basket, err := app.service.ApplyCoupon(c.Request.Context(), newBasket(body.Basket, body.Customer, body.Origin), body.Code)
+	if err != nil {
+		app.logger.Errorw("error occurred while applying coupon", "error", err)
+		app.writeApplyError(c, err)
+		return
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, Basket{
+		Value:           basket.Value,
+		AppliedDiscount: basket.AppliedDiscount,
+	})
+}
+
+type RedeemReq struct {
+	ApplyReq
+	OrderID string `json:"orderId,omitempty"`
+}
+
+func (app *Application) Redeem(c *gin.Context) {
+	var body RedeemReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
 	}
 
-	basket, err := app.service.ApplyCoupon(c.Request.Context(), *basket, body.Code)
+	basket, err := app.service.RedeemCoupon(c.Request.Context(), newBasket(body.Basket, body.Customer, body.Origin),
+		body.Code, body.OrderID)
 	if err != nil {
-		app.logger.Errorw("error occurred while applying coupon", "error", err)
-		switch err {
-		case service.ErrInvalidCode, service.ErrInvalidBasketValue, service.ErrMinBasketValue, service.ErrNotFound:
-			app.writeJSONError(c, http.StatusBadRequest, err)
-			return
-		default:
-			app.writeJSONError(c, http.StatusInternalServerError, err)
-			return
-		}
+		app.logger.Errorw("error occurred while redeeming coupon", "error", err)
+		app.writeApplyError(c, err)
+		return
 	}
 
 	app.writeJSONResponse(c, http.StatusOK, Basket{
@@ -131,3 +465,22 @@ func (app *Application) Apply(c *gin.Context) {
 		AppliedDiscount: basket.AppliedDiscount,
 	})
 }
+
+func (app *Application) writeApplyError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidCode, service.ErrMalformedCode, service.ErrInvalidBasketValue, service.ErrMinBasketValue,
+		service.ErrNotFound, service.ErrInvalidToken, service.ErrTokenExpired, service.ErrTokensDisabled,
+		service.ErrInvalidTimeZone, service.ErrCustomerRequired, service.ErrInvalidBasketLine, service.ErrConditionsNotMet,
+		service.ErrExpressionNotMet, service.ErrDiscountNotApplicable:
+		app.writeJSONError(c, http.StatusBadRequest, err)
+	case service.ErrNotAssigned, service.ErrNotActivated, service.ErrOutsideSchedule, service.ErrChannelNotAllowed,
+		service.ErrStoreNotAllowed, service.ErrRegionNotAllowed, service.ErrUsageLimitReached:
+		app.writeJSONError(c, http.StatusForbidden, err)
+	case service.ErrExpressionFailed:
+		app.writeJSONError(c, http.StatusUnprocessableEntity, err)
+	case service.ErrRedemptionsDisabled, service.ErrExpressionsDisabled:
+		app.writeJSONError(c, http.StatusNotImplemented, err)
+	default:
+		app.writeJSONError(c, http.StatusInternalServerError, err)
+	}
+}
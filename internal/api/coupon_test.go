@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -55,7 +56,7 @@ func TestCreate(t *testing.T) {
 			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
 				srv.On("CreateCoupon",
 					mock.MatchedBy(func(_ context.Context) bool { return true }),
-					args.Discount, args.Code, args.MinBasketValue).
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue}).
 					Return(nil).
 					Once()
 
@@ -77,7 +78,7 @@ func TestCreate(t *testing.T) {
 			},
 			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
 				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
-					args.Discount, args.Code, args.MinBasketValue).
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue}).
 					Return(service.ErrInvalidDiscount).
 					Once()
 			},
@@ -92,12 +93,170 @@ func TestCreate(t *testing.T) {
 			},
 			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
 				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
-					args.Discount, args.Code, args.MinBasketValue).
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue}).
 					Return(service.ErrInvalidMinBasketValue).
 					Once()
 			},
 			want: http.StatusBadRequest,
 		},
+		{
+			name: "Existing coupon code",
+			body: &api.CreateCouponReq{
+				Code:           "test",
+				Discount:       10,
+				MinBasketValue: 20,
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue}).
+					Return(service.ErrCouponExists).
+					Once()
+			},
+			want: http.StatusConflict,
+		},
+		{
+			name: "Coupon with schedule",
+			body: &api.CreateCouponReq{
+				Code:           "happy",
+				Discount:       10,
+				MinBasketValue: 20,
+				Schedule: &api.Schedule{
+					Weekdays: []string{"fri", "Sat"},
+					Windows:  []api.TimeWindow{{Start: "22:00", End: "02:00"}, {Start: "17:00", End: "24:00"}},
+				},
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue,
+						Schedule: domain.Schedule{
+							Weekdays: []time.Weekday{time.Friday, time.Saturday},
+							Windows:  []domain.TimeWindow{{Start: 22 * 60, End: 2 * 60}, {Start: 17 * 60, End: 24 * 60}},
+						}}).
+					Return(nil).
+					Once()
+			},
+			want: http.StatusCreated,
+		},
+		{
+			name: "Coupon with eligibility",
+			body: &api.CreateCouponReq{
+				Code:           "online",
+				Discount:       10,
+				MinBasketValue: 20,
+				Channels:       &api.Restriction{Allow: []string{"online"}},
+				Regions:        &api.Restriction{Deny: []string{"AT"}},
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue,
+						Eligibility: domain.Eligibility{
+							Channels: domain.Restriction{Allow: []string{"online"}},
+							Regions:  domain.Restriction{Deny: []string{"AT"}},
+						}}).
+					Return(nil).
+					Once()
+			},
+			want: http.StatusCreated,
+		},
+		{
+			name: "Coupon with conditions",
+			body: &api.CreateCouponReq{
+				Code:           "bundle",
+				Discount:       10,
+				MinBasketValue: 20,
+				Conditions: []api.Condition{
+					{Type: "minQuantity", Category: "drinks", Min: 3},
+					{Type: "any", Conditions: []api.Condition{{Type: "containsSku", SKU: "tea-20"}}},
+				},
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue,
+						Conditions: []domain.Condition{
+							{Type: domain.ConditionMinQuantity, Category: "drinks", Min: 3},
+							{Type: domain.ConditionAny, Conditions: []domain.Condition{
+								{Type: domain.ConditionContainsSKU, SKU: "tea-20"},
+							}},
+						}}).
+					Return(service.ErrInvalidCondition).
+					Once()
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid expression",
+			body: &api.CreateCouponReq{
+				Code:           "gold",
+				Discount:       10,
+				MinBasketValue: 20,
+				Expression:     `customer.level == "gold"`,
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue,
+						Expression: args.Expression}).
+					Return(fmt.Errorf("%w: undefined field 'level'", service.ErrInvalidExpression)).
+					Once()
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Coupon with discount strategy",
+			body: &api.CreateCouponReq{
+				Code:           "percent",
+				Discount:       20,
+				MinBasketValue: 20,
+				Strategy:       "percent",
+				Params:         json.RawMessage(`{"max":500}`),
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue,
+						Strategy: "percent", Params: json.RawMessage(`{"max":500}`)}).
+					Return(nil).
+					Once()
+			},
+			want: http.StatusCreated,
+		},
+		{
+			name: "Unknown discount strategy",
+			body: &api.CreateCouponReq{
+				Code:           "bogo",
+				Discount:       20,
+				MinBasketValue: 20,
+				Strategy:       "bogo",
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
+				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue,
+						Strategy: "bogo"}).
+					Return(service.ErrInvalidStrategy).
+					Once()
+			},
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown weekday",
+			body: &api.CreateCouponReq{
+				Code:           "happy",
+				Discount:       10,
+				MinBasketValue: 20,
+				Schedule:       &api.Schedule{Weekdays: []string{"someday"}},
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {},
+			want:      http.StatusBadRequest,
+		},
+		{
+			name: "Malformed time",
+			body: &api.CreateCouponReq{
+				Code:           "happy",
+				Discount:       10,
+				MinBasketValue: 20,
+				Schedule:       &api.Schedule{Windows: []api.TimeWindow{{Start: "7:00", End: "24:30"}}},
+			},
+			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {},
+			want:      http.StatusBadRequest,
+		},
 		{
 			name: "Internal server error",
 			body: &api.CreateCouponReq{
@@ -107,7 +266,7 @@ func TestCreate(t *testing.T) {
 			},
 			setupMock: func(srv *mocks.Service, args *api.CreateCouponReq) {
 				srv.On("CreateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
-					args.Discount, args.Code, args.MinBasketValue).
+					domain.Coupon{Code: args.Code, Discount: args.Discount, MinBasketValue: args.MinBasketValue}).
 					Return(errors.New("error")).
 					Once()
 			},
@@ -200,6 +359,35 @@ func TestGet(t *testing.T) {
 			wantStatusCode: http.StatusNotFound,
 			want:           nil,
 		},
+		{
+			name:  "Duplicated codes",
+			codes: []string{"test", "test2", "test"},
+			setupMock: func(srv *mocks.Service, codes []string) {
+				srv.On("GetCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), []string{"test", "test2"}).
+					Return([]domain.Coupon{{ID: "id1", Code: "test", Discount: 10}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           []coupon{{Code: "test", Discount: 10}},
+		},
+		{
+			name:           "Too many codes",
+			codes:          distinctCodes(101),
+			setupMock:      func(srv *mocks.Service, codes []string) {},
+			wantStatusCode: http.StatusBadRequest,
+			want:           nil,
+		},
+		{
+			name:  "As many codes as allowed",
+			codes: distinctCodes(100),
+			setupMock: func(srv *mocks.Service, codes []string) {
+				srv.On("GetCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), codes).
+					Return([]domain.Coupon{{ID: "id1", Code: "code0", Discount: 10}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           []coupon{{Code: "code0", Discount: 10}},
+		},
 		{
 			name:           "Empty codes",
 			codes:          []string{},
@@ -207,6 +395,17 @@ func TestGet(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			want:           nil,
 		},
+		{
+			name:  "Malformed code",
+			codes: []string{"ABCD9"},
+			setupMock: func(srv *mocks.Service, codes []string) {
+				srv.On("GetCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), codes).
+					Return(nil, service.ErrMalformedCode).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+			want:           nil,
+		},
 		{
 			name:  "Unknown error",
 			codes: []string{"test", "test2"},
@@ -218,6 +417,17 @@ func TestGet(t *testing.T) {
 			wantStatusCode: http.StatusInternalServerError,
 			want:           nil,
 		},
+		{
+			name:  "Storage unavailable",
+			codes: []string{"test"},
+			setupMock: func(srv *mocks.Service, codes []string) {
+				srv.On("GetCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), codes).
+					Return(nil, fmt.Errorf("%w: connection refused", service.ErrUnavailable)).
+					Once()
+			},
+			wantStatusCode: http.StatusServiceUnavailable,
+			want:           nil,
+		},
 	}
 
 	for _, tc := range tests {
@@ -246,6 +456,14 @@ func TestGet(t *testing.T) {
 	}
 }
 
+func distinctCodes(n int) []string {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		codes = append(codes, fmt.Sprintf("code%d", i))
+	}
+	return codes
+}
+
 func TestApply(t *testing.T) {
 	if os.Getenv("LONG") != "" {
 		t.Skip("Skipping TestApply in long mode.")
@@ -290,6 +508,75 @@ func TestApply(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			want:           api.Basket{},
 		},
+		{
+			name: "Outside coupon schedule",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "happy", Origin: api.Origin{TimeZone: "Europe/Berlin"}},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value, TimeZone: "Europe/Berlin"}, code).
+					Return(nil, service.ErrOutsideSchedule).
+					Once()
+			},
+			wantStatusCode: http.StatusForbidden,
+			want:           api.Basket{},
+		},
+		{
+			name: "Channel not allowed",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "online", Origin: api.Origin{Channel: "store", StoreID: "s1", Region: "DE"}},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value, Channel: "store", StoreID: "s1", Region: "DE"}, code).
+					Return(nil, service.ErrChannelNotAllowed).
+					Once()
+			},
+			wantStatusCode: http.StatusForbidden,
+			want:           api.Basket{},
+		},
+		{
+			name: "Basket lines not meeting conditions",
+			body: api.ApplyReq{
+				Basket: api.Basket{Value: 100, Lines: []api.Line{
+					{SKU: "water-1l", Category: "drinks", Quantity: 2, UnitPrice: 10},
+					{SKU: "pfand-025", Quantity: 2, UnitPrice: 25, Deposit: true},
+				}},
+				Code: "bundle",
+			},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value, Lines: []domain.Line{
+						{SKU: "water-1l", Category: "drinks", Quantity: 2, UnitPrice: 10},
+						{SKU: "pfand-025", Quantity: 2, UnitPrice: 25, Deposit: true},
+					}}, code).
+					Return(nil, service.ErrConditionsNotMet).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+			want:           api.Basket{},
+		},
+		{
+			name: "Failing coupon expression",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "gold", Customer: &api.Customer{ID: "c1", Tier: "gold"}},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value, Customer: domain.Customer{ID: "c1", Tier: "gold"}}, code).
+					Return(nil, service.ErrExpressionFailed).
+					Once()
+			},
+			wantStatusCode: http.StatusUnprocessableEntity,
+			want:           api.Basket{},
+		},
+		{
+			name: "Unknown time zone",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "happy", Origin: api.Origin{TimeZone: "Europe/Atlantis"}},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value, TimeZone: "Europe/Atlantis"}, code).
+					Return(nil, service.ErrInvalidTimeZone).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+			want:           api.Basket{},
+		},
 		{
 			name: "Negative basket value",
 			body: api.ApplyReq{Basket: api.Basket{Value: -100}, Code: "test"},
@@ -302,6 +589,30 @@ func TestApply(t *testing.T) {
 			wantStatusCode: http.StatusBadRequest,
 			want:           api.Basket{},
 		},
+		{
+			name: "Malformed code",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "ABCD9"},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value}, code).
+					Return(nil, service.ErrMalformedCode).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+			want:           api.Basket{},
+		},
+		{
+			name: "Coupon assigned to another customer",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "test"},
+			setupMock: func(srv *mocks.Service, value int, code string) {
+				srv.On("ApplyCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: value}, code).
+					Return(nil, service.ErrNotAssigned).
+					Once()
+			},
+			wantStatusCode: http.StatusForbidden,
+			want:           api.Basket{},
+		},
 		{
 			name: "Undefined error",
 			body: api.ApplyReq{Basket: api.Basket{Value: 5}, Code: "test"},
@@ -347,3 +658,296 @@ func TestApply(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetCoupon in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		code           string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		wantETag       string
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful retrieval",
+			code: "test",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test").
+					Return(&domain.Coupon{ID: "id1", Code: "test", Discount: 10, Version: 3}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantETag:       `"3"`,
+		},
+		{
+			name: "Coupon not found",
+			code: "test",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test").
+					Return(nil, service.ErrNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "Malformed code",
+			code: "ABCD9",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "ABCD9").
+					Return(nil, service.ErrMalformedCode).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/coupons/:code", app.GetCoupon)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/coupons/"+tc.code, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			assert.Equal(t, tc.wantETag, w.Header().Get("ETag"))
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.Coupon
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, 3, resp["data"].Version)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestUpdate in long mode.")
+	}
+
+	discount := 20
+	isPatch := func(patch domain.CouponPatch) bool {
+		return patch.Discount != nil && *patch.Discount == discount && patch.MinBasketValue == nil
+	}
+
+	type testCase struct {
+		name           string
+		ifMatch        string
+		body           string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		wantETag       string
+	}
+
+	tests := []testCase{
+		{
+			name:    "Successful update",
+			ifMatch: `"3"`,
+			body:    `{"discount":20}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("UpdateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 3,
+					mock.MatchedBy(isPatch)).
+					Return(&domain.Coupon{ID: "id1", Code: "test", Discount: 20, Version: 4}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantETag:       `"4"`,
+		},
+		{
+			name:    "Stale version",
+			ifMatch: `"2"`,
+			body:    `{"discount":20}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("UpdateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 2,
+					mock.MatchedBy(isPatch)).
+					Return(nil, service.ErrVersionMismatch).
+					Once()
+			},
+			wantStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "Missing If-Match",
+			body:           `{"discount":20}`,
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:           "Unquoted If-Match",
+			ifMatch:        "3",
+			body:           `{"discount":20}`,
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "Wildcard If-Match",
+			ifMatch:        "*",
+			body:           `{"discount":20}`,
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "Coupon not found",
+			ifMatch: `"3"`,
+			body:    `{"discount":20}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("UpdateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 3,
+					mock.Anything).
+					Return(nil, service.ErrNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:    "Invalid discount",
+			ifMatch: `"3"`,
+			body:    `{"discount":200}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("UpdateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 3,
+					mock.Anything).
+					Return(nil, service.ErrInvalidDiscount).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "Eligibility, limits, conditions and strategy",
+			ifMatch: `"3"`,
+			body: `{"stores":{"deny":["s1"]},"usageLimits":[],"conditions":[{"type":"minQuantity","min":2}],` +
+				`"strategy":"percent","params":{"percent":10}}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("UpdateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 3,
+					mock.MatchedBy(func(patch domain.CouponPatch) bool {
+						return patch.Discount == nil && patch.Channels == nil &&
+							patch.Stores != nil && assert.ObjectsAreEqual([]string{"s1"}, patch.Stores.Deny) &&
+							patch.UsageLimits != nil && len(*patch.UsageLimits) == 0 &&
+							patch.Conditions != nil && len(*patch.Conditions) == 1 && (*patch.Conditions)[0].Min == 2 &&
+							patch.Strategy != nil && *patch.Strategy == "percent" &&
+							patch.Params != nil && string(*patch.Params) == `{"percent":10}`
+					})).
+					Return(&domain.Coupon{ID: "id1", Code: "test", Version: 4}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			wantETag:       `"4"`,
+		},
+		{
+			name:           "Invalid schedule",
+			ifMatch:        `"3"`,
+			body:           `{"schedule":{"windows":[{"start":"25:00","end":"26:00"}]}}`,
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.PATCH("/v1/coupons/:code", app.Update)
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/coupons/test", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			assert.Equal(t, tc.wantETag, w.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDelete in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		ifMatch        string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+	}
+
+	tests := []testCase{
+		{
+			name:    "Successful deletion",
+			ifMatch: `"3"`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("DeleteCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 3).
+					Return(nil).
+					Once()
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:    "Stale version",
+			ifMatch: `"2"`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("DeleteCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 2).
+					Return(service.ErrVersionMismatch).
+					Once()
+			},
+			wantStatusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "Missing If-Match",
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:    "Coupon not found",
+			ifMatch: `"3"`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("DeleteCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), "test", 3).
+					Return(service.ErrNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.DELETE("/v1/coupons/:code", app.Delete)
+
+			req := httptest.NewRequest(http.MethodDelete, "/v1/coupons/test", nil)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+		})
+	}
+}
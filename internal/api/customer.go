@@ -0,0 +1,118 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type WalletEntry struct {
+	Coupon
+	Activated bool `json:"activated"`
+}
+
+// customerFromRequest reads the customer ID from the path and their
+// segments from the comma separated segments query parameter. Both are taken
+// as given: callers are trusted to have authenticated the customer.
+func customerFromRequest(c *gin.Context) domain.Customer {
+	customer := domain.Customer{
+		ID: c.Param("id"),
+	}
+	if rawSegments := c.Query("segments"); rawSegments != "" {
+		customer.Segments = strings.Split(rawSegments, ",")
+	}
+	return customer
+}
+
+func (app *Application) GetCustomerCoupons(c *gin.Context) {
+	customer := customerFromRequest(c)
+
+	coupons, err := app.service.GetCustomerCoupons(c.Request.Context(), customer)
+	if err != nil {
+		app.logger.Errorw("error occurred while getting customer coupons", "error", err)
+		switch err {
+		case service.ErrInvalidCustomer:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := make([]Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		resp = append(resp, newCoupon(coupon))
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
+
+func (app *Application) GetWallet(c *gin.Context) {
+	entries, err := app.service.GetWallet(c.Request.Context(), customerFromRequest(c))
+	if err != nil {
+		app.logger.Errorw("error occurred while getting wallet", "error", err)
+		switch err {
+		case service.ErrInvalidCustomer:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrWalletDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := make([]WalletEntry, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, WalletEntry{
+			Coupon:    newCoupon(entry.Coupon),
+			Activated: entry.Activated,
+		})
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
+
+func (app *Application) ActivateCoupon(c *gin.Context) {
+	err := app.service.ActivateCoupon(c.Request.Context(), customerFromRequest(c), c.Param("code"))
+	if err != nil {
+		app.logger.Errorw("error occurred while activating coupon", "error", err)
+		app.writeWalletError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (app *Application) DeactivateCoupon(c *gin.Context) {
+	err := app.service.DeactivateCoupon(c.Request.Context(), customerFromRequest(c), c.Param("code"))
+	if err != nil {
+		app.logger.Errorw("error occurred while deactivating coupon", "error", err)
+		app.writeWalletError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (app *Application) writeWalletError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidCustomer, service.ErrInvalidCode, service.ErrMalformedCode:
+		app.writeJSONError(c, http.StatusBadRequest, err)
+	case service.ErrNotFound:
+		app.writeJSONError(c, http.StatusNotFound, err)
+	case service.ErrNotAssigned:
+		app.writeJSONError(c, http.StatusForbidden, err)
+	case service.ErrWalletDisabled:
+		app.writeJSONError(c, http.StatusNotImplemented, err)
+	default:
+		app.writeJSONError(c, http.StatusInternalServerError, err)
+	}
+}
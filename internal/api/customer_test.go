@@ -0,0 +1,265 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestGetCustomerCoupons(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetCustomerCoupons in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		url            string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           []api.Coupon
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful retrieval",
+			url:  "/v1/customers/c1/coupons?segments=gold,vip",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetCustomerCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1", Segments: []string{"gold", "vip"}}).
+					Return([]domain.Coupon{
+						{ID: "id1", Code: "test1", Discount: 10, Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}},
+						{ID: "id2", Code: "test2", Discount: 20, Assignment: domain.Assignment{Segments: []string{"gold"}}},
+					}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want: []api.Coupon{
+				{Code: "test1", Discount: 10, CustomerIDs: []string{"c1"}},
+				{Code: "test2", Discount: 20, Segments: []string{"gold"}},
+			},
+		},
+		{
+			name: "No assigned coupons",
+			url:  "/v1/customers/c1/coupons",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetCustomerCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1"}).
+					Return([]domain.Coupon{}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           []api.Coupon{},
+		},
+		{
+			name: "Unknown error",
+			url:  "/v1/customers/c1/coupons",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetCustomerCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1"}).
+					Return(nil, errors.New("error test")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/customers/:id/coupons", app.GetCustomerCoupons)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string][]api.Coupon
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"], "expected %+v, got: %+v", tc.want, resp)
+			}
+		})
+	}
+}
+
+func TestGetWallet(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetWallet in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		url            string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           []api.WalletEntry
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful retrieval",
+			url:  "/v1/customers/c1/wallet",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetWallet", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "c1"}).
+					Return([]domain.WalletEntry{
+						{Coupon: domain.Coupon{Code: "test1", Discount: 10, RequiresActivation: true}, Activated: true},
+						{Coupon: domain.Coupon{Code: "test2", Discount: 20, RequiresActivation: true}},
+					}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want: []api.WalletEntry{
+				{Coupon: api.Coupon{Code: "test1", Discount: 10, RequiresActivation: true}, Activated: true},
+				{Coupon: api.Coupon{Code: "test2", Discount: 20, RequiresActivation: true}},
+			},
+		},
+		{
+			name: "Wallet not configured",
+			url:  "/v1/customers/c1/wallet",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetWallet", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "c1"}).
+					Return(nil, service.ErrWalletDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/customers/:id/wallet", app.GetWallet)
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string][]api.WalletEntry
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"], "expected %+v, got: %+v", tc.want, resp)
+			}
+		})
+	}
+}
+
+func TestActivateCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestActivateCoupon in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		method         string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+	}
+
+	tests := []testCase{
+		{
+			name:   "Successful activation",
+			method: http.MethodPut,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("ActivateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1", Segments: []string{"gold"}}, "test").
+					Return(nil).
+					Once()
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "Coupon assigned to another customer",
+			method: http.MethodPut,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("ActivateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1", Segments: []string{"gold"}}, "test").
+					Return(service.ErrNotAssigned).
+					Once()
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:   "Unknown coupon",
+			method: http.MethodPut,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("ActivateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1", Segments: []string{"gold"}}, "test").
+					Return(service.ErrNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:   "Successful deactivation",
+			method: http.MethodDelete,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("DeactivateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1", Segments: []string{"gold"}}, "test").
+					Return(nil).
+					Once()
+			},
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:   "Deactivation error",
+			method: http.MethodDelete,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("DeactivateCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Customer{ID: "c1", Segments: []string{"gold"}}, "test").
+					Return(errors.New("error test")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.PUT("/v1/customers/:id/wallet/:code", app.ActivateCoupon)
+			router.DELETE("/v1/customers/:id/wallet/:code", app.DeactivateCoupon)
+
+			req := httptest.NewRequest(tc.method, "/v1/customers/c1/wallet/test?segments=gold", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+		})
+	}
+}
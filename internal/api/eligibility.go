@@ -0,0 +1,23 @@
+package api
+
+import "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+
+type Restriction struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+func parseRestriction(restriction *Restriction) domain.Restriction {
+	if restriction == nil {
+		return domain.Restriction{}
+	}
+	return domain.Restriction(*restriction)
+}
+
+func newRestriction(restriction domain.Restriction) *Restriction {
+	if !restriction.Restricted() {
+		return nil
+	}
+	resp := Restriction(restriction)
+	return &resp
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errPreconditionRequired = errors.New("If-Match header required")
+	errInvalidIfMatch       = errors.New(`If-Match header must be a single entity tag such as "1"`)
+)
+
+// etag is the strong entity tag of a coupon version.
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// ifMatch returns the coupon version of the If-Match header. Only a single
+// strong entity tag is accepted, as writes are checked against one version.
+func ifMatch(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, errPreconditionRequired
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return 0, errInvalidIfMatch
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
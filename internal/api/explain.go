@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type RuleCheck struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type Explanation struct {
+	Code     string      `json:"code"`
+	Eligible bool        `json:"eligible"`
+	Discount int         `json:"discount"`
+	Checks   []RuleCheck `json:"checks"`
+}
+
+func (app *Application) Explain(c *gin.Context) {
+	var body ApplyReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	explanation, err := app.service.ExplainCoupon(c.Request.Context(), newBasket(body.Basket, body.Customer, body.Origin), body.Code)
+	if err != nil {
+		app.logger.Errorw("error occurred while explaining coupon", "error", err)
+		switch err {
+		case service.ErrInvalidCode, service.ErrMalformedCode, service.ErrInvalidBasketValue, service.ErrInvalidToken,
+			service.ErrTokenExpired, service.ErrTokensDisabled, service.ErrInvalidTimeZone,
+			service.ErrInvalidBasketLine:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrNotFound:
+			app.writeJSONError(c, http.StatusNotFound, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := Explanation{
+		Code:     explanation.Code,
+		Eligible: explanation.Eligible,
+		Discount: explanation.Discount,
+		Checks:   make([]RuleCheck, 0, len(explanation.Checks)),
+	}
+	for _, check := range explanation.Checks {
+		resp.Checks = append(resp.Checks, RuleCheck(check))
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
@@ -0,0 +1,112 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestExplain(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestExplain in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		body           api.ApplyReq
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.Explanation
+	}
+
+	tests := []testCase{
+		{
+			name: "Failing rules reported",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "online", Origin: api.Origin{Channel: "store", Region: "AT"}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("ExplainCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: 100, Channel: "store", Region: "AT"}, "online").
+					Return(&domain.Explanation{
+						Code: "online",
+						Checks: []domain.RuleCheck{
+							{Rule: "assignment", Passed: true},
+							{Rule: "channel", Reason: service.ErrChannelNotAllowed.Error()},
+							{Rule: "region", Reason: service.ErrRegionNotAllowed.Error()},
+						},
+					}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want: api.Explanation{
+				Code: "online",
+				Checks: []api.RuleCheck{
+					{Rule: "assignment", Passed: true},
+					{Rule: "channel", Reason: service.ErrChannelNotAllowed.Error()},
+					{Rule: "region", Reason: service.ErrRegionNotAllowed.Error()},
+				},
+			},
+		},
+		{
+			name: "Unknown coupon",
+			body: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "unknown"},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("ExplainCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: 100}, "unknown").
+					Return(nil, service.ErrNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "Missing code",
+			body:           api.ApplyReq{Basket: api.Basket{Value: 100}},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/coupons/explain", app.Explain)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/coupons/explain", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.Explanation
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
@@ -4,9 +4,15 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	domain "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	token "github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
 )
 
 // Service is an autogenerated mock type for the Service type
@@ -22,6 +28,54 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// ActivateCoupon provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) ActivateCoupon(_a0 context.Context, _a1 domain.Customer, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ActivateCoupon")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_ActivateCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ActivateCoupon'
+type Service_ActivateCoupon_Call struct {
+	*mock.Call
+}
+
+// ActivateCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+//   - _a2 string
+func (_e *Service_Expecter) ActivateCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_ActivateCoupon_Call {
+	return &Service_ActivateCoupon_Call{Call: _e.mock.On("ActivateCoupon", _a0, _a1, _a2)}
+}
+
+func (_c *Service_ActivateCoupon_Call) Run(run func(_a0 context.Context, _a1 domain.Customer, _a2 string)) *Service_ActivateCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_ActivateCoupon_Call) Return(_a0 error) *Service_ActivateCoupon_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_ActivateCoupon_Call) RunAndReturn(run func(context.Context, domain.Customer, string) error) *Service_ActivateCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ApplyCoupon provides a mock function with given fields: _a0, _a1, _a2
 func (_m *Service) ApplyCoupon(_a0 context.Context, _a1 domain.Basket, _a2 string) (*domain.Basket, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -82,17 +136,122 @@ func (_c *Service_ApplyCoupon_Call) RunAndReturn(run func(context.Context, domai
 	return _c
 }
 
-// CreateCoupon provides a mock function with given fields: _a0, _a1, _a2, _a3
-func (_m *Service) CreateCoupon(_a0 context.Context, _a1 int, _a2 string, _a3 int) error {
-	ret := _m.Called(_a0, _a1, _a2, _a3)
+// Backup provides a mock function with given fields: _a0, _a1
+func (_m *Service) Backup(_a0 context.Context, _a1 io.Writer) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Backup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_Backup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backup'
+type Service_Backup_Call struct {
+	*mock.Call
+}
+
+// Backup is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 io.Writer
+func (_e *Service_Expecter) Backup(_a0 interface{}, _a1 interface{}) *Service_Backup_Call {
+	return &Service_Backup_Call{Call: _e.mock.On("Backup", _a0, _a1)}
+}
+
+func (_c *Service_Backup_Call) Run(run func(_a0 context.Context, _a1 io.Writer)) *Service_Backup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(io.Writer))
+	})
+	return _c
+}
+
+func (_c *Service_Backup_Call) Return(_a0 error) *Service_Backup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_Backup_Call) RunAndReturn(run func(context.Context, io.Writer) error) *Service_Backup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CacheStats provides a mock function with given fields: _a0
+func (_m *Service) CacheStats(_a0 context.Context) (*domain.CacheStats, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CacheStats")
+	}
+
+	var r0 *domain.CacheStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.CacheStats, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.CacheStats); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CacheStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_CacheStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CacheStats'
+type Service_CacheStats_Call struct {
+	*mock.Call
+}
+
+// CacheStats is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *Service_Expecter) CacheStats(_a0 interface{}) *Service_CacheStats_Call {
+	return &Service_CacheStats_Call{Call: _e.mock.On("CacheStats", _a0)}
+}
+
+func (_c *Service_CacheStats_Call) Run(run func(_a0 context.Context)) *Service_CacheStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Service_CacheStats_Call) Return(_a0 *domain.CacheStats, _a1 error) *Service_CacheStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_CacheStats_Call) RunAndReturn(run func(context.Context) (*domain.CacheStats, error)) *Service_CacheStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCoupon provides a mock function with given fields: _a0, _a1
+func (_m *Service) CreateCoupon(_a0 context.Context, _a1 domain.Coupon) error {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateCoupon")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string, int) error); ok {
-		r0 = rf(_a0, _a1, _a2, _a3)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Coupon) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -107,16 +266,14 @@ type Service_CreateCoupon_Call struct {
 
 // CreateCoupon is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 int
-//   - _a2 string
-//   - _a3 int
-func (_e *Service_Expecter) CreateCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *Service_CreateCoupon_Call {
-	return &Service_CreateCoupon_Call{Call: _e.mock.On("CreateCoupon", _a0, _a1, _a2, _a3)}
+//   - _a1 domain.Coupon
+func (_e *Service_Expecter) CreateCoupon(_a0 interface{}, _a1 interface{}) *Service_CreateCoupon_Call {
+	return &Service_CreateCoupon_Call{Call: _e.mock.On("CreateCoupon", _a0, _a1)}
 }
 
-func (_c *Service_CreateCoupon_Call) Run(run func(_a0 context.Context, _a1 int, _a2 string, _a3 int)) *Service_CreateCoupon_Call {
+func (_c *Service_CreateCoupon_Call) Run(run func(_a0 context.Context, _a1 domain.Coupon)) *Service_CreateCoupon_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int), args[2].(string), args[3].(int))
+		run(args[0].(context.Context), args[1].(domain.Coupon))
 	})
 	return _c
 }
@@ -126,33 +283,33 @@ func (_c *Service_CreateCoupon_Call) Return(_a0 error) *Service_CreateCoupon_Cal
 	return _c
 }
 
-func (_c *Service_CreateCoupon_Call) RunAndReturn(run func(context.Context, int, string, int) error) *Service_CreateCoupon_Call {
+func (_c *Service_CreateCoupon_Call) RunAndReturn(run func(context.Context, domain.Coupon) error) *Service_CreateCoupon_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetCoupons provides a mock function with given fields: _a0, _a1
-func (_m *Service) GetCoupons(_a0 context.Context, _a1 []string) ([]domain.Coupon, error) {
+// CreatePromotion provides a mock function with given fields: _a0, _a1
+func (_m *Service) CreatePromotion(_a0 context.Context, _a1 domain.Promotion) (*domain.Promotion, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetCoupons")
+		panic("no return value specified for CreatePromotion")
 	}
 
-	var r0 []domain.Coupon
+	var r0 *domain.Promotion
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Coupon, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Promotion) (*domain.Promotion, error)); ok {
 		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Coupon); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Promotion) *domain.Promotion); ok {
 		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Coupon)
+			r0 = ret.Get(0).(*domain.Promotion)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Promotion) error); ok {
 		r1 = rf(_a0, _a1)
 	} else {
 		r1 = ret.Error(1)
@@ -161,31 +318,1310 @@ func (_m *Service) GetCoupons(_a0 context.Context, _a1 []string) ([]domain.Coupo
 	return r0, r1
 }
 
-// Service_GetCoupons_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCoupons'
-type Service_GetCoupons_Call struct {
+// Service_CreatePromotion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePromotion'
+type Service_CreatePromotion_Call struct {
 	*mock.Call
 }
 
-// GetCoupons is a helper method to define mock.On call
+// CreatePromotion is a helper method to define mock.On call
 //   - _a0 context.Context
-//   - _a1 []string
-func (_e *Service_Expecter) GetCoupons(_a0 interface{}, _a1 interface{}) *Service_GetCoupons_Call {
-	return &Service_GetCoupons_Call{Call: _e.mock.On("GetCoupons", _a0, _a1)}
+//   - _a1 domain.Promotion
+func (_e *Service_Expecter) CreatePromotion(_a0 interface{}, _a1 interface{}) *Service_CreatePromotion_Call {
+	return &Service_CreatePromotion_Call{Call: _e.mock.On("CreatePromotion", _a0, _a1)}
 }
 
-func (_c *Service_GetCoupons_Call) Run(run func(_a0 context.Context, _a1 []string)) *Service_GetCoupons_Call {
+func (_c *Service_CreatePromotion_Call) Run(run func(_a0 context.Context, _a1 domain.Promotion)) *Service_CreatePromotion_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]string))
+		run(args[0].(context.Context), args[1].(domain.Promotion))
 	})
 	return _c
 }
 
-func (_c *Service_GetCoupons_Call) Return(_a0 []domain.Coupon, _a1 error) *Service_GetCoupons_Call {
+func (_c *Service_CreatePromotion_Call) Return(_a0 *domain.Promotion, _a1 error) *Service_CreatePromotion_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Service_GetCoupons_Call) RunAndReturn(run func(context.Context, []string) ([]domain.Coupon, error)) *Service_GetCoupons_Call {
+func (_c *Service_CreatePromotion_Call) RunAndReturn(run func(context.Context, domain.Promotion) (*domain.Promotion, error)) *Service_CreatePromotion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateTrigger provides a mock function with given fields: _a0, _a1
+func (_m *Service) CreateTrigger(_a0 context.Context, _a1 domain.Trigger) (*domain.Trigger, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTrigger")
+	}
+
+	var r0 *domain.Trigger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Trigger) (*domain.Trigger, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Trigger) *domain.Trigger); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Trigger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Trigger) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_CreateTrigger_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTrigger'
+type Service_CreateTrigger_Call struct {
+	*mock.Call
+}
+
+// CreateTrigger is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Trigger
+func (_e *Service_Expecter) CreateTrigger(_a0 interface{}, _a1 interface{}) *Service_CreateTrigger_Call {
+	return &Service_CreateTrigger_Call{Call: _e.mock.On("CreateTrigger", _a0, _a1)}
+}
+
+func (_c *Service_CreateTrigger_Call) Run(run func(_a0 context.Context, _a1 domain.Trigger)) *Service_CreateTrigger_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Trigger))
+	})
+	return _c
+}
+
+func (_c *Service_CreateTrigger_Call) Return(_a0 *domain.Trigger, _a1 error) *Service_CreateTrigger_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_CreateTrigger_Call) RunAndReturn(run func(context.Context, domain.Trigger) (*domain.Trigger, error)) *Service_CreateTrigger_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeactivateCoupon provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) DeactivateCoupon(_a0 context.Context, _a1 domain.Customer, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivateCoupon")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_DeactivateCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeactivateCoupon'
+type Service_DeactivateCoupon_Call struct {
+	*mock.Call
+}
+
+// DeactivateCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+//   - _a2 string
+func (_e *Service_Expecter) DeactivateCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_DeactivateCoupon_Call {
+	return &Service_DeactivateCoupon_Call{Call: _e.mock.On("DeactivateCoupon", _a0, _a1, _a2)}
+}
+
+func (_c *Service_DeactivateCoupon_Call) Run(run func(_a0 context.Context, _a1 domain.Customer, _a2 string)) *Service_DeactivateCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_DeactivateCoupon_Call) Return(_a0 error) *Service_DeactivateCoupon_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_DeactivateCoupon_Call) RunAndReturn(run func(context.Context, domain.Customer, string) error) *Service_DeactivateCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCoupon provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) DeleteCoupon(_a0 context.Context, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCoupon")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_DeleteCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCoupon'
+type Service_DeleteCoupon_Call struct {
+	*mock.Call
+}
+
+// DeleteCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 int
+func (_e *Service_Expecter) DeleteCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_DeleteCoupon_Call {
+	return &Service_DeleteCoupon_Call{Call: _e.mock.On("DeleteCoupon", _a0, _a1, _a2)}
+}
+
+func (_c *Service_DeleteCoupon_Call) Run(run func(_a0 context.Context, _a1 string, _a2 int)) *Service_DeleteCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Service_DeleteCoupon_Call) Return(_a0 error) *Service_DeleteCoupon_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_DeleteCoupon_Call) RunAndReturn(run func(context.Context, string, int) error) *Service_DeleteCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExplainCoupon provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) ExplainCoupon(_a0 context.Context, _a1 domain.Basket, _a2 string) (*domain.Explanation, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExplainCoupon")
+	}
+
+	var r0 *domain.Explanation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Basket, string) (*domain.Explanation, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Basket, string) *domain.Explanation); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Explanation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Basket, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_ExplainCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExplainCoupon'
+type Service_ExplainCoupon_Call struct {
+	*mock.Call
+}
+
+// ExplainCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Basket
+//   - _a2 string
+func (_e *Service_Expecter) ExplainCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_ExplainCoupon_Call {
+	return &Service_ExplainCoupon_Call{Call: _e.mock.On("ExplainCoupon", _a0, _a1, _a2)}
+}
+
+func (_c *Service_ExplainCoupon_Call) Run(run func(_a0 context.Context, _a1 domain.Basket, _a2 string)) *Service_ExplainCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Basket), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_ExplainCoupon_Call) Return(_a0 *domain.Explanation, _a1 error) *Service_ExplainCoupon_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_ExplainCoupon_Call) RunAndReturn(run func(context.Context, domain.Basket, string) (*domain.Explanation, error)) *Service_ExplainCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GenerateCoupons provides a mock function with given fields: _a0, _a1
+func (_m *Service) GenerateCoupons(_a0 context.Context, _a1 domain.CodeBatch) (*domain.Job, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateCoupons")
+	}
+
+	var r0 *domain.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CodeBatch) (*domain.Job, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.CodeBatch) *domain.Job); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.CodeBatch) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GenerateCoupons_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateCoupons'
+type Service_GenerateCoupons_Call struct {
+	*mock.Call
+}
+
+// GenerateCoupons is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.CodeBatch
+func (_e *Service_Expecter) GenerateCoupons(_a0 interface{}, _a1 interface{}) *Service_GenerateCoupons_Call {
+	return &Service_GenerateCoupons_Call{Call: _e.mock.On("GenerateCoupons", _a0, _a1)}
+}
+
+func (_c *Service_GenerateCoupons_Call) Run(run func(_a0 context.Context, _a1 domain.CodeBatch)) *Service_GenerateCoupons_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.CodeBatch))
+	})
+	return _c
+}
+
+func (_c *Service_GenerateCoupons_Call) Return(_a0 *domain.Job, _a1 error) *Service_GenerateCoupons_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GenerateCoupons_Call) RunAndReturn(run func(context.Context, domain.CodeBatch) (*domain.Job, error)) *Service_GenerateCoupons_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCoupon provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetCoupon(_a0 context.Context, _a1 string) (*domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCoupon")
+	}
+
+	var r0 *domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Coupon, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Coupon); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCoupon'
+type Service_GetCoupon_Call struct {
+	*mock.Call
+}
+
+// GetCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *Service_Expecter) GetCoupon(_a0 interface{}, _a1 interface{}) *Service_GetCoupon_Call {
+	return &Service_GetCoupon_Call{Call: _e.mock.On("GetCoupon", _a0, _a1)}
+}
+
+func (_c *Service_GetCoupon_Call) Run(run func(_a0 context.Context, _a1 string)) *Service_GetCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_GetCoupon_Call) Return(_a0 *domain.Coupon, _a1 error) *Service_GetCoupon_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetCoupon_Call) RunAndReturn(run func(context.Context, string) (*domain.Coupon, error)) *Service_GetCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCoupons provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetCoupons(_a0 context.Context, _a1 []string) ([]domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCoupons")
+	}
+
+	var r0 []domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Coupon, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Coupon); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetCoupons_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCoupons'
+type Service_GetCoupons_Call struct {
+	*mock.Call
+}
+
+// GetCoupons is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 []string
+func (_e *Service_Expecter) GetCoupons(_a0 interface{}, _a1 interface{}) *Service_GetCoupons_Call {
+	return &Service_GetCoupons_Call{Call: _e.mock.On("GetCoupons", _a0, _a1)}
+}
+
+func (_c *Service_GetCoupons_Call) Run(run func(_a0 context.Context, _a1 []string)) *Service_GetCoupons_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *Service_GetCoupons_Call) Return(_a0 []domain.Coupon, _a1 error) *Service_GetCoupons_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetCoupons_Call) RunAndReturn(run func(context.Context, []string) ([]domain.Coupon, error)) *Service_GetCoupons_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCustomerCoupons provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetCustomerCoupons(_a0 context.Context, _a1 domain.Customer) ([]domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomerCoupons")
+	}
+
+	var r0 []domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) ([]domain.Coupon, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) []domain.Coupon); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Customer) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetCustomerCoupons_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCustomerCoupons'
+type Service_GetCustomerCoupons_Call struct {
+	*mock.Call
+}
+
+// GetCustomerCoupons is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+func (_e *Service_Expecter) GetCustomerCoupons(_a0 interface{}, _a1 interface{}) *Service_GetCustomerCoupons_Call {
+	return &Service_GetCustomerCoupons_Call{Call: _e.mock.On("GetCustomerCoupons", _a0, _a1)}
+}
+
+func (_c *Service_GetCustomerCoupons_Call) Run(run func(_a0 context.Context, _a1 domain.Customer)) *Service_GetCustomerCoupons_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer))
+	})
+	return _c
+}
+
+func (_c *Service_GetCustomerCoupons_Call) Return(_a0 []domain.Coupon, _a1 error) *Service_GetCustomerCoupons_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetCustomerCoupons_Call) RunAndReturn(run func(context.Context, domain.Customer) ([]domain.Coupon, error)) *Service_GetCustomerCoupons_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJob provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetJob(_a0 context.Context, _a1 string) (*domain.Job, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJob")
+	}
+
+	var r0 *domain.Job
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Job, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Job); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Job)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJob'
+type Service_GetJob_Call struct {
+	*mock.Call
+}
+
+// GetJob is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *Service_Expecter) GetJob(_a0 interface{}, _a1 interface{}) *Service_GetJob_Call {
+	return &Service_GetJob_Call{Call: _e.mock.On("GetJob", _a0, _a1)}
+}
+
+func (_c *Service_GetJob_Call) Run(run func(_a0 context.Context, _a1 string)) *Service_GetJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_GetJob_Call) Return(_a0 *domain.Job, _a1 error) *Service_GetJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetJob_Call) RunAndReturn(run func(context.Context, string) (*domain.Job, error)) *Service_GetJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJobCodes provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetJobCodes(_a0 context.Context, _a1 string) ([]string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobCodes")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetJobCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobCodes'
+type Service_GetJobCodes_Call struct {
+	*mock.Call
+}
+
+// GetJobCodes is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *Service_Expecter) GetJobCodes(_a0 interface{}, _a1 interface{}) *Service_GetJobCodes_Call {
+	return &Service_GetJobCodes_Call{Call: _e.mock.On("GetJobCodes", _a0, _a1)}
+}
+
+func (_c *Service_GetJobCodes_Call) Run(run func(_a0 context.Context, _a1 string)) *Service_GetJobCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_GetJobCodes_Call) Return(_a0 []string, _a1 error) *Service_GetJobCodes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetJobCodes_Call) RunAndReturn(run func(context.Context, string) ([]string, error)) *Service_GetJobCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPromotions provides a mock function with given fields: _a0
+func (_m *Service) GetPromotions(_a0 context.Context) ([]domain.Promotion, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromotions")
+	}
+
+	var r0 []domain.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Promotion, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Promotion); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetPromotions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPromotions'
+type Service_GetPromotions_Call struct {
+	*mock.Call
+}
+
+// GetPromotions is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *Service_Expecter) GetPromotions(_a0 interface{}) *Service_GetPromotions_Call {
+	return &Service_GetPromotions_Call{Call: _e.mock.On("GetPromotions", _a0)}
+}
+
+func (_c *Service_GetPromotions_Call) Run(run func(_a0 context.Context)) *Service_GetPromotions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Service_GetPromotions_Call) Return(_a0 []domain.Promotion, _a1 error) *Service_GetPromotions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetPromotions_Call) RunAndReturn(run func(context.Context) ([]domain.Promotion, error)) *Service_GetPromotions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReferralCode provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetReferralCode(_a0 context.Context, _a1 domain.Customer) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReferralCode")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Customer) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetReferralCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReferralCode'
+type Service_GetReferralCode_Call struct {
+	*mock.Call
+}
+
+// GetReferralCode is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+func (_e *Service_Expecter) GetReferralCode(_a0 interface{}, _a1 interface{}) *Service_GetReferralCode_Call {
+	return &Service_GetReferralCode_Call{Call: _e.mock.On("GetReferralCode", _a0, _a1)}
+}
+
+func (_c *Service_GetReferralCode_Call) Run(run func(_a0 context.Context, _a1 domain.Customer)) *Service_GetReferralCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer))
+	})
+	return _c
+}
+
+func (_c *Service_GetReferralCode_Call) Return(_a0 string, _a1 error) *Service_GetReferralCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetReferralCode_Call) RunAndReturn(run func(context.Context, domain.Customer) (string, error)) *Service_GetReferralCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetReferralStats provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetReferralStats(_a0 context.Context, _a1 domain.Customer) (*domain.ReferralStats, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReferralStats")
+	}
+
+	var r0 *domain.ReferralStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) (*domain.ReferralStats, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) *domain.ReferralStats); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ReferralStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Customer) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetReferralStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetReferralStats'
+type Service_GetReferralStats_Call struct {
+	*mock.Call
+}
+
+// GetReferralStats is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+func (_e *Service_Expecter) GetReferralStats(_a0 interface{}, _a1 interface{}) *Service_GetReferralStats_Call {
+	return &Service_GetReferralStats_Call{Call: _e.mock.On("GetReferralStats", _a0, _a1)}
+}
+
+func (_c *Service_GetReferralStats_Call) Run(run func(_a0 context.Context, _a1 domain.Customer)) *Service_GetReferralStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer))
+	})
+	return _c
+}
+
+func (_c *Service_GetReferralStats_Call) Return(_a0 *domain.ReferralStats, _a1 error) *Service_GetReferralStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetReferralStats_Call) RunAndReturn(run func(context.Context, domain.Customer) (*domain.ReferralStats, error)) *Service_GetReferralStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTriggers provides a mock function with given fields: _a0
+func (_m *Service) GetTriggers(_a0 context.Context) ([]domain.Trigger, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTriggers")
+	}
+
+	var r0 []domain.Trigger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Trigger, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Trigger); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Trigger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetTriggers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTriggers'
+type Service_GetTriggers_Call struct {
+	*mock.Call
+}
+
+// GetTriggers is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *Service_Expecter) GetTriggers(_a0 interface{}) *Service_GetTriggers_Call {
+	return &Service_GetTriggers_Call{Call: _e.mock.On("GetTriggers", _a0)}
+}
+
+func (_c *Service_GetTriggers_Call) Run(run func(_a0 context.Context)) *Service_GetTriggers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Service_GetTriggers_Call) Return(_a0 []domain.Trigger, _a1 error) *Service_GetTriggers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetTriggers_Call) RunAndReturn(run func(context.Context) ([]domain.Trigger, error)) *Service_GetTriggers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWallet provides a mock function with given fields: _a0, _a1
+func (_m *Service) GetWallet(_a0 context.Context, _a1 domain.Customer) ([]domain.WalletEntry, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWallet")
+	}
+
+	var r0 []domain.WalletEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) ([]domain.WalletEntry, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) []domain.WalletEntry); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WalletEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Customer) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetWallet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWallet'
+type Service_GetWallet_Call struct {
+	*mock.Call
+}
+
+// GetWallet is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+func (_e *Service_Expecter) GetWallet(_a0 interface{}, _a1 interface{}) *Service_GetWallet_Call {
+	return &Service_GetWallet_Call{Call: _e.mock.On("GetWallet", _a0, _a1)}
+}
+
+func (_c *Service_GetWallet_Call) Run(run func(_a0 context.Context, _a1 domain.Customer)) *Service_GetWallet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer))
+	})
+	return _c
+}
+
+func (_c *Service_GetWallet_Call) Return(_a0 []domain.WalletEntry, _a1 error) *Service_GetWallet_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetWallet_Call) RunAndReturn(run func(context.Context, domain.Customer) ([]domain.WalletEntry, error)) *Service_GetWallet_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HandleEvent provides a mock function with given fields: _a0, _a1
+func (_m *Service) HandleEvent(_a0 context.Context, _a1 domain.OrderEvent) ([]domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleEvent")
+	}
+
+	var r0 []domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderEvent) ([]domain.Coupon, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OrderEvent) []domain.Coupon); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.OrderEvent) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_HandleEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HandleEvent'
+type Service_HandleEvent_Call struct {
+	*mock.Call
+}
+
+// HandleEvent is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.OrderEvent
+func (_e *Service_Expecter) HandleEvent(_a0 interface{}, _a1 interface{}) *Service_HandleEvent_Call {
+	return &Service_HandleEvent_Call{Call: _e.mock.On("HandleEvent", _a0, _a1)}
+}
+
+func (_c *Service_HandleEvent_Call) Run(run func(_a0 context.Context, _a1 domain.OrderEvent)) *Service_HandleEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.OrderEvent))
+	})
+	return _c
+}
+
+func (_c *Service_HandleEvent_Call) Return(_a0 []domain.Coupon, _a1 error) *Service_HandleEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_HandleEvent_Call) RunAndReturn(run func(context.Context, domain.OrderEvent) ([]domain.Coupon, error)) *Service_HandleEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IssueToken provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Service) IssueToken(_a0 context.Context, _a1 int, _a2 int, _a3 time.Time) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) (string, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Time) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_IssueToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IssueToken'
+type Service_IssueToken_Call struct {
+	*mock.Call
+}
+
+// IssueToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 int
+//   - _a2 int
+//   - _a3 time.Time
+func (_e *Service_Expecter) IssueToken(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *Service_IssueToken_Call {
+	return &Service_IssueToken_Call{Call: _e.mock.On("IssueToken", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *Service_IssueToken_Call) Run(run func(_a0 context.Context, _a1 int, _a2 int, _a3 time.Time)) *Service_IssueToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(int), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *Service_IssueToken_Call) Return(_a0 string, _a1 error) *Service_IssueToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_IssueToken_Call) RunAndReturn(run func(context.Context, int, int, time.Time) (string, error)) *Service_IssueToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PriceBasket provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) PriceBasket(_a0 context.Context, _a1 domain.Basket, _a2 []string) (*domain.Pricing, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PriceBasket")
+	}
+
+	var r0 *domain.Pricing
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Basket, []string) (*domain.Pricing, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Basket, []string) *domain.Pricing); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Pricing)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Basket, []string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_PriceBasket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PriceBasket'
+type Service_PriceBasket_Call struct {
+	*mock.Call
+}
+
+// PriceBasket is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Basket
+//   - _a2 []string
+func (_e *Service_Expecter) PriceBasket(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_PriceBasket_Call {
+	return &Service_PriceBasket_Call{Call: _e.mock.On("PriceBasket", _a0, _a1, _a2)}
+}
+
+func (_c *Service_PriceBasket_Call) Run(run func(_a0 context.Context, _a1 domain.Basket, _a2 []string)) *Service_PriceBasket_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Basket), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *Service_PriceBasket_Call) Return(_a0 *domain.Pricing, _a1 error) *Service_PriceBasket_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_PriceBasket_Call) RunAndReturn(run func(context.Context, domain.Basket, []string) (*domain.Pricing, error)) *Service_PriceBasket_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeemCoupon provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Service) RedeemCoupon(_a0 context.Context, _a1 domain.Basket, _a2 string, _a3 string) (*domain.Basket, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemCoupon")
+	}
+
+	var r0 *domain.Basket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Basket, string, string) (*domain.Basket, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Basket, string, string) *domain.Basket); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Basket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Basket, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_RedeemCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeemCoupon'
+type Service_RedeemCoupon_Call struct {
+	*mock.Call
+}
+
+// RedeemCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Basket
+//   - _a2 string
+//   - _a3 string
+func (_e *Service_Expecter) RedeemCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *Service_RedeemCoupon_Call {
+	return &Service_RedeemCoupon_Call{Call: _e.mock.On("RedeemCoupon", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *Service_RedeemCoupon_Call) Run(run func(_a0 context.Context, _a1 domain.Basket, _a2 string, _a3 string)) *Service_RedeemCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Basket), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *Service_RedeemCoupon_Call) Return(_a0 *domain.Basket, _a1 error) *Service_RedeemCoupon_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_RedeemCoupon_Call) RunAndReturn(run func(context.Context, domain.Basket, string, string) (*domain.Basket, error)) *Service_RedeemCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RedeemReferral provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Service) RedeemReferral(_a0 context.Context, _a1 domain.Customer, _a2 string) (*domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemReferral")
+	}
+
+	var r0 *domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer, string) (*domain.Coupon, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer, string) *domain.Coupon); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Customer, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_RedeemReferral_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeemReferral'
+type Service_RedeemReferral_Call struct {
+	*mock.Call
+}
+
+// RedeemReferral is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+//   - _a2 string
+func (_e *Service_Expecter) RedeemReferral(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Service_RedeemReferral_Call {
+	return &Service_RedeemReferral_Call{Call: _e.mock.On("RedeemReferral", _a0, _a1, _a2)}
+}
+
+func (_c *Service_RedeemReferral_Call) Run(run func(_a0 context.Context, _a1 domain.Customer, _a2 string)) *Service_RedeemReferral_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Service_RedeemReferral_Call) Return(_a0 *domain.Coupon, _a1 error) *Service_RedeemReferral_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_RedeemReferral_Call) RunAndReturn(run func(context.Context, domain.Customer, string) (*domain.Coupon, error)) *Service_RedeemReferral_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCoupon provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Service) UpdateCoupon(_a0 context.Context, _a1 string, _a2 int, _a3 domain.CouponPatch) (*domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCoupon")
+	}
+
+	var r0 *domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, domain.CouponPatch) (*domain.Coupon, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, domain.CouponPatch) *domain.Coupon); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, domain.CouponPatch) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_UpdateCoupon_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCoupon'
+type Service_UpdateCoupon_Call struct {
+	*mock.Call
+}
+
+// UpdateCoupon is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 int
+//   - _a3 domain.CouponPatch
+func (_e *Service_Expecter) UpdateCoupon(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *Service_UpdateCoupon_Call {
+	return &Service_UpdateCoupon_Call{Call: _e.mock.On("UpdateCoupon", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *Service_UpdateCoupon_Call) Run(run func(_a0 context.Context, _a1 string, _a2 int, _a3 domain.CouponPatch)) *Service_UpdateCoupon_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(domain.CouponPatch))
+	})
+	return _c
+}
+
+func (_c *Service_UpdateCoupon_Call) Return(_a0 *domain.Coupon, _a1 error) *Service_UpdateCoupon_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_UpdateCoupon_Call) RunAndReturn(run func(context.Context, string, int, domain.CouponPatch) (*domain.Coupon, error)) *Service_UpdateCoupon_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyToken provides a mock function with given fields: _a0, _a1
+func (_m *Service) VerifyToken(_a0 context.Context, _a1 string) (*token.Claims, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyToken")
+	}
+
+	var r0 *token.Claims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*token.Claims, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *token.Claims); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*token.Claims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_VerifyToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyToken'
+type Service_VerifyToken_Call struct {
+	*mock.Call
+}
+
+// VerifyToken is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *Service_Expecter) VerifyToken(_a0 interface{}, _a1 interface{}) *Service_VerifyToken_Call {
+	return &Service_VerifyToken_Call{Call: _e.mock.On("VerifyToken", _a0, _a1)}
+}
+
+func (_c *Service_VerifyToken_Call) Run(run func(_a0 context.Context, _a1 string)) *Service_VerifyToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_VerifyToken_Call) Return(_a0 *token.Claims, _a1 error) *Service_VerifyToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_VerifyToken_Call) RunAndReturn(run func(context.Context, string) (*token.Claims, error)) *Service_VerifyToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
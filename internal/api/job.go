@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type GenerateCouponsReq struct {
+	Count          int    `json:"count" binding:"required"`
+	Length         int    `json:"length" binding:"required"`
+	Alphabet       string `json:"alphabet"`
+	Prefix         string `json:"prefix"`
+	CheckChar      bool   `json:"checkChar"`
+	Discount       int    `json:"discount" binding:"required"`
+	MinBasketValue int    `json:"minBasketValue"`
+}
+
+type Job struct {
+	ID        string  `json:"id"`
+	Status    string  `json:"status"`
+	Total     int     `json:"total"`
+	Generated int     `json:"generated"`
+	Progress  float64 `json:"progress"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func newJob(job *domain.Job) Job {
+	var progress float64
+	if job.Total > 0 {
+		progress = float64(job.Done) / float64(job.Total)
+	}
+
+	return Job{
+		ID:        job.ID,
+		Status:    string(job.Status),
+		Total:     job.Total,
+		Generated: job.Done,
+		Progress:  progress,
+		Error:     job.Error,
+	}
+}
+
+func (app *Application) Generate(c *gin.Context) {
+	var body GenerateCouponsReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := app.service.GenerateCoupons(c.Request.Context(), domain.CodeBatch{
+		Count:          body.Count,
+		Length:         body.Length,
+		Alphabet:       body.Alphabet,
+		Prefix:         body.Prefix,
+		CheckChar:      body.CheckChar,
+		Discount:       body.Discount,
+		MinBasketValue: body.MinBasketValue,
+	})
+	if err != nil {
+		app.logger.Errorw("error occurred while starting coupon generation", "error", err)
+		switch err {
+		case service.ErrInvalidCount, service.ErrInvalidLength, service.ErrInvalidAlphabet, service.ErrInvalidPrefix,
+			service.ErrInvalidCodeFormat, service.ErrCodeSpaceTooSmall, service.ErrInvalidDiscount,
+			service.ErrInvalidMinBasketValue:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrTooManyJobs:
+			app.writeJSONError(c, http.StatusTooManyRequests, err)
+			return
+		case service.ErrJobsClosed:
+			app.writeJSONError(c, http.StatusServiceUnavailable, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.Header("Location", fmt.Sprintf("/v1/jobs/%s", job.ID))
+	app.writeJSONResponse(c, http.StatusAccepted, newJob(job))
+}
+
+func (app *Application) GetJob(c *gin.Context) {
+	job, err := app.service.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		app.logger.Errorw("error occurred while getting job", "error", err)
+		switch err {
+		case service.ErrJobNotFound:
+			app.writeJSONError(c, http.StatusNotFound, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, newJob(job))
+}
+
+func (app *Application) DownloadJob(c *gin.Context) {
+	id := c.Param("id")
+
+	codes, err := app.service.GetJobCodes(c.Request.Context(), id)
+	if err != nil {
+		app.logger.Errorw("error occurred while downloading job result", "error", err)
+		switch err {
+		case service.ErrJobNotFound:
+			app.writeJSONError(c, http.StatusNotFound, err)
+			return
+		case service.ErrJobNotCompleted:
+			app.writeJSONError(c, http.StatusConflict, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="coupons-%s.csv"`, id))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	// Batches run up to a million codes, so they are streamed rather than
+	// built in memory.
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"code"})
+	for _, code := range codes {
+		_ = w.Write([]string{code})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		app.logger.Errorw("error occurred while streaming job result", "error", err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,283 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/config"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestGenerate(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGenerate in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		body           *api.GenerateCouponsReq
+		setupMock      func(*mocks.Service, *api.GenerateCouponsReq)
+		wantStatusCode int
+		want           api.Job
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful job creation",
+			body: &api.GenerateCouponsReq{Count: 100, Length: 8, Prefix: "XMAS", CheckChar: true, Discount: 10},
+			setupMock: func(srv *mocks.Service, body *api.GenerateCouponsReq) {
+				srv.On("GenerateCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.CodeBatch{Count: 100, Length: 8, Prefix: "XMAS", CheckChar: true, Discount: 10}).
+					Return(&domain.Job{ID: "job1", Status: domain.JobPending, Total: 100}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusAccepted,
+			want:           api.Job{ID: "job1", Status: "pending", Total: 100},
+		},
+		{
+			name:           "Invalid body",
+			body:           nil,
+			setupMock:      func(srv *mocks.Service, body *api.GenerateCouponsReq) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid alphabet",
+			body: &api.GenerateCouponsReq{Count: 100, Length: 8, Alphabet: "O0", Discount: 10},
+			setupMock: func(srv *mocks.Service, body *api.GenerateCouponsReq) {
+				srv.On("GenerateCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidAlphabet).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Too many jobs",
+			body: &api.GenerateCouponsReq{Count: 100, Length: 8, Discount: 10},
+			setupMock: func(srv *mocks.Service, body *api.GenerateCouponsReq) {
+				srv.On("GenerateCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrTooManyJobs).
+					Once()
+			},
+			wantStatusCode: http.StatusTooManyRequests,
+		},
+		{
+			name: "Internal server error",
+			body: &api.GenerateCouponsReq{Count: 100, Length: 8, Discount: 10},
+			setupMock: func(srv *mocks.Service, body *api.GenerateCouponsReq) {
+				srv.On("GenerateCoupons", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, errors.New("error")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv, tc.body)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/coupons/generate", app.Generate)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/coupons/generate", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusAccepted {
+				var resp map[string]api.Job
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"], "expected %+v, got: %+v", tc.want, resp)
+				assert.Equal(t, "/v1/jobs/job1", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetJob in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		id             string
+		setupMock      func(*mocks.Service, string)
+		wantStatusCode int
+		want           api.Job
+	}
+
+	tests := []testCase{
+		{
+			name: "Running job",
+			id:   "job1",
+			setupMock: func(srv *mocks.Service, id string) {
+				srv.On("GetJob", mock.MatchedBy(func(_ context.Context) bool { return true }), id).
+					Return(&domain.Job{ID: id, Status: domain.JobRunning, Total: 200, Done: 50}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           api.Job{ID: "job1", Status: "running", Total: 200, Generated: 50, Progress: 0.25},
+		},
+		{
+			name: "Job not found",
+			id:   "unknown",
+			setupMock: func(srv *mocks.Service, id string) {
+				srv.On("GetJob", mock.MatchedBy(func(_ context.Context) bool { return true }), id).
+					Return(nil, service.ErrJobNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv, tc.id)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/jobs/:id", app.GetJob)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/jobs/"+tc.id, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.Job
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"], "expected %+v, got: %+v", tc.want, resp)
+			}
+		})
+	}
+}
+
+func TestDownloadJob(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDownloadJob in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		id             string
+		setupMock      func(*mocks.Service, string)
+		wantStatusCode int
+		want           string
+	}
+
+	tests := []testCase{
+		{
+			name: "Completed job",
+			id:   "job1",
+			setupMock: func(srv *mocks.Service, id string) {
+				srv.On("GetJobCodes", mock.MatchedBy(func(_ context.Context) bool { return true }), id).
+					Return([]string{"XMAS2345", "XMAS6789"}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           "code\nXMAS2345\nXMAS6789\n",
+		},
+		{
+			name: "Job still running",
+			id:   "job1",
+			setupMock: func(srv *mocks.Service, id string) {
+				srv.On("GetJobCodes", mock.MatchedBy(func(_ context.Context) bool { return true }), id).
+					Return(nil, service.ErrJobNotCompleted).
+					Once()
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "Job not found",
+			id:   "unknown",
+			setupMock: func(srv *mocks.Service, id string) {
+				srv.On("GetJobCodes", mock.MatchedBy(func(_ context.Context) bool { return true }), id).
+					Return(nil, service.ErrJobNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv, tc.id)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/jobs/:id/download", app.DownloadJob)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/jobs/"+tc.id+"/download", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				assert.Equal(t, tc.want, w.Body.String())
+				assert.Equal(t, `attachment; filename="coupons-job1.csv"`, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestDownloadJobNotLogged(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDownloadJobNotLogged in long mode.")
+	}
+
+	srv := mocks.NewService(t)
+	srv.On("GetJobCodes", mock.MatchedBy(func(_ context.Context) bool { return true }), "job1").
+		Return([]string{"XMAS2345"}, nil).
+		Once()
+
+	core, logs := observer.New(zap.InfoLevel)
+	app := api.New(config.Config{}, zap.New(core).Sugar(), srv)
+	router := app.Mount(gin.TestMode)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/jobs/job1/download", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 1, logs.Len())
+	for _, entry := range logs.All() {
+		assert.NotContains(t, fmt.Sprint(entry.Message, entry.ContextMap()), "XMAS2345", "expected codes not to be logged")
+	}
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type CreatePromotionReq struct {
+	Name           string          `json:"name" binding:"required"`
+	Discount       int             `json:"discount" binding:"required"`
+	MinBasketValue int             `json:"minBasketValue"`
+	CustomerIDs    []string        `json:"customerIds,omitempty"`
+	Segments       []string        `json:"segments,omitempty"`
+	Schedule       *Schedule       `json:"schedule,omitempty"`
+	Channels       *Restriction    `json:"channels,omitempty"`
+	Stores         *Restriction    `json:"stores,omitempty"`
+	Regions        *Restriction    `json:"regions,omitempty"`
+	Conditions     []Condition     `json:"conditions,omitempty"`
+	Expression     string          `json:"expression,omitempty"`
+	Strategy       string          `json:"strategy,omitempty"`
+	Params         json.RawMessage `json:"params,omitempty"`
+	Active         *bool           `json:"active,omitempty"`
+}
+
+type Promotion struct {
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Active         bool            `json:"active"`
+	Discount       int             `json:"discount"`
+	MinBasketValue int             `json:"minBasketValue"`
+	CustomerIDs    []string        `json:"customerIds,omitempty"`
+	Segments       []string        `json:"segments,omitempty"`
+	Schedule       *Schedule       `json:"schedule,omitempty"`
+	Channels       *Restriction    `json:"channels,omitempty"`
+	Stores         *Restriction    `json:"stores,omitempty"`
+	Regions        *Restriction    `json:"regions,omitempty"`
+	Conditions     []Condition     `json:"conditions,omitempty"`
+	Expression     string          `json:"expression,omitempty"`
+	Strategy       string          `json:"strategy,omitempty"`
+	Params         json.RawMessage `json:"params,omitempty"`
+}
+
+func newPromotion(promotion domain.Promotion) Promotion {
+	return Promotion{
+		ID:             promotion.ID,
+		Name:           promotion.Name,
+		Active:         promotion.Active,
+		Discount:       promotion.Discount,
+		MinBasketValue: promotion.MinBasketValue,
+		CustomerIDs:    promotion.Assignment.CustomerIDs,
+		Segments:       promotion.Assignment.Segments,
+		Schedule:       newSchedule(promotion.Schedule),
+		Channels:       newRestriction(promotion.Eligibility.Channels),
+		Stores:         newRestriction(promotion.Eligibility.Stores),
+		Regions:        newRestriction(promotion.Eligibility.Regions),
+		Conditions:     newConditions(promotion.Conditions),
+		Expression:     promotion.Expression,
+		Strategy:       promotion.Strategy,
+		Params:         promotion.Params,
+	}
+}
+
+func (app *Application) CreatePromotion(c *gin.Context) {
+	var body CreatePromotionReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	schedule, err := parseSchedule(body.Schedule)
+	if err != nil {
+		app.logger.Errorw("error occurred while parsing schedule", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	active := body.Active == nil || *body.Active
+
+	promotion, err := app.service.CreatePromotion(c.Request.Context(), domain.Promotion{
+		Coupon: domain.Coupon{
+			Discount:       body.Discount,
+			MinBasketValue: body.MinBasketValue,
+			Assignment: domain.Assignment{
+				CustomerIDs: body.CustomerIDs,
+				Segments:    body.Segments,
+			},
+			Schedule: schedule,
+			Eligibility: domain.Eligibility{
+				Channels: parseRestriction(body.Channels),
+				Stores:   parseRestriction(body.Stores),
+				Regions:  parseRestriction(body.Regions),
+			},
+			Conditions: parseConditions(body.Conditions),
+			Expression: body.Expression,
+			Strategy:   body.Strategy,
+			Params:     body.Params,
+		},
+		Name:   body.Name,
+		Active: active,
+	})
+	if err != nil {
+		app.logger.Errorw("error occurred while creating promotion", "error", err)
+		if errors.Is(err, service.ErrInvalidExpression) {
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		}
+		switch err {
+		case service.ErrInvalidPromotion, service.ErrInvalidDiscount, service.ErrInvalidMinBasketValue,
+			service.ErrInvalidAssignment, service.ErrInvalidSchedule, service.ErrInvalidEligibility,
+			service.ErrInvalidCondition, service.ErrInvalidStrategy, service.ErrInvalidDiscountParams:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrPromotionsDisabled, service.ErrExpressionsDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	app.writeJSONResponse(c, http.StatusCreated, newPromotion(*promotion))
+}
+
+func (app *Application) GetPromotions(c *gin.Context) {
+	promotions, err := app.service.GetPromotions(c.Request.Context())
+	if err != nil {
+		app.logger.Errorw("error occurred while getting promotions", "error", err)
+		switch err {
+		case service.ErrPromotionsDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := make([]Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		resp = append(resp, newPromotion(promotion))
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
+
+type PriceReq struct {
+	Basket   Basket    `json:"basket" binding:"required"`
+	Codes    []string  `json:"codes,omitempty"`
+	Customer *Customer `json:"customer,omitempty"`
+	Origin
+}
+
+type AppliedDiscount struct {
+	PromotionID string `json:"promotionId,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Discount    int    `json:"discount"`
+}
+
+type RejectedCode struct {
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+type Pricing struct {
+	Value           int               `json:"value"`
+	AppliedDiscount int               `json:"appliedDiscount"`
+	Discounts       []AppliedDiscount `json:"discounts"`
+	Rejected        []RejectedCode    `json:"rejected"`
+}
+
+func (app *Application) Price(c *gin.Context) {
+	var body PriceReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	pricing, err := app.service.PriceBasket(c.Request.Context(), newBasket(body.Basket, body.Customer, body.Origin), body.Codes)
+	if err != nil {
+		app.logger.Errorw("error occurred while pricing basket", "error", err)
+		switch err {
+		case service.ErrInvalidBasketValue, service.ErrInvalidTimeZone, service.ErrInvalidBasketLine:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := Pricing{
+		Value:           pricing.Value,
+		AppliedDiscount: pricing.AppliedDiscount,
+		Discounts:       make([]AppliedDiscount, 0, len(pricing.Discounts)),
+		Rejected:        make([]RejectedCode, 0, len(pricing.Rejected)),
+	}
+	for _, discount := range pricing.Discounts {
+		resp.Discounts = append(resp.Discounts, AppliedDiscount(discount))
+	}
+	for _, rejected := range pricing.Rejected {
+		resp.Rejected = append(resp.Rejected, RejectedCode{Code: rejected.Code, Error: rejected.Reason})
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
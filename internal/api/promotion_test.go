@@ -0,0 +1,370 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreatePromotion in long mode.")
+	}
+
+	inactive := false
+
+	type testCase struct {
+		name           string
+		body           *api.CreatePromotionReq
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.Promotion
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful creation defaults to active",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 10, MinBasketValue: 50},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.MatchedBy(func(p domain.Promotion) bool {
+					return p.Name == "summer" && p.Active && p.Discount == 10 && p.MinBasketValue == 50
+				})).
+					Return(&domain.Promotion{Name: "summer", Active: true, Coupon: domain.Coupon{ID: "p1", Discount: 10, MinBasketValue: 50}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want:           api.Promotion{ID: "p1", Name: "summer", Active: true, Discount: 10, MinBasketValue: 50},
+		},
+		{
+			name: "Inactive promotion",
+			body: &api.CreatePromotionReq{Name: "winter", Discount: 10, Active: &inactive},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.MatchedBy(func(p domain.Promotion) bool {
+					return p.Name == "winter" && !p.Active
+				})).
+					Return(&domain.Promotion{Name: "winter", Coupon: domain.Coupon{ID: "p2", Discount: 10}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want:           api.Promotion{ID: "p2", Name: "winter", Discount: 10},
+		},
+		{
+			name: "Promotion with schedule and conditions",
+			body: &api.CreatePromotionReq{
+				Name:       "happy hour",
+				Discount:   10,
+				Schedule:   &api.Schedule{Weekdays: []string{"fri"}, Windows: []api.TimeWindow{{Start: "17:00", End: "19:00"}}},
+				Conditions: []api.Condition{{Type: "minQuantity", Category: "drinks", Min: 2}},
+				Expression: `customer.tier == "gold"`,
+			},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.MatchedBy(func(p domain.Promotion) bool {
+					return len(p.Schedule.Weekdays) == 1 && p.Schedule.Weekdays[0] == time.Friday &&
+						len(p.Schedule.Windows) == 1 && p.Schedule.Windows[0] == domain.TimeWindow{Start: 17 * 60, End: 19 * 60} &&
+						len(p.Conditions) == 1 && p.Conditions[0].Category == "drinks" &&
+						p.Expression == `customer.tier == "gold"`
+				})).
+					Return(func(_ context.Context, p domain.Promotion) (*domain.Promotion, error) {
+						p.ID = "p3"
+						return &p, nil
+					}).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want: api.Promotion{
+				ID:         "p3",
+				Name:       "happy hour",
+				Active:     true,
+				Discount:   10,
+				Schedule:   &api.Schedule{Weekdays: []string{"fri"}, Windows: []api.TimeWindow{{Start: "17:00", End: "19:00"}}},
+				Conditions: []api.Condition{{Type: "minQuantity", Category: "drinks", Min: 2}},
+				Expression: `customer.tier == "gold"`,
+			},
+		},
+		{
+			name: "Promotion limited to online in some regions",
+			body: &api.CreatePromotionReq{
+				Name:     "online",
+				Discount: 10,
+				Channels: &api.Restriction{Allow: []string{"online"}},
+				Stores:   &api.Restriction{Deny: []string{"outlet-1"}},
+				Regions:  &api.Restriction{Allow: []string{"de", "at"}},
+			},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.MatchedBy(func(p domain.Promotion) bool {
+					return assert.ObjectsAreEqual(domain.Eligibility{
+						Channels: domain.Restriction{Allow: []string{"online"}},
+						Stores:   domain.Restriction{Deny: []string{"outlet-1"}},
+						Regions:  domain.Restriction{Allow: []string{"de", "at"}},
+					}, p.Eligibility)
+				})).
+					Return(func(_ context.Context, p domain.Promotion) (*domain.Promotion, error) {
+						p.ID = "p4"
+						return &p, nil
+					}).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want: api.Promotion{
+				ID:       "p4",
+				Name:     "online",
+				Active:   true,
+				Discount: 10,
+				Channels: &api.Restriction{Allow: []string{"online"}},
+				Stores:   &api.Restriction{Deny: []string{"outlet-1"}},
+				Regions:  &api.Restriction{Allow: []string{"de", "at"}},
+			},
+		},
+		{
+			name: "Invalid eligibility",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 10, Channels: &api.Restriction{Allow: []string{" "}}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidEligibility).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Promotion with strategy",
+			body: &api.CreatePromotionReq{Name: "capped", Discount: 20, Strategy: "percent", Params: json.RawMessage(`{"max":500}`)},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.MatchedBy(func(p domain.Promotion) bool {
+					return p.Strategy == "percent" && string(p.Params) == `{"max":500}`
+				})).
+					Return(func(_ context.Context, p domain.Promotion) (*domain.Promotion, error) {
+						p.ID = "p5"
+						return &p, nil
+					}).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want:           api.Promotion{ID: "p5", Name: "capped", Active: true, Discount: 20, Strategy: "percent", Params: json.RawMessage(`{"max":500}`)},
+		},
+		{
+			name: "Unknown strategy",
+			body: &api.CreatePromotionReq{Name: "bogo", Discount: 10, Strategy: "bogo"},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidStrategy).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid strategy params",
+			body: &api.CreatePromotionReq{Name: "capped", Discount: 20, Strategy: "percent", Params: json.RawMessage(`{"max":"500"}`)},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidDiscountParams).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "Malformed schedule",
+			body:           &api.CreatePromotionReq{Name: "summer", Discount: 10, Schedule: &api.Schedule{Weekdays: []string{"someday"}}},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid condition",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 10, Conditions: []api.Condition{{Type: "minValue"}}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidCondition).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid expression",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 10, Expression: "customer.level"},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, fmt.Errorf("%w: unknown field", service.ErrInvalidExpression)).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Expressions not configured",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 10, Expression: `customer.tier == "gold"`},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrExpressionsDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+		{
+			name:           "Missing name",
+			body:           &api.CreatePromotionReq{Discount: 10},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid discount",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 200},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidDiscount).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Promotions not configured",
+			body: &api.CreatePromotionReq{Name: "summer", Discount: 10},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreatePromotion", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrPromotionsDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/promotions", app.CreatePromotion)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/promotions", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusCreated {
+				var resp map[string]api.Promotion
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
+
+func TestPrice(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPrice in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		body           *api.PriceReq
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.Pricing
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful pricing",
+			body: &api.PriceReq{
+				Basket:   api.Basket{Value: 100},
+				Codes:    []string{"welcome", "unknown"},
+				Customer: &api.Customer{ID: "c1", Segments: []string{"gold"}},
+			},
+			setupMock: func(srv *mocks.Service) {
+				basket := domain.Basket{Value: 100, Customer: domain.Customer{ID: "c1", Segments: []string{"gold"}}}
+				srv.On("PriceBasket", mock.MatchedBy(func(_ context.Context) bool { return true }), basket, []string{"welcome", "unknown"}).
+					Return(&domain.Pricing{
+						Value:           75,
+						AppliedDiscount: 25,
+						Discounts: []domain.AppliedDiscount{
+							{PromotionID: "p1", Name: "gold", Discount: 10},
+							{Code: "welcome", Discount: 15},
+						},
+						Rejected: []domain.RejectedCode{{Code: "unknown", Reason: service.ErrNotFound.Error()}},
+					}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want: api.Pricing{
+				Value:           75,
+				AppliedDiscount: 25,
+				Discounts: []api.AppliedDiscount{
+					{PromotionID: "p1", Name: "gold", Discount: 10},
+					{Code: "welcome", Discount: 15},
+				},
+				Rejected: []api.RejectedCode{{Code: "unknown", Error: service.ErrNotFound.Error()}},
+			},
+		},
+		{
+			name:           "Missing basket",
+			body:           &api.PriceReq{Codes: []string{"welcome"}},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Internal server error",
+			body: &api.PriceReq{Basket: api.Basket{Value: 100}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("PriceBasket", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Basket{Value: 100}, []string(nil)).
+					Return(nil, errors.New("error")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/pricing", app.Price)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/pricing", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.Pricing
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type ReferralCode struct {
+	Code string `json:"code"`
+}
+
+type ReferralStats struct {
+	ReferrerID string `json:"referrerId"`
+	Code       string `json:"code"`
+	Referrals  int    `json:"referrals"`
+	Pending    int    `json:"pending"`
+	Rewarded   int    `json:"rewarded"`
+	Capped     int    `json:"capped"`
+	MaxRewards int    `json:"maxRewards,omitempty"`
+}
+
+type RedeemReferralReq struct {
+	Customer Customer `json:"customer" binding:"required"`
+}
+
+func (app *Application) GetReferralCode(c *gin.Context) {
+	code, err := app.service.GetReferralCode(c.Request.Context(), customerFromRequest(c))
+	if err != nil {
+		app.logger.Errorw("error occurred while getting referral code", "error", err)
+		app.writeReferralError(c, err)
+		return
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, ReferralCode{Code: code})
+}
+
+func (app *Application) GetReferralStats(c *gin.Context) {
+	stats, err := app.service.GetReferralStats(c.Request.Context(), customerFromRequest(c))
+	if err != nil {
+		app.logger.Errorw("error occurred while getting referral stats", "error", err)
+		app.writeReferralError(c, err)
+		return
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, ReferralStats(*stats))
+}
+
+func (app *Application) RedeemReferral(c *gin.Context) {
+	var body RedeemReferralReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	coupon, err := app.service.RedeemReferral(c.Request.Context(), domain.Customer{
+		ID:       body.Customer.ID,
+		Segments: body.Customer.Segments,
+	}, c.Param("code"))
+	if err != nil {
+		app.logger.Errorw("error occurred while redeeming referral code", "error", err)
+		app.writeReferralError(c, err)
+		return
+	}
+
+	app.writeJSONResponse(c, http.StatusCreated, newCoupon(*coupon))
+}
+
+func (app *Application) writeReferralError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrInvalidCustomer:
+		app.writeJSONError(c, http.StatusBadRequest, err)
+	case service.ErrReferralNotFound:
+		app.writeJSONError(c, http.StatusNotFound, err)
+	case service.ErrSelfReferral:
+		app.writeJSONError(c, http.StatusForbidden, err)
+	case service.ErrAlreadyReferred, service.ErrExistingCustomer:
+		app.writeJSONError(c, http.StatusConflict, err)
+	case service.ErrReferralsDisabled:
+		app.writeJSONError(c, http.StatusNotImplemented, err)
+	default:
+		app.writeJSONError(c, http.StatusInternalServerError, err)
+	}
+}
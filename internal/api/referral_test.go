@@ -0,0 +1,190 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestRedeemReferral(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedeemReferral in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.Coupon
+	}
+
+	tests := []testCase{
+		{
+			name: "Welcome coupon issued",
+			body: `{"customer":{"id":"bob"}}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemReferral", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "bob"}, "REFCODE").
+					Return(&domain.Coupon{Code: "WELCOME", Discount: 10, Assignment: domain.Assignment{CustomerIDs: []string{"bob"}}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want:           api.Coupon{Code: "WELCOME", Discount: 10, CustomerIDs: []string{"bob"}},
+		},
+		{
+			name: "Self referral",
+			body: `{"customer":{"id":"alice"}}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemReferral", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "alice"}, "REFCODE").
+					Return(nil, service.ErrSelfReferral).
+					Once()
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name: "Already referred",
+			body: `{"customer":{"id":"bob"}}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemReferral", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "bob"}, "REFCODE").
+					Return(nil, service.ErrAlreadyReferred).
+					Once()
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "Existing customer",
+			body: `{"customer":{"id":"bob"}}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemReferral", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "bob"}, "REFCODE").
+					Return(nil, service.ErrExistingCustomer).
+					Once()
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name: "Unknown referral code",
+			body: `{"customer":{"id":"bob"}}`,
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemReferral", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "bob"}, "REFCODE").
+					Return(nil, service.ErrReferralNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "Malformed body",
+			body:           `{"customer":`,
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/referrals/:code/redeem", app.RedeemReferral)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/referrals/REFCODE/redeem", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusCreated {
+				var resp map[string]api.Coupon
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
+
+func TestGetReferralStats(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetReferralStats in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.ReferralStats
+	}
+
+	tests := []testCase{
+		{
+			name: "Referrer stats",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetReferralStats", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "alice"}).
+					Return(&domain.ReferralStats{ReferrerID: "alice", Code: "REFCODE", Referrals: 3, Pending: 1, Rewarded: 1, Capped: 1, MaxRewards: 1}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           api.ReferralStats{ReferrerID: "alice", Code: "REFCODE", Referrals: 3, Pending: 1, Rewarded: 1, Capped: 1, MaxRewards: 1},
+		},
+		{
+			name: "No referral code",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetReferralStats", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "alice"}).
+					Return(nil, service.ErrReferralNotFound).
+					Once()
+			},
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name: "Referrals not configured",
+			setupMock: func(srv *mocks.Service) {
+				srv.On("GetReferralStats", mock.MatchedBy(func(_ context.Context) bool { return true }), domain.Customer{ID: "alice"}).
+					Return(nil, service.ErrReferralsDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/v1/customers/:id/referral/stats", app.GetReferralStats)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/customers/alice/referral/stats", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.ReferralStats
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Schedule lists weekdays as "mon" to "sun" and windows as "HH:MM" local
+// times. An end of "24:00" closes a window at midnight.
+type Schedule struct {
+	Weekdays []string     `json:"weekdays,omitempty"`
+	Windows  []TimeWindow `json:"windows,omitempty"`
+}
+
+type TimeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func parseSchedule(schedule *Schedule) (domain.Schedule, error) {
+	var parsed domain.Schedule
+	if schedule == nil {
+		return parsed, nil
+	}
+
+	for _, name := range schedule.Weekdays {
+		weekday, ok := parseWeekday(name)
+		if !ok {
+			return domain.Schedule{}, service.ErrInvalidSchedule
+		}
+		parsed.Weekdays = append(parsed.Weekdays, weekday)
+	}
+
+	for _, window := range schedule.Windows {
+		start, ok := parseClock(window.Start)
+		if !ok {
+			return domain.Schedule{}, service.ErrInvalidSchedule
+		}
+		end, ok := parseClock(window.End)
+		if !ok {
+			return domain.Schedule{}, service.ErrInvalidSchedule
+		}
+		parsed.Windows = append(parsed.Windows, domain.TimeWindow{Start: start, End: end})
+	}
+
+	return parsed, nil
+}
+
+func newSchedule(schedule domain.Schedule) *Schedule {
+	if !schedule.Restricted() {
+		return nil
+	}
+
+	resp := &Schedule{}
+	for _, weekday := range schedule.Weekdays {
+		resp.Weekdays = append(resp.Weekdays, weekdays[weekday])
+	}
+	for _, window := range schedule.Windows {
+		resp.Windows = append(resp.Windows, TimeWindow{
+			Start: formatClock(window.Start),
+			End:   formatClock(window.End),
+		})
+	}
+	return resp
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for i, weekday := range weekdays {
+		if name == weekday {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+func parseClock(value string) (int, bool) {
+	if len(value) != 5 || value[2] != ':' {
+		return 0, false
+	}
+
+	digits := value[:2] + value[3:]
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, false
+		}
+	}
+
+	hours := int(digits[0]-'0')*10 + int(digits[1]-'0')
+	minutes := int(digits[2]-'0')*10 + int(digits[3]-'0')
+	if minutes > 59 || hours*60+minutes > domain.MinutesPerDay {
+		return 0, false
+	}
+	return hours*60 + minutes, true
+}
+
+func formatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
@@ -2,12 +2,40 @@ package api
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
 )
 
 type Service interface {
-	CreateCoupon(context.Context, int, string, int) error
+	CreateCoupon(context.Context, domain.Coupon) error
+	GetCoupon(context.Context, string) (*domain.Coupon, error)
 	GetCoupons(context.Context, []string) ([]domain.Coupon, error)
+	UpdateCoupon(context.Context, string, int, domain.CouponPatch) (*domain.Coupon, error)
+	DeleteCoupon(context.Context, string, int) error
 	ApplyCoupon(context.Context, domain.Basket, string) (*domain.Basket, error)
+	RedeemCoupon(context.Context, domain.Basket, string, string) (*domain.Basket, error)
+	ExplainCoupon(context.Context, domain.Basket, string) (*domain.Explanation, error)
+	GetCustomerCoupons(context.Context, domain.Customer) ([]domain.Coupon, error)
+	GetWallet(context.Context, domain.Customer) ([]domain.WalletEntry, error)
+	ActivateCoupon(context.Context, domain.Customer, string) error
+	DeactivateCoupon(context.Context, domain.Customer, string) error
+	CreatePromotion(context.Context, domain.Promotion) (*domain.Promotion, error)
+	GetPromotions(context.Context) ([]domain.Promotion, error)
+	PriceBasket(context.Context, domain.Basket, []string) (*domain.Pricing, error)
+	CreateTrigger(context.Context, domain.Trigger) (*domain.Trigger, error)
+	GetTriggers(context.Context) ([]domain.Trigger, error)
+	HandleEvent(context.Context, domain.OrderEvent) ([]domain.Coupon, error)
+	GetReferralCode(context.Context, domain.Customer) (string, error)
+	RedeemReferral(context.Context, domain.Customer, string) (*domain.Coupon, error)
+	GetReferralStats(context.Context, domain.Customer) (*domain.ReferralStats, error)
+	GenerateCoupons(context.Context, domain.CodeBatch) (*domain.Job, error)
+	GetJob(context.Context, string) (*domain.Job, error)
+	GetJobCodes(context.Context, string) ([]string, error)
+	IssueToken(context.Context, int, int, time.Time) (string, error)
+	VerifyToken(context.Context, string) (*token.Claims, error)
+	Backup(context.Context, io.Writer) error
+	CacheStats(context.Context) (*domain.CacheStats, error)
 }
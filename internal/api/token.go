@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type IssueTokenReq struct {
+	Discount       int       `json:"discount" binding:"required"`
+	MinBasketValue int       `json:"minBasketValue"`
+	ExpiresAt      time.Time `json:"expiresAt" binding:"required"`
+}
+
+type VerifyTokenReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type Token struct {
+	Token          string    `json:"token,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	Discount       int       `json:"discount"`
+	MinBasketValue int       `json:"minBasketValue"`
+	IssuedAt       time.Time `json:"issuedAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+func (app *Application) IssueToken(c *gin.Context) {
+	var body IssueTokenReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tok, err := app.service.IssueToken(c.Request.Context(), body.Discount, body.MinBasketValue, body.ExpiresAt)
+	if err != nil {
+		app.logger.Errorw("error occurred while issuing token", "error", err)
+		switch err {
+		case service.ErrInvalidDiscount, service.ErrInvalidMinBasketValue, service.ErrInvalidExpiry:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrTokensDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	app.writeJSONResponse(c, http.StatusCreated, Token{Token: tok})
+}
+
+func (app *Application) VerifyToken(c *gin.Context) {
+	var body VerifyTokenReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	claims, err := app.service.VerifyToken(c.Request.Context(), body.Token)
+	if err != nil {
+		app.logger.Errorw("error occurred while verifying token", "error", err)
+		switch err {
+		case service.ErrInvalidToken, service.ErrTokenExpired:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrTokensDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, Token{
+		ID:             claims.ID,
+		Discount:       claims.Discount,
+		MinBasketValue: claims.MinBasketValue,
+		IssuedAt:       time.Unix(claims.IssuedAt, 0).UTC(),
+		ExpiresAt:      time.Unix(claims.ExpiresAt, 0).UTC(),
+	})
+}
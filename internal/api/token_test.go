@@ -0,0 +1,261 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/config"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
+)
+
+func TestIssueToken(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestIssueToken in long mode.")
+	}
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name           string
+		body           *api.IssueTokenReq
+		setupMock      func(*mocks.Service, *api.IssueTokenReq)
+		wantStatusCode int
+		want           api.Token
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful issuing",
+			body: &api.IssueTokenReq{Discount: 10, MinBasketValue: 50, ExpiresAt: expiresAt},
+			setupMock: func(srv *mocks.Service, body *api.IssueTokenReq) {
+				srv.On("IssueToken", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					body.Discount, body.MinBasketValue, body.ExpiresAt).
+					Return("ct1.k1.payload.signature", nil).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want:           api.Token{Token: "ct1.k1.payload.signature"},
+		},
+		{
+			name:           "Missing expiry",
+			body:           &api.IssueTokenReq{Discount: 10},
+			setupMock:      func(srv *mocks.Service, body *api.IssueTokenReq) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Tokens not configured",
+			body: &api.IssueTokenReq{Discount: 10, ExpiresAt: expiresAt},
+			setupMock: func(srv *mocks.Service, body *api.IssueTokenReq) {
+				srv.On("IssueToken", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					body.Discount, body.MinBasketValue, body.ExpiresAt).
+					Return("", service.ErrTokensDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+		{
+			name: "Internal server error",
+			body: &api.IssueTokenReq{Discount: 10, ExpiresAt: expiresAt},
+			setupMock: func(srv *mocks.Service, body *api.IssueTokenReq) {
+				srv.On("IssueToken", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					body.Discount, body.MinBasketValue, body.ExpiresAt).
+					Return("", errors.New("error")).
+					Once()
+			},
+			wantStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv, tc.body)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/tokens", app.IssueToken)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/tokens", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusCreated {
+				var resp map[string]api.Token
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want.Token, resp["data"].Token)
+			}
+		})
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestVerifyToken in long mode.")
+	}
+
+	issuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		name           string
+		body           *api.VerifyTokenReq
+		setupMock      func(*mocks.Service, *api.VerifyTokenReq)
+		wantStatusCode int
+		want           api.Token
+	}
+
+	tests := []testCase{
+		{
+			name: "Valid token",
+			body: &api.VerifyTokenReq{Token: "ct1.k1.payload.signature"},
+			setupMock: func(srv *mocks.Service, body *api.VerifyTokenReq) {
+				srv.On("VerifyToken", mock.MatchedBy(func(_ context.Context) bool { return true }), body.Token).
+					Return(&token.Claims{
+						ID:             "id1",
+						Discount:       10,
+						MinBasketValue: 50,
+						IssuedAt:       issuedAt.Unix(),
+						ExpiresAt:      expiresAt.Unix(),
+					}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want: api.Token{
+				ID:             "id1",
+				Discount:       10,
+				MinBasketValue: 50,
+				IssuedAt:       issuedAt,
+				ExpiresAt:      expiresAt,
+			},
+		},
+		{
+			name: "Expired token",
+			body: &api.VerifyTokenReq{Token: "ct1.k1.payload.signature"},
+			setupMock: func(srv *mocks.Service, body *api.VerifyTokenReq) {
+				srv.On("VerifyToken", mock.MatchedBy(func(_ context.Context) bool { return true }), body.Token).
+					Return(nil, service.ErrTokenExpired).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid token",
+			body: &api.VerifyTokenReq{Token: "ct1.k1.payload.forged"},
+			setupMock: func(srv *mocks.Service, body *api.VerifyTokenReq) {
+				srv.On("VerifyToken", mock.MatchedBy(func(_ context.Context) bool { return true }), body.Token).
+					Return(nil, service.ErrInvalidToken).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing token",
+			body:           &api.VerifyTokenReq{},
+			setupMock:      func(srv *mocks.Service, body *api.VerifyTokenReq) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv, tc.body)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/tokens/verify", app.VerifyToken)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/tokens/verify", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.Token
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"], "expected %+v, got: %+v", tc.want, resp)
+			}
+		})
+	}
+}
+
+func TestIssueTokenRequiresAdmin(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestIssueTokenRequiresAdmin in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		adminToken     string
+		authorization  string
+		wantStatusCode int
+	}
+
+	tests := []testCase{
+		{name: "No admin token configured", authorization: "Bearer ", wantStatusCode: http.StatusNotFound},
+		{name: "Missing token", adminToken: "secret", wantStatusCode: http.StatusUnauthorized},
+		{name: "Wrong token", adminToken: "secret", authorization: "Bearer wrong", wantStatusCode: http.StatusUnauthorized},
+		{name: "Admin token", adminToken: "secret", authorization: "Bearer secret", wantStatusCode: http.StatusCreated},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			if tc.wantStatusCode == http.StatusCreated {
+				srv.On("IssueToken", mock.MatchedBy(func(_ context.Context) bool { return true }), 10, 0, mock.Anything).
+					Return("ct1.k1.payload.signature", nil).
+					Once()
+			}
+			defer srv.AssertExpectations(t)
+
+			app := api.New(config.Config{AdminToken: tc.adminToken}, zap.NewNop().Sugar(), srv)
+			router := app.Mount(gin.TestMode)
+
+			body := `{"discount": 10, "expiresAt": "2030-01-01T00:00:00Z"}`
+			req := httptest.NewRequest(http.MethodPost, "/v1/tokens", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+		})
+	}
+}
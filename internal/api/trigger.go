@@ -0,0 +1,155 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+type CouponTemplate struct {
+	Prefix             string `json:"prefix,omitempty"`
+	Discount           int    `json:"discount" binding:"required"`
+	MinBasketValue     int    `json:"minBasketValue"`
+	RequiresActivation bool   `json:"requiresActivation,omitempty"`
+}
+
+type CreateTriggerReq struct {
+	Name          string         `json:"name" binding:"required"`
+	Event         string         `json:"event" binding:"required"`
+	MinOrderValue int            `json:"minOrderValue"`
+	Code          string         `json:"code,omitempty"`
+	Template      CouponTemplate `json:"template" binding:"required"`
+}
+
+type Trigger struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Event         string         `json:"event"`
+	MinOrderValue int            `json:"minOrderValue"`
+	Code          string         `json:"code,omitempty"`
+	Template      CouponTemplate `json:"template"`
+}
+
+func newTrigger(trigger domain.Trigger) Trigger {
+	return Trigger{
+		ID:            trigger.ID,
+		Name:          trigger.Name,
+		Event:         string(trigger.Event),
+		MinOrderValue: trigger.MinOrderValue,
+		Code:          trigger.Code,
+		Template:      CouponTemplate(trigger.Template),
+	}
+}
+
+func (app *Application) CreateTrigger(c *gin.Context) {
+	var body CreateTriggerReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	trigger, err := app.service.CreateTrigger(c.Request.Context(), domain.Trigger{
+		Name:          body.Name,
+		Event:         domain.TriggerEvent(body.Event),
+		MinOrderValue: body.MinOrderValue,
+		Code:          body.Code,
+		Template:      domain.CouponTemplate(body.Template),
+	})
+	if err != nil {
+		app.logger.Errorw("error occurred while creating trigger", "error", err)
+		switch err {
+		case service.ErrInvalidTrigger, service.ErrInvalidDiscount, service.ErrInvalidMinBasketValue,
+			service.ErrInvalidPrefix, service.ErrInvalidAlphabet, service.ErrInvalidCodeFormat:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrTriggersDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	app.writeJSONResponse(c, http.StatusCreated, newTrigger(*trigger))
+}
+
+func (app *Application) GetTriggers(c *gin.Context) {
+	triggers, err := app.service.GetTriggers(c.Request.Context())
+	if err != nil {
+		app.logger.Errorw("error occurred while getting triggers", "error", err)
+		switch err {
+		case service.ErrTriggersDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := make([]Trigger, 0, len(triggers))
+	for _, trigger := range triggers {
+		resp = append(resp, newTrigger(trigger))
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
+
+type EventReq struct {
+	Type     string   `json:"type" binding:"required"`
+	OrderID  string   `json:"orderId" binding:"required"`
+	Customer Customer `json:"customer" binding:"required"`
+	Value    int      `json:"value"`
+	Code     string   `json:"code,omitempty"`
+}
+
+func (app *Application) HandleEvent(c *gin.Context) {
+	var body EventReq
+
+	if err := c.ShouldBindBodyWithJSON(&body); err != nil {
+		app.logger.Errorw("error occurred while binding body", "error", err)
+		app.writeJSONError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	coupons, err := app.service.HandleEvent(c.Request.Context(), domain.OrderEvent{
+		Type:    domain.TriggerEvent(body.Type),
+		OrderID: body.OrderID,
+		Customer: domain.Customer{
+			ID:       body.Customer.ID,
+			Segments: body.Customer.Segments,
+		},
+		Value: body.Value,
+		Code:  body.Code,
+	})
+	if err != nil {
+		app.logger.Errorw("error occurred while handling event", "error", err)
+		switch err {
+		case service.ErrInvalidEvent:
+			app.writeJSONError(c, http.StatusBadRequest, err)
+			return
+		case service.ErrIssuancePending:
+			app.writeJSONError(c, http.StatusConflict, err)
+			return
+		case service.ErrTriggersDisabled:
+			app.writeJSONError(c, http.StatusNotImplemented, err)
+			return
+		default:
+			app.writeJSONError(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	resp := make([]Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		resp = append(resp, newCoupon(coupon))
+	}
+
+	app.writeJSONResponse(c, http.StatusOK, resp)
+}
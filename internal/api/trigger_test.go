@@ -0,0 +1,219 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestCreateTrigger(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateTrigger in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		body           *api.CreateTriggerReq
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.Trigger
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful creation",
+			body: &api.CreateTriggerReq{
+				Name:          "spend 50",
+				Event:         "order_completed",
+				MinOrderValue: 50,
+				Template:      api.CouponTemplate{Prefix: "NEXT", Discount: 5},
+			},
+			setupMock: func(srv *mocks.Service) {
+				trigger := domain.Trigger{
+					Name:          "spend 50",
+					Event:         domain.EventOrderCompleted,
+					MinOrderValue: 50,
+					Template:      domain.CouponTemplate{Prefix: "NEXT", Discount: 5},
+				}
+				created := trigger
+				created.ID = "t1"
+				srv.On("CreateTrigger", mock.MatchedBy(func(_ context.Context) bool { return true }), trigger).
+					Return(&created, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusCreated,
+			want: api.Trigger{
+				ID:            "t1",
+				Name:          "spend 50",
+				Event:         "order_completed",
+				MinOrderValue: 50,
+				Template:      api.CouponTemplate{Prefix: "NEXT", Discount: 5},
+			},
+		},
+		{
+			name: "Invalid trigger",
+			body: &api.CreateTriggerReq{Name: "spend 50", Event: "refund", Template: api.CouponTemplate{Discount: 5}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreateTrigger", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidTrigger).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "Missing event",
+			body:           &api.CreateTriggerReq{Name: "spend 50", Template: api.CouponTemplate{Discount: 5}},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Triggers not configured",
+			body: &api.CreateTriggerReq{Name: "spend 50", Event: "order_completed", Template: api.CouponTemplate{Discount: 5}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("CreateTrigger", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrTriggersDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/triggers", app.CreateTrigger)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/triggers", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusCreated {
+				var resp map[string]api.Trigger
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestHandleEvent in long mode.")
+	}
+
+	type testCase struct {
+		name           string
+		body           *api.EventReq
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           []api.Coupon
+	}
+
+	tests := []testCase{
+		{
+			name: "Issued coupons",
+			body: &api.EventReq{Type: "order_completed", OrderID: "o1", Customer: api.Customer{ID: "c1"}, Value: 60},
+			setupMock: func(srv *mocks.Service) {
+				event := domain.OrderEvent{
+					Type:     domain.EventOrderCompleted,
+					OrderID:  "o1",
+					Customer: domain.Customer{ID: "c1"},
+					Value:    60,
+				}
+				srv.On("HandleEvent", mock.MatchedBy(func(_ context.Context) bool { return true }), event).
+					Return([]domain.Coupon{{
+						Code:       "NEXT123456",
+						Discount:   5,
+						Assignment: domain.Assignment{CustomerIDs: []string{"c1"}},
+					}}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           []api.Coupon{{Code: "NEXT123456", Discount: 5, CustomerIDs: []string{"c1"}}},
+		},
+		{
+			name: "Invalid event",
+			body: &api.EventReq{Type: "refund", OrderID: "o1", Customer: api.Customer{ID: "c1"}},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("HandleEvent", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrInvalidEvent).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Replay during first delivery",
+			body: &api.EventReq{Type: "order_completed", OrderID: "o1", Customer: api.Customer{ID: "c1"}, Value: 60},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("HandleEvent", mock.MatchedBy(func(_ context.Context) bool { return true }), mock.Anything).
+					Return(nil, service.ErrIssuancePending).
+					Once()
+			},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "Missing order ID",
+			body:           &api.EventReq{Type: "order_completed", Customer: api.Customer{ID: "c1"}},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/events", app.HandleEvent)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/events", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string][]api.Coupon
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+
+// UsageLimit caps the redemptions per customer to Count per "day" or
+// "week". Rolling periods end at the time of redemption, calendar periods
+// start at local midnight, or on Monday for weeks.
+type UsageLimit struct {
+	Count   int    `json:"count"`
+	Period  string `json:"period"`
+	Rolling bool   `json:"rolling,omitempty"`
+}
+
+func parseUsageLimits(limits []UsageLimit) []domain.UsageLimit {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	parsed := make([]domain.UsageLimit, 0, len(limits))
+	for _, limit := range limits {
+		parsed = append(parsed, domain.UsageLimit{
+			Count:   limit.Count,
+			Period:  domain.UsagePeriod(limit.Period),
+			Rolling: limit.Rolling,
+		})
+	}
+	return parsed
+}
+
+func newUsageLimits(limits []domain.UsageLimit) []UsageLimit {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	resp := make([]UsageLimit, 0, len(limits))
+	for _, limit := range limits {
+		resp = append(resp, UsageLimit{
+			Count:   limit.Count,
+			Period:  string(limit.Period),
+			Rolling: limit.Rolling,
+		})
+	}
+	return resp
+}
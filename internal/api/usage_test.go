@@ -0,0 +1,129 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestRedeem(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedeem in long mode.")
+	}
+
+	customer := &api.Customer{ID: "c1"}
+	basket := domain.Basket{Value: 100, Customer: domain.Customer{ID: "c1"}}
+
+	type testCase struct {
+		name           string
+		body           api.RedeemReq
+		setupMock      func(*mocks.Service)
+		wantStatusCode int
+		want           api.Basket
+	}
+
+	tests := []testCase{
+		{
+			name: "Successful redemption",
+			body: api.RedeemReq{
+				ApplyReq: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "daily", Customer: customer},
+				OrderID:  "o1",
+			},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), basket, "daily", "o1").
+					Return(&domain.Basket{Value: 90, AppliedDiscount: 10}, nil).
+					Once()
+			},
+			wantStatusCode: http.StatusOK,
+			want:           api.Basket{Value: 90, AppliedDiscount: 10},
+		},
+		{
+			name: "Usage limit reached",
+			body: api.RedeemReq{
+				ApplyReq: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "daily", Customer: customer},
+			},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), basket, "daily", "").
+					Return(nil, service.ErrUsageLimitReached).
+					Once()
+			},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name: "Anonymous basket",
+			body: api.RedeemReq{
+				ApplyReq: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "daily"},
+			},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }),
+					domain.Basket{Value: 100}, "daily", "").
+					Return(nil, service.ErrCustomerRequired).
+					Once()
+			},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Redemptions not configured",
+			body: api.RedeemReq{
+				ApplyReq: api.ApplyReq{Basket: api.Basket{Value: 100}, Code: "daily", Customer: customer},
+			},
+			setupMock: func(srv *mocks.Service) {
+				srv.On("RedeemCoupon", mock.MatchedBy(func(_ context.Context) bool { return true }), basket, "daily", "").
+					Return(nil, service.ErrRedemptionsDisabled).
+					Once()
+			},
+			wantStatusCode: http.StatusNotImplemented,
+		},
+		{
+			name:           "Missing code",
+			body:           api.RedeemReq{ApplyReq: api.ApplyReq{Basket: api.Basket{Value: 100}}},
+			setupMock:      func(srv *mocks.Service) {},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := mocks.NewService(t)
+			tc.setupMock(srv)
+			defer srv.AssertExpectations(t)
+
+			app := newTestApplication(t, srv)
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.POST("/v1/coupons/redeem", app.Redeem)
+
+			var buff bytes.Buffer
+			err := json.NewEncoder(&buff).Encode(tc.body)
+			require.NoErrorf(t, err, "error encoding request %v", err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/coupons/redeem", strings.NewReader(buff.String()))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatusCode, w.Code, "expected status code %d, got: %d", tc.wantStatusCode, w.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var resp map[string]api.Basket
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp), "error decoding response body")
+				assert.Equal(t, tc.want, resp["data"])
+			}
+		})
+	}
+}
@@ -2,15 +2,88 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/expression"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
 )
 
 type Config struct {
-	Addr string
+	Addr            string
+	CodeCheckDigit  bool
+	CodeAlphabet    string
+	CodePolicy      couponcode.Policy
+	TokenKeys       string
+	TokenSigningKey string
+	// AdminToken is the bearer token of admin endpoints, which are not
+	// served without one.
+	AdminToken      string
+	ReferralProgram domain.ReferralProgram
+	// ExpressionCostLimit bounds the evaluation cost of coupon expressions.
+	ExpressionCostLimit int
+	// Repository selects where coupons are stored: "memory", "sqlite",
+	// "bolt" or "postgres".
+	Repository       string
+	SQLitePath       string
+	BoltPath         string
+	PostgresURL      string
+	PostgresMaxConns int
+	// MemoryDataDir makes the memory repository durable: it keeps a snapshot
+	// and a write-ahead log there when set.
+	MemoryDataDir      string
+	MemorySync         memory.SyncMode
+	MemorySyncInterval time.Duration
+	MemoryCompactAfter int
+	// VolatileState accepts losing the in-memory wallet, promotions,
+	// triggers, referrals and redemptions on restart while coupons are
+	// stored persistently.
+	VolatileState bool
+	// CacheSize enables caching lookups by code for up to that many codes in
+	// front of the repository.
+	CacheSize        int
+	CacheTTL         time.Duration
+	CacheNegativeTTL time.Duration
 }
 
 func New() Config {
 	return Config{
-		Addr: getString("ADDR", ":8080"),
+		Addr:           getString("ADDR", ":8080"),
+		CodeCheckDigit: getBool("CODE_CHECK_DIGIT", false),
+		CodeAlphabet:   getString("CODE_ALPHABET", couponcode.DefaultAlphabet),
+		CodePolicy: couponcode.Policy{
+			Trim:       getBool("CODE_TRIM", true),
+			FoldCase:   getBool("CODE_FOLD_CASE", false),
+			Separators: getString("CODE_SEPARATORS", ""),
+			Charset:    getString("CODE_CHARSET", ""),
+			MinLength:  getInt("CODE_MIN_LENGTH", 1),
+			MaxLength:  getInt("CODE_MAX_LENGTH", 64),
+		},
+		TokenKeys:       getString("TOKEN_KEYS", ""),
+		TokenSigningKey: getString("TOKEN_SIGNING_KEY", ""),
+		AdminToken:      getString("ADMIN_TOKEN", ""),
+		ReferralProgram: domain.ReferralProgram{
+			CodePrefix: getString("REFERRAL_CODE_PREFIX", "REF"),
+			Welcome:    domain.CouponTemplate{Discount: getInt("REFERRAL_WELCOME_DISCOUNT", 10)},
+			Reward:     domain.CouponTemplate{Discount: getInt("REFERRAL_REWARD_DISCOUNT", 10)},
+			MaxRewards: getInt("REFERRAL_MAX_REWARDS", 10),
+		},
+		ExpressionCostLimit: getInt("EXPRESSION_COST_LIMIT", expression.DefaultCostLimit),
+		Repository:          getString("REPOSITORY", "memory"),
+		SQLitePath:          getString("SQLITE_PATH", "coupons.db"),
+		BoltPath:            getString("BOLT_PATH", "coupons.bolt"),
+		PostgresURL:         getString("POSTGRES_URL", ""),
+		PostgresMaxConns:    getInt("POSTGRES_MAX_CONNS", 10),
+		MemoryDataDir:       getString("MEMORY_DATA_DIR", ""),
+		MemorySync:          memory.SyncMode(getString("MEMORY_SYNC", string(memory.SyncAlways))),
+		MemorySyncInterval:  getDuration("MEMORY_SYNC_INTERVAL", time.Second),
+		MemoryCompactAfter:  getInt("MEMORY_COMPACT_AFTER", 1000),
+		VolatileState:       getBool("VOLATILE_STATE", false),
+		CacheSize:           getInt("CACHE_SIZE", 0),
+		CacheTTL:            getDuration("CACHE_TTL", 30*time.Second),
+		CacheNegativeTTL:    getDuration("CACHE_NEGATIVE_TTL", 5*time.Second),
 	}
 }
 
@@ -20,3 +93,30 @@ func getString(key string, fallback string) string {
 	}
 	return fallback
 }
+
+func getBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,50 @@
+package couponcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultAlphabet contains upper-case letters and digits without the
+// characters that are easily confused when typed by hand (0/O, 1/I).
+const DefaultAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+const ambiguousChars = "0Oo1Il"
+
+var (
+	ErrInvalidAlphabet = errors.New("invalid alphabet")
+	ErrInvalidLength   = errors.New("invalid length")
+	ErrInvalidPrefix   = errors.New("invalid prefix")
+)
+
+func ValidateAlphabet(alphabet string) error {
+	if len(alphabet) < 2 {
+		return ErrInvalidAlphabet
+	}
+
+	seen := make(map[rune]struct{}, len(alphabet))
+	for _, r := range alphabet {
+		if !isAlphanumeric(r) || strings.ContainsRune(ambiguousChars, r) {
+			return ErrInvalidAlphabet
+		}
+		if _, ok := seen[r]; ok {
+			return ErrInvalidAlphabet
+		}
+		seen[r] = struct{}{}
+	}
+
+	return nil
+}
+
+func Contains(alphabet string, s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune(alphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+}
@@ -0,0 +1,90 @@
+package couponcode
+
+import (
+	"crypto/rand"
+	"math"
+)
+
+const (
+	MinLength = 4
+	MaxLength = 32
+)
+
+type Generator struct {
+	alphabet   string
+	length     int
+	prefix     string
+	checkDigit CheckDigit
+}
+
+// NewGenerator returns a generator for prefixed random codes. A nil
+// checkDigit generates codes without a check character.
+func NewGenerator(alphabet string, length int, prefix string, checkDigit CheckDigit) (*Generator, error) {
+	if err := ValidateAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+
+	if length < MinLength || length > MaxLength {
+		return nil, ErrInvalidLength
+	}
+
+	if !Contains(alphabet, prefix) {
+		return nil, ErrInvalidPrefix
+	}
+
+	return &Generator{
+		alphabet:   alphabet,
+		length:     length,
+		prefix:     prefix,
+		checkDigit: checkDigit,
+	}, nil
+}
+
+// Capacity returns the number of distinct codes the generator can produce,
+// saturating at math.MaxInt.
+func (g *Generator) Capacity() int {
+	capacity := math.Pow(float64(len(g.alphabet)), float64(g.length))
+	if capacity >= math.MaxInt {
+		return math.MaxInt
+	}
+	return int(capacity)
+}
+
+func (g *Generator) Generate() (string, error) {
+	body, err := g.random()
+	if err != nil {
+		return "", err
+	}
+
+	code := g.prefix + body
+	if g.checkDigit == nil {
+		return code, nil
+	}
+	return g.checkDigit.Append(code)
+}
+
+// random draws length characters uniformly from the alphabet, rejecting
+// bytes that would introduce a modulo bias.
+func (g *Generator) random() (string, error) {
+	n := len(g.alphabet)
+	limit := 256 - 256%n
+	out := make([]byte, 0, g.length)
+	buf := make([]byte, g.length*2)
+
+	for len(out) < g.length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			out = append(out, g.alphabet[int(b)%n])
+			if len(out) == g.length {
+				break
+			}
+		}
+	}
+
+	return string(out), nil
+}
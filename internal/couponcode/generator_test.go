@@ -0,0 +1,106 @@
+package couponcode_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+)
+
+func TestNewGenerator(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestNewGenerator in long mode.")
+	}
+
+	type args struct {
+		alphabet string
+		length   int
+		prefix   string
+	}
+
+	type testCase struct {
+		name        string
+		args        args
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Valid generator",
+			args: args{alphabet: couponcode.DefaultAlphabet, length: 8, prefix: "XMAS"},
+		},
+		{
+			name:        "Ambiguous characters in alphabet",
+			args:        args{alphabet: "ABCO0", length: 8},
+			expectedErr: couponcode.ErrInvalidAlphabet,
+		},
+		{
+			name:        "Duplicated characters in alphabet",
+			args:        args{alphabet: "ABCA", length: 8},
+			expectedErr: couponcode.ErrInvalidAlphabet,
+		},
+		{
+			name:        "Alphabet with separator",
+			args:        args{alphabet: "AB-C", length: 8},
+			expectedErr: couponcode.ErrInvalidAlphabet,
+		},
+		{
+			name:        "Length too short",
+			args:        args{alphabet: couponcode.DefaultAlphabet, length: 2},
+			expectedErr: couponcode.ErrInvalidLength,
+		},
+		{
+			name:        "Length too long",
+			args:        args{alphabet: couponcode.DefaultAlphabet, length: 64},
+			expectedErr: couponcode.ErrInvalidLength,
+		},
+		{
+			name:        "Prefix outside alphabet",
+			args:        args{alphabet: couponcode.DefaultAlphabet, length: 8, prefix: "SPRING"},
+			expectedErr: couponcode.ErrInvalidPrefix,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := couponcode.NewGenerator(tc.args.alphabet, tc.args.length, tc.args.prefix, nil)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGenerate in long mode.")
+	}
+
+	luhn, err := couponcode.NewLuhn(couponcode.DefaultAlphabet)
+	require.NoError(t, err)
+
+	gen, err := couponcode.NewGenerator(couponcode.DefaultAlphabet, 8, "XMAS", luhn)
+	require.NoError(t, err)
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 1000; i++ {
+		code, err := gen.Generate()
+		require.NoError(t, err)
+
+		assert.Len(t, code, len("XMAS")+8+1)
+		assert.True(t, strings.HasPrefix(code, "XMAS"), "expected prefix XMAS, got: %s", code)
+		assert.True(t, couponcode.Contains(couponcode.DefaultAlphabet, code), "unexpected character in %s", code)
+		assert.True(t, luhn.Valid(code), "invalid check char in %s", code)
+
+		seen[code] = struct{}{}
+	}
+
+	assert.Len(t, seen, 1000, "expected generated codes to be unique")
+}
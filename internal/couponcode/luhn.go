@@ -0,0 +1,78 @@
+package couponcode
+
+import "strings"
+
+// CheckDigit appends and verifies a trailing check character on codes.
+type CheckDigit interface {
+	Alphabet() string
+	Append(string) (string, error)
+	Valid(string) bool
+}
+
+// Luhn implements the Luhn mod N algorithm over an alphabet. It detects
+// every single-character typo and most adjacent transpositions.
+type Luhn struct {
+	alphabet string
+}
+
+func NewLuhn(alphabet string) (*Luhn, error) {
+	if err := ValidateAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+	return &Luhn{alphabet: alphabet}, nil
+}
+
+func (l *Luhn) Alphabet() string {
+	return l.alphabet
+}
+
+func (l *Luhn) Append(s string) (string, error) {
+	check, err := CheckChar(l.alphabet, s)
+	if err != nil {
+		return "", err
+	}
+	return s + string(check), nil
+}
+
+func (l *Luhn) Valid(s string) bool {
+	return Valid(l.alphabet, s)
+}
+
+// CheckChar computes the Luhn mod N check character of s over the given
+// alphabet. Every character of s must be part of the alphabet.
+func CheckChar(alphabet string, s string) (byte, error) {
+	n := len(alphabet)
+	factor := 2
+	sum := 0
+
+	for i := len(s) - 1; i >= 0; i-- {
+		codePoint := strings.IndexByte(alphabet, s[i])
+		if codePoint < 0 {
+			return 0, ErrInvalidAlphabet
+		}
+
+		addend := factor * codePoint
+		if factor == 2 {
+			factor = 1
+		} else {
+			factor = 2
+		}
+		sum += addend/n + addend%n
+	}
+
+	return alphabet[(n-sum%n)%n], nil
+}
+
+// Valid reports whether the last character of s is the Luhn mod N check
+// character of the characters before it.
+func Valid(alphabet string, s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+
+	check, err := CheckChar(alphabet, s[:len(s)-1])
+	if err != nil {
+		return false
+	}
+	return check == s[len(s)-1]
+}
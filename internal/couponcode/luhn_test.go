@@ -0,0 +1,86 @@
+package couponcode_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+)
+
+func TestCheckChar(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCheckChar in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		alphabet    string
+		input       string
+		want        byte
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Decimal alphabet matches classic Luhn",
+			alphabet: "0123456789",
+			input:    "7992739871",
+			want:     '3',
+		},
+		{
+			name:     "Default alphabet",
+			alphabet: couponcode.DefaultAlphabet,
+			input:    "ABCD",
+			want:     '8',
+		},
+		{
+			name:        "Character outside alphabet",
+			alphabet:    couponcode.DefaultAlphabet,
+			input:       "AB0D",
+			expectedErr: couponcode.ErrInvalidAlphabet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := couponcode.CheckChar(tc.alphabet, tc.input)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, string(tc.want), string(got), "expected check char %q, got: %q", tc.want, got)
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestValid in long mode.")
+	}
+
+	type testCase struct {
+		name     string
+		alphabet string
+		code     string
+		want     bool
+	}
+
+	testCases := []testCase{
+		{name: "Valid decimal code", alphabet: "0123456789", code: "79927398713", want: true},
+		{name: "Single digit typo", alphabet: "0123456789", code: "79927398714", want: false},
+		{name: "Valid code", alphabet: couponcode.DefaultAlphabet, code: "ABCD8", want: true},
+		{name: "Adjacent transposition", alphabet: couponcode.DefaultAlphabet, code: "BACD8", want: false},
+		{name: "Too short", alphabet: couponcode.DefaultAlphabet, code: "8", want: false},
+		{name: "Character outside alphabet", alphabet: couponcode.DefaultAlphabet, code: "AB0D8", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, couponcode.Valid(tc.alphabet, tc.code))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package couponcode
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidCharset = errors.New("invalid charset")
+
+// Policy describes how codes are normalized before they are stored or
+// looked up, and which normalized codes are acceptable.
+type Policy struct {
+	Trim       bool
+	FoldCase   bool
+	Separators string
+	Charset    string
+	MinLength  int
+	MaxLength  int
+}
+
+func (p Policy) Normalize(code string) string {
+	if p.Trim {
+		code = strings.TrimSpace(code)
+	}
+
+	if p.Separators != "" {
+		code = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.Separators, r) {
+				return -1
+			}
+			return r
+		}, code)
+	}
+
+	if p.FoldCase {
+		code = strings.ToUpper(code)
+	}
+
+	return code
+}
+
+// Stable reports whether s is left unchanged by normalization.
+func (p Policy) Stable(s string) bool {
+	return p.Normalize(s) == s
+}
+
+func (p Policy) Validate(code string) error {
+	length := utf8.RuneCountInString(code)
+	if length < p.MinLength || (p.MaxLength > 0 && length > p.MaxLength) {
+		return ErrInvalidLength
+	}
+
+	return p.ValidateCharset(code)
+}
+
+func (p Policy) ValidateCharset(s string) error {
+	if p.Charset != "" && !Contains(p.Charset, s) {
+		return ErrInvalidCharset
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package couponcode_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+)
+
+func TestNormalize(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestNormalize in long mode.")
+	}
+
+	type testCase struct {
+		name   string
+		policy couponcode.Policy
+		code   string
+		want   string
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Zero policy keeps code as given",
+			policy: couponcode.Policy{},
+			code:   " summer-10 ",
+			want:   " summer-10 ",
+		},
+		{
+			name:   "Trim",
+			policy: couponcode.Policy{Trim: true},
+			code:   " summer10\t",
+			want:   "summer10",
+		},
+		{
+			name:   "Fold case",
+			policy: couponcode.Policy{FoldCase: true},
+			code:   "Summer10",
+			want:   "SUMMER10",
+		},
+		{
+			name:   "Strip separators",
+			policy: couponcode.Policy{Separators: "- "},
+			code:   "SUM-MER 10",
+			want:   "SUMMER10",
+		},
+		{
+			name:   "All rules",
+			policy: couponcode.Policy{Trim: true, FoldCase: true, Separators: "-"},
+			code:   " sum-mer10 ",
+			want:   "SUMMER10",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.policy.Normalize(tc.code))
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPolicyValidate in long mode.")
+	}
+
+	policy := couponcode.Policy{
+		Charset:   "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+		MinLength: 4,
+		MaxLength: 12,
+	}
+
+	type testCase struct {
+		name        string
+		code        string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "Valid code", code: "SUMMER10"},
+		{name: "Too short", code: "ABC", expectedErr: couponcode.ErrInvalidLength},
+		{name: "Too long", code: "ABCDEFGHIJKLM", expectedErr: couponcode.ErrInvalidLength},
+		{name: "Character outside charset", code: "SUMMER_10", expectedErr: couponcode.ErrInvalidCharset},
+		{name: "Lower-case character", code: "summer10", expectedErr: couponcode.ErrInvalidCharset},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Validate(tc.code)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
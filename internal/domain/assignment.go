@@ -0,0 +1,34 @@
+package domain
+
+// Assignment restricts a coupon to individual customers or customer
+// segments. A zero Assignment leaves the coupon open to everyone.
+type Assignment struct {
+	CustomerIDs []string
+	Segments    []string
+}
+
+func (a Assignment) Restricted() bool {
+	return len(a.CustomerIDs) > 0 || len(a.Segments) > 0
+}
+
+func (a Assignment) AssignedTo(customer Customer) bool {
+	if customer.ID != "" {
+		for _, id := range a.CustomerIDs {
+			if id == customer.ID {
+				return true
+			}
+		}
+	}
+
+	for _, segment := range a.Segments {
+		if customer.InSegment(segment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a Assignment) Allows(customer Customer) bool {
+	return !a.Restricted() || a.AssignedTo(customer)
+}
@@ -3,4 +3,27 @@ package domain
 type Basket struct {
 	Value           int
 	AppliedDiscount int
+	Customer        Customer
+	// TimeZone is the IANA time zone of the store or channel, which
+	// coupon schedules are evaluated in. Empty means UTC.
+	TimeZone string
+	Channel  string
+	StoreID  string
+	Region   string
+	Lines    []Line
+}
+
+// Line is Quantity items of one SKU at UnitPrice each.
+type Line struct {
+	SKU       string
+	Category  string
+	Quantity  int
+	UnitPrice int
+	// Deposit marks refundable deposits such as bottle deposits (Pfand),
+	// which never count toward coupon conditions.
+	Deposit bool
+}
+
+func (l Line) Value() int {
+	return l.Quantity * l.UnitPrice
 }
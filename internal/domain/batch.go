@@ -0,0 +1,11 @@
+package domain
+
+type CodeBatch struct {
+	Count          int
+	Length         int
+	Alphabet       string
+	Prefix         string
+	CheckChar      bool
+	Discount       int
+	MinBasketValue int
+}
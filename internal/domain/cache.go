@@ -0,0 +1,9 @@
+package domain
+
+// CacheStats counts the lookups answered by a coupon cache and those passed
+// on to the repository behind it.
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
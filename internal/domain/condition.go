@@ -0,0 +1,81 @@
+package domain
+
+import "strings"
+
+type ConditionType string
+
+const (
+	ConditionAll         ConditionType = "all"
+	ConditionAny         ConditionType = "any"
+	ConditionMinQuantity ConditionType = "minQuantity"
+	ConditionContainsSKU ConditionType = "containsSku"
+	ConditionMinValue    ConditionType = "minValue"
+)
+
+// Condition is a requirement on the lines of a basket. All and any combine
+// nested conditions; the other types only look at eligible lines, which
+// excludes deposits.
+type Condition struct {
+	Type ConditionType
+	// Conditions are the operands of all and any.
+	Conditions []Condition
+	// Category limits minQuantity and minValue to lines of the category.
+	// Empty counts every eligible line.
+	Category string
+	SKU      string
+	Min      int
+}
+
+func (c Condition) Matches(lines []Line) bool {
+	switch c.Type {
+	case ConditionAll:
+		for _, condition := range c.Conditions {
+			if !condition.Matches(lines) {
+				return false
+			}
+		}
+		return true
+	case ConditionAny:
+		for _, condition := range c.Conditions {
+			if condition.Matches(lines) {
+				return true
+			}
+		}
+		return false
+	case ConditionMinQuantity:
+		quantity := 0
+		for _, line := range c.eligible(lines) {
+			quantity += line.Quantity
+		}
+		return quantity >= c.Min
+	case ConditionContainsSKU:
+		for _, line := range c.eligible(lines) {
+			if strings.EqualFold(line.SKU, c.SKU) {
+				return true
+			}
+		}
+		return false
+	case ConditionMinValue:
+		value := 0
+		for _, line := range c.eligible(lines) {
+			value += line.Value()
+		}
+		return value >= c.Min
+	default:
+		return false
+	}
+}
+
+func (c Condition) eligible(lines []Line) []Line {
+	eligible := make([]Line, 0, len(lines))
+	for _, line := range lines {
+		if line.Deposit {
+			continue
+		}
+		if c.Category != "" && !strings.EqualFold(line.Category, c.Category) {
+			continue
+		}
+		eligible = append(eligible, line)
+	}
+	return eligible
+}
@@ -1,8 +1,91 @@
 package domain
 
+import "encoding/json"
+
 type Coupon struct {
-	ID             string
-	Code           string
-	Discount       int
-	MinBasketValue int
+	ID                 string
+	Code               string
+	Discount           int
+	MinBasketValue     int
+	Assignment         Assignment
+	RequiresActivation bool
+	Schedule           Schedule
+	Eligibility        Eligibility
+	UsageLimits        []UsageLimit
+	Conditions         []Condition
+	// Expression is a CEL condition over the basket, customer and context
+	// that has to evaluate to true.
+	Expression string
+	// Strategy names the discount strategy calculating the discount, with
+	// Params passed to it as given. Empty means a fixed amount.
+	Strategy string
+	Params   json.RawMessage
+	// Version counts the writes of the coupon, starting at 1. Repositories
+	// set it, and reject updates made from an older version.
+	Version int
+}
+
+// CouponPatch changes the fields of a coupon that are set and keeps the
+// others.
+type CouponPatch struct {
+	Discount           *int
+	MinBasketValue     *int
+	CustomerIDs        *[]string
+	Segments           *[]string
+	RequiresActivation *bool
+	Schedule           *Schedule
+	Channels           *Restriction
+	Stores             *Restriction
+	Regions            *Restriction
+	UsageLimits        *[]UsageLimit
+	Conditions         *[]Condition
+	Expression         *string
+	Strategy           *string
+	Params             *json.RawMessage
+}
+
+func (p CouponPatch) Apply(coupon Coupon) Coupon {
+	if p.Discount != nil {
+		coupon.Discount = *p.Discount
+	}
+	if p.MinBasketValue != nil {
+		coupon.MinBasketValue = *p.MinBasketValue
+	}
+	if p.CustomerIDs != nil {
+		coupon.Assignment.CustomerIDs = *p.CustomerIDs
+	}
+	if p.Segments != nil {
+		coupon.Assignment.Segments = *p.Segments
+	}
+	if p.RequiresActivation != nil {
+		coupon.RequiresActivation = *p.RequiresActivation
+	}
+	if p.Schedule != nil {
+		coupon.Schedule = *p.Schedule
+	}
+	if p.Channels != nil {
+		coupon.Eligibility.Channels = *p.Channels
+	}
+	if p.Stores != nil {
+		coupon.Eligibility.Stores = *p.Stores
+	}
+	if p.Regions != nil {
+		coupon.Eligibility.Regions = *p.Regions
+	}
+	if p.UsageLimits != nil {
+		coupon.UsageLimits = *p.UsageLimits
+	}
+	if p.Conditions != nil {
+		coupon.Conditions = *p.Conditions
+	}
+	if p.Expression != nil {
+		coupon.Expression = *p.Expression
+	}
+	if p.Strategy != nil {
+		coupon.Strategy = *p.Strategy
+	}
+	if p.Params != nil {
+		coupon.Params = *p.Params
+	}
+	return coupon
 }
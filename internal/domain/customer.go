@@ -0,0 +1,18 @@
+package domain
+
+type Customer struct {
+	ID       string
+	Segments []string
+	// Tier is the loyalty tier of the customer, which coupon expressions
+	// can refer to.
+	Tier string
+}
+
+func (c Customer) InSegment(segment string) bool {
+	for _, s := range c.Segments {
+		if s == segment {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "strings"
+
+// Eligibility restricts where a coupon can be redeemed. A zero Eligibility
+// leaves the coupon valid in every channel, store and region.
+type Eligibility struct {
+	Channels Restriction
+	Stores   Restriction
+	Regions  Restriction
+}
+
+// Restriction is an allow and a deny list of case-insensitive values. A
+// denied value is never allowed, and an empty allow list allows every value
+// that is not denied.
+type Restriction struct {
+	Allow []string
+	Deny  []string
+}
+
+func (r Restriction) Restricted() bool {
+	return len(r.Allow) > 0 || len(r.Deny) > 0
+}
+
+func (r Restriction) Allows(value string) bool {
+	if contains(r.Deny, value) {
+		return false
+	}
+	return len(r.Allow) == 0 || contains(r.Allow, value)
+}
+
+func contains(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
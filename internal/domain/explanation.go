@@ -0,0 +1,15 @@
+package domain
+
+// Explanation reports the outcome of every rule of a coupon for a basket.
+type Explanation struct {
+	Code     string
+	Eligible bool
+	Discount int
+	Checks   []RuleCheck
+}
+
+type RuleCheck struct {
+	Rule   string
+	Passed bool
+	Reason string
+}
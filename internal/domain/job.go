@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+type Job struct {
+	ID         string
+	Status     JobStatus
+	Total      int
+	Done       int
+	Error      string
+	CreatedAt  time.Time
+	FinishedAt time.Time
+}
+
+func (j Job) Finished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
@@ -0,0 +1,28 @@
+package domain
+
+type Pricing struct {
+	Value           int
+	AppliedDiscount int
+	Discounts       []AppliedDiscount
+	Rejected        []RejectedCode
+}
+
+// AppliedDiscount is granted either by an auto promotion, identified by
+// PromotionID, or by an entered Code.
+type AppliedDiscount struct {
+	PromotionID string
+	Name        string
+	Code        string
+	Discount    int
+}
+
+type RejectedCode struct {
+	Code   string
+	Reason string
+}
+
+func (p *Pricing) Add(discount AppliedDiscount) {
+	p.Value -= discount.Discount
+	p.AppliedDiscount += discount.Discount
+	p.Discounts = append(p.Discounts, discount)
+}
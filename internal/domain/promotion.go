@@ -0,0 +1,9 @@
+package domain
+
+// Promotion is a code-less coupon that applies to every qualifying basket.
+// Its rules are those of the embedded coupon, whose Code stays empty.
+type Promotion struct {
+	Coupon
+	Name   string
+	Active bool
+}
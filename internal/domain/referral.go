@@ -0,0 +1,38 @@
+package domain
+
+type ReferralStatus string
+
+const (
+	ReferralPending  ReferralStatus = "pending"
+	ReferralRewarded ReferralStatus = "rewarded"
+	ReferralCapped   ReferralStatus = "capped"
+)
+
+// ReferralProgram describes the welcome coupon a referee gets for redeeming
+// a referral code and the reward coupon the referrer gets once the referee
+// completes their first order. Referral codes start with CodePrefix.
+// MaxRewards caps the rewards per referrer, 0 leaves them unlimited.
+type ReferralProgram struct {
+	CodePrefix string
+	Welcome    CouponTemplate
+	Reward     CouponTemplate
+	MaxRewards int
+}
+
+type Referral struct {
+	Code       string
+	ReferrerID string
+	RefereeID  string
+	Status     ReferralStatus
+	RewardCode string
+}
+
+type ReferralStats struct {
+	ReferrerID string
+	Code       string
+	Referrals  int
+	Pending    int
+	Rewarded   int
+	Capped     int
+	MaxRewards int
+}
@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+const MinutesPerDay = 24 * 60
+
+// Schedule restricts a coupon to recurring local times. A zero Schedule
+// leaves the coupon valid at any time.
+type Schedule struct {
+	Weekdays []time.Weekday
+	Windows  []TimeWindow
+}
+
+// TimeWindow is a local time range in minutes since midnight, including
+// Start and excluding End. A window with End before Start wraps past
+// midnight and belongs to the weekday it starts on.
+type TimeWindow struct {
+	Start int
+	End   int
+}
+
+func (s Schedule) Restricted() bool {
+	return len(s.Weekdays) > 0 || len(s.Windows) > 0
+}
+
+// Allows reports whether the schedule covers t, read as wall clock time in
+// the location of t.
+func (s Schedule) Allows(t time.Time) bool {
+	if !s.Restricted() {
+		return true
+	}
+
+	if len(s.Windows) == 0 {
+		return s.onWeekday(t.Weekday())
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	for _, window := range s.Windows {
+		if day, ok := window.contains(t.Weekday(), minute); ok && s.onWeekday(day) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s Schedule) onWeekday(day time.Weekday) bool {
+	if len(s.Weekdays) == 0 {
+		return true
+	}
+
+	for _, weekday := range s.Weekdays {
+		if weekday == day {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether minute on day falls into the window, and the
+// weekday the matching window started on.
+func (w TimeWindow) contains(day time.Weekday, minute int) (time.Weekday, bool) {
+	if w.Start < w.End {
+		return day, minute >= w.Start && minute < w.End
+	}
+
+	if minute >= w.Start {
+		return day, true
+	}
+	if minute < w.End {
+		return (day + 6) % 7, true
+	}
+	return day, false
+}
+
+func (w TimeWindow) Valid() bool {
+	return w.Start >= 0 && w.Start < MinutesPerDay && w.End > 0 && w.End <= MinutesPerDay && w.Start != w.End
+}
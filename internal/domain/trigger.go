@@ -0,0 +1,42 @@
+package domain
+
+type TriggerEvent string
+
+const (
+	EventRedemption     TriggerEvent = "redemption"
+	EventOrderCompleted TriggerEvent = "order_completed"
+)
+
+// Trigger issues a personal coupon built from Template to the customer of
+// every event of type Event that meets its condition. Code restricts
+// redemption triggers to redemptions of that code.
+type Trigger struct {
+	ID            string
+	Name          string
+	Event         TriggerEvent
+	MinOrderValue int
+	Code          string
+	Template      CouponTemplate
+}
+
+type CouponTemplate struct {
+	Prefix             string
+	Discount           int
+	MinBasketValue     int
+	RequiresActivation bool
+}
+
+type OrderEvent struct {
+	Type     TriggerEvent
+	OrderID  string
+	Customer Customer
+	Value    int
+	Code     string
+}
+
+// Issuance records the coupon a trigger issued for an order.
+type Issuance struct {
+	TriggerID string
+	OrderID   string
+	Code      string
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+type UsagePeriod string
+
+const (
+	UsagePerDay  UsagePeriod = "day"
+	UsagePerWeek UsagePeriod = "week"
+)
+
+// UsageLimit caps how often a customer can redeem a coupon within a period.
+// Rolling periods end now, calendar periods start at local midnight, or on
+// Monday at midnight for weeks.
+type UsageLimit struct {
+	Count   int
+	Period  UsagePeriod
+	Rolling bool
+}
+
+// Since returns the start of the period containing now, in the location of
+// now.
+func (l UsageLimit) Since(now time.Time) time.Time {
+	if l.Rolling {
+		switch l.Period {
+		case UsagePerWeek:
+			return now.Add(-7 * 24 * time.Hour)
+		default:
+			return now.Add(-24 * time.Hour)
+		}
+	}
+
+	year, month, day := now.Date()
+	if l.Period == UsagePerWeek {
+		day -= (int(now.Weekday()) + 6) % 7
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+type Redemption struct {
+	Code       string
+	CustomerID string
+	OrderID    string
+	RedeemedAt time.Time
+}
+
+// UsageWindow is a usage limit resolved to a point in time: at most Max
+// redemptions since Since.
+type UsageWindow struct {
+	Since time.Time
+	Max   int
+}
@@ -0,0 +1,6 @@
+package domain
+
+type WalletEntry struct {
+	Coupon    Coupon
+	Activated bool
+}
@@ -0,0 +1,187 @@
+// Package expression compiles and evaluates coupon conditions written in
+// CEL, e.g. `basket.total >= 2000 && customer.tier == "gold"`.
+//
+// Expressions only see the Input they are evaluated against. They cannot
+// call out of the sandbox, and every evaluation is bounded by a cost limit.
+package expression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/google/cel-go/interpreter"
+)
+
+const (
+	DefaultCostLimit = 10000
+	// MaxLength is the maximum length of an expression in bytes.
+	MaxLength = 1024
+
+	maxPrograms       = 1024
+	maxRecursionDepth = 32
+	interruptInterval = 100
+)
+
+var (
+	ErrInvalid          = errors.New("invalid expression")
+	ErrCostExceeded     = errors.New("expression cost limit exceeded")
+	ErrNotBoolean       = errors.New("expression does not evaluate to a boolean")
+	ErrInvalidLimit     = errors.New("invalid cost limit")
+	ErrEvaluationFailed = errors.New("expression evaluation failed")
+)
+
+// Input is everything an expression can refer to: basket, customer and
+// context.
+type Input struct {
+	Basket   Basket
+	Customer Customer
+	Context  Context
+}
+
+type Basket struct {
+	Total int    `cel:"total"`
+	Lines []Line `cel:"lines"`
+}
+
+type Line struct {
+	SKU       string `cel:"sku"`
+	Category  string `cel:"category"`
+	Quantity  int    `cel:"quantity"`
+	UnitPrice int    `cel:"unitPrice"`
+	Deposit   bool   `cel:"deposit"`
+}
+
+type Customer struct {
+	ID       string   `cel:"id"`
+	Tier     string   `cel:"tier"`
+	Segments []string `cel:"segments"`
+}
+
+// Context describes where and when a basket is checked out. Now is in the
+// time zone of the basket.
+type Context struct {
+	Channel  string    `cel:"channel"`
+	Store    string    `cel:"store"`
+	Region   string    `cel:"region"`
+	TimeZone string    `cel:"timeZone"`
+	Now      time.Time `cel:"now"`
+}
+
+// Engine compiles expressions and caches the compiled programs by source.
+type Engine struct {
+	env       *cel.Env
+	costLimit uint64
+
+	mu       *sync.RWMutex
+	programs map[string]cel.Program
+}
+
+func New(costLimit int) (*Engine, error) {
+	if costLimit <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	env, err := cel.NewEnv(
+		ext.NativeTypes(ext.ParseStructTags(true),
+			reflect.TypeOf(Basket{}), reflect.TypeOf(Customer{}), reflect.TypeOf(Context{})),
+		cel.Variable("basket", cel.ObjectType("expression.Basket")),
+		cel.Variable("customer", cel.ObjectType("expression.Customer")),
+		cel.Variable("context", cel.ObjectType("expression.Context")),
+		cel.ParserRecursionLimit(maxRecursionDepth),
+		cel.ParserExpressionSizeLimit(MaxLength),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Engine{
+		env:       env,
+		costLimit: uint64(costLimit),
+		mu:        &sync.RWMutex{},
+		programs:  make(map[string]cel.Program),
+	}, nil
+}
+
+// Compile type checks the expression and caches its program. Compile errors
+// wrap ErrInvalid or ErrNotBoolean and describe the problem.
+func (e *Engine) Compile(expr string) error {
+	_, err := e.program(expr)
+	return err
+}
+
+// Eval evaluates the expression against the input, compiling it first if it
+// is not cached yet.
+func (e *Engine) Eval(ctx context.Context, expr string, in Input) (bool, error) {
+	program, err := e.program(expr)
+	if err != nil {
+		return false, err
+	}
+
+	out, _, err := program.ContextEval(ctx, map[string]any{
+		"basket":   in.Basket,
+		"customer": in.Customer,
+		"context":  in.Context,
+	})
+	if err != nil {
+		var cancelled interpreter.EvalCancelledError
+		if errors.As(err, &cancelled) && cancelled.Cause == interpreter.CostLimitExceeded {
+			return false, ErrCostExceeded
+		}
+		return false, fmt.Errorf("%w: %v", ErrEvaluationFailed, err)
+	}
+
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, ErrNotBoolean
+	}
+	return result, nil
+}
+
+func (e *Engine) program(expr string) (cel.Program, error) {
+	e.mu.RLock()
+	program, ok := e.programs[expr]
+	e.mu.RUnlock()
+	if ok {
+		return program, nil
+	}
+
+	if len(expr) > MaxLength {
+		return nil, fmt.Errorf("%w: longer than %d bytes", ErrInvalid, MaxLength)
+	}
+
+	ast, issues := e.env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, issues.Err())
+	}
+
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, ErrNotBoolean
+	}
+
+	program, err := e.env.Program(ast,
+		cel.CostLimit(e.costLimit),
+		cel.InterruptCheckFrequency(interruptInterval),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.programs) >= maxPrograms {
+		for cached := range e.programs {
+			delete(e.programs, cached)
+			break
+		}
+	}
+	e.programs[expr] = program
+
+	return program, nil
+}
@@ -0,0 +1,168 @@
+package expression_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/expression"
+)
+
+func TestEval(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestEval in long mode.")
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	in := expression.Input{
+		Basket: expression.Basket{
+			Total: 2500,
+			Lines: []expression.Line{
+				{SKU: "water-1l", Category: "drinks", Quantity: 6, UnitPrice: 50},
+				{SKU: "pfand-025", Category: "drinks", Quantity: 6, UnitPrice: 25, Deposit: true},
+				{SKU: "coffee-500g", Category: "food", Quantity: 1, UnitPrice: 1900},
+			},
+		},
+		Customer: expression.Customer{ID: "c1", Tier: "gold", Segments: []string{"newsletter"}},
+		Context: expression.Context{
+			Channel: "online",
+			Region:  "DE",
+			Now:     time.Date(2024, 10, 5, 18, 30, 0, 0, berlin),
+		},
+	}
+
+	type testCase struct {
+		name        string
+		expr        string
+		want        bool
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Basket total and customer tier",
+			expr: `basket.total >= 2000 && customer.tier == "gold"`,
+			want: true,
+		},
+		{
+			name: "Other tier",
+			expr: `basket.total >= 2000 && customer.tier == "silver"`,
+			want: false,
+		},
+		{
+			name: "Quantity of non-deposit lines",
+			expr: `basket.lines.filter(l, !l.deposit && l.category == "drinks").map(l, l.quantity).exists(q, q >= 6)`,
+			want: true,
+		},
+		{
+			name: "Customer segment and channel",
+			expr: `"newsletter" in customer.segments && context.channel == "online"`,
+			want: true,
+		},
+		{
+			name: "Weekday in the basket time zone",
+			expr: `context.now.getDayOfWeek("Europe/Berlin") == 6 && context.now.getHours("Europe/Berlin") >= 18`,
+			want: true,
+		},
+		{
+			name:        "Cost limit",
+			expr:        `[1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(a, [1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(b, [1, 2, 3, 4, 5, 6, 7, 8, 9, 10].all(c, a + b + c > 0)))`,
+			expectedErr: expression.ErrCostExceeded,
+		},
+		{
+			name:        "Runtime error",
+			expr:        `basket.total / (basket.total - 2500) > 1`,
+			expectedErr: expression.ErrEvaluationFailed,
+		},
+	}
+
+	engine, err := expression.New(1000)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := engine.Eval(context.Background(), tc.expr, in)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCompile in long mode.")
+	}
+
+	engine, err := expression.New(expression.DefaultCostLimit)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name        string
+		expr        string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Valid expression",
+			expr: `basket.total >= 2000`,
+		},
+		{
+			name:        "Syntax error",
+			expr:        `basket.total >=`,
+			expectedErr: expression.ErrInvalid,
+		},
+		{
+			name:        "Unknown field",
+			expr:        `basket.tax > 0`,
+			expectedErr: expression.ErrInvalid,
+		},
+		{
+			name:        "Unknown variable",
+			expr:        `order.total > 0`,
+			expectedErr: expression.ErrInvalid,
+		},
+		{
+			name:        "Type mismatch",
+			expr:        `customer.tier >= 2000`,
+			expectedErr: expression.ErrInvalid,
+		},
+		{
+			name:        "Not a boolean",
+			expr:        `basket.total`,
+			expectedErr: expression.ErrNotBoolean,
+		},
+		{
+			name:        "Too long",
+			expr:        strings.Repeat("true && ", expression.MaxLength) + "true",
+			expectedErr: expression.ErrInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := engine.Compile(tc.expr)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+
+	_, err = expression.New(0)
+	assert.ErrorIs(t, err, expression.ErrInvalidLimit)
+}
@@ -0,0 +1,28 @@
+package bolt
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+// storageError marks errors of the file, rather than of the data, as
+// repository.ErrUnavailable. Other errors are returned as is.
+func storageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pathErr *fs.PathError
+	var errno syscall.Errno
+	if errors.Is(err, bbolt.ErrDatabaseNotOpen) || errors.Is(err, bbolt.ErrTimeout) ||
+		errors.As(err, &pathErr) || errors.As(err, &errno) {
+		return repository.Unavailable(err)
+	}
+
+	return err
+}
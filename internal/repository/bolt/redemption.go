@@ -0,0 +1,137 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+// Redemptions keeps a bucket per customer and code holding their
+// redemptions, keyed by time so that counting a window is a range scan.
+type Redemptions struct {
+	db *bbolt.DB
+}
+
+func (r *Redemptions) Count(ctx context.Context, customerID, code string, since time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(redemptionsBucket).Bucket(redemptionKey(customerID, code))
+		count = countSince(bucket, since)
+		return nil
+	})
+	if err != nil {
+		return 0, storageError(err)
+	}
+
+	return count, nil
+}
+
+// Record checks the windows and stores the redemption in one transaction.
+func (r *Redemptions) Record(ctx context.Context, redemption domain.Redemption, windows []domain.UsageWindow) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	data, err := json.Marshal(redemption)
+	if err != nil {
+		return false, err
+	}
+
+	recorded := false
+	err = r.db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.Bucket(redemptionsBucket).
+			CreateBucketIfNotExists(redemptionKey(redemption.CustomerID, redemption.Code))
+		if err != nil {
+			return err
+		}
+
+		for _, window := range windows {
+			if countSince(bucket, window.Since) >= window.Max {
+				return nil
+			}
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.Put(timeKey(redemption.RedeemedAt, seq), data); err != nil {
+			return err
+		}
+		recorded = true
+		return nil
+	})
+	if err != nil {
+		return false, storageError(err)
+	}
+
+	return recorded, nil
+}
+
+// HasRedeemed looks for a bucket of the customer, which only exists once they
+// redeemed a coupon.
+func (r *Redemptions) HasRedeemed(ctx context.Context, customerID string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	prefix := redemptionKey(customerID, "")
+	redeemed := false
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		k, _ := tx.Bucket(redemptionsBucket).Cursor().Seek(prefix)
+		redeemed = k != nil && bytes.HasPrefix(k, prefix)
+		return nil
+	})
+	if err != nil {
+		return false, storageError(err)
+	}
+
+	return redeemed, nil
+}
+
+func countSince(bucket *bbolt.Bucket, since time.Time) int {
+	if bucket == nil {
+		return 0
+	}
+
+	count := 0
+	c := bucket.Cursor()
+	for k, _ := c.Seek(timeKey(since, 0)); k != nil; k, _ = c.Next() {
+		count++
+	}
+	return count
+}
+
+// redemptionKey prefixes the customer ID with its length, so that no pair of
+// customer ID and code shares the key of another, and the key of a customer
+// without code prefixes only the keys of that customer.
+func redemptionKey(customerID, code string) []byte {
+	key := binary.AppendUvarint(nil, uint64(len(customerID)))
+	key = append(key, customerID...)
+	return append(key, code...)
+}
+
+// timeKey sorts by time first, and by sequence among redemptions at the
+// same instant. Times before 1970 all map to the first key.
+func timeKey(t time.Time, seq uint64) []byte {
+	var nanos uint64
+	if n := t.UnixNano(); n > 0 {
+		nanos = uint64(n)
+	}
+
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[0:8], nanos)
+	binary.BigEndian.PutUint64(key[8:16], seq)
+	return key
+}
@@ -0,0 +1,337 @@
+// Package bolt stores coupons and redemptions in a single bbolt file, an
+// embedded key-value store that needs no server.
+package bolt
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/document"
+)
+
+var (
+	couponsBucket     = []byte("coupons")
+	codesBucket       = []byte("codes")
+	redemptionsBucket = []byte("redemptions")
+)
+
+const openTimeout = 5 * time.Second
+
+// Repository stores every coupon as a JSON document keyed by ID, with an
+// index from code to ID. Every update runs in a single transaction.
+type Repository struct {
+	db *bbolt.DB
+}
+
+// Open opens the database at path, creating it and its buckets if needed.
+// It fails if another process holds the file open.
+func Open(path string) (*Repository, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{couponsBucket, codesBucket, redemptionsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Repository{db: db}, nil
+}
+
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+// Redemptions returns the redemption history kept in the same file.
+func (r *Repository) Redemptions() *Redemptions {
+	return &Redemptions{db: r.db}
+}
+
+// Backup writes a consistent copy of the database file to w. Writes go on
+// while it runs; the copy holds the state when it started.
+func (r *Repository) Backup(ctx context.Context, w io.Writer) error {
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		_, err := tx.WriteTo(&contextWriter{ctx: ctx, w: w})
+		return err
+	})
+	// bbolt does not wrap the errors of the writer.
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+func (r *Repository) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var coupon *domain.Coupon
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		coupon, err = find(tx, code)
+		if err == nil && coupon == nil {
+			return repository.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return coupon, nil
+}
+
+// FindByCodes reads every coupon in a single transaction, so they are
+// consistent with one another.
+func (r *Repository) FindByCodes(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	coupons := make([]domain.Coupon, 0, len(codes))
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		for _, code := range codes {
+			coupon, err := find(tx, code)
+			if err != nil {
+				return err
+			}
+			if coupon != nil {
+				coupons = append(coupons, *coupon)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return coupons, nil
+}
+
+// Save stores the coupon, replacing the coupon with the same code or ID and
+// keeping the code index in step.
+func (r *Repository) Save(ctx context.Context, coupon domain.Coupon) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return storageError(r.db.Update(func(tx *bbolt.Tx) error {
+		stored, err := find(tx, coupon.Code)
+		if err != nil {
+			return err
+		}
+
+		coupon.Version = 1
+		if stored != nil {
+			coupon.Version = stored.Version + 1
+		}
+		return put(tx, coupon)
+	}))
+}
+
+// Insert stores the coupon unless its code is taken, in which case it
+// returns repository.ErrAlreadyExists.
+func (r *Repository) Insert(ctx context.Context, coupon domain.Coupon) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return storageError(r.db.Update(func(tx *bbolt.Tx) error {
+		if tx.Bucket(codesBucket).Get([]byte(coupon.Code)) != nil {
+			return repository.ErrAlreadyExists
+		}
+
+		coupon.Version = 1
+		return put(tx, coupon)
+	}))
+}
+
+func (r *Repository) Update(ctx context.Context, coupon domain.Coupon) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return storageError(r.db.Update(func(tx *bbolt.Tx) error {
+		if _, err := findVersion(tx, coupon.Code, coupon.Version); err != nil {
+			return err
+		}
+
+		coupon.Version++
+		return put(tx, coupon)
+	}))
+}
+
+func (r *Repository) Delete(ctx context.Context, code string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return storageError(r.db.Update(func(tx *bbolt.Tx) error {
+		stored, err := findVersion(tx, code, version)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Bucket(couponsBucket).Delete([]byte(stored.ID)); err != nil {
+			return err
+		}
+		return tx.Bucket(codesBucket).Delete([]byte(code))
+	}))
+}
+
+// find returns the coupon with the code, or nil if there is none.
+func find(tx *bbolt.Tx, code string) (*domain.Coupon, error) {
+	id := tx.Bucket(codesBucket).Get([]byte(code))
+	if id == nil {
+		return nil, nil
+	}
+
+	data := tx.Bucket(couponsBucket).Get(id)
+	if data == nil {
+		return nil, nil
+	}
+
+	return document.Decode(data)
+}
+
+// findVersion returns the coupon with the code if it is at the version.
+func findVersion(tx *bbolt.Tx, code string, version int) (*domain.Coupon, error) {
+	stored, err := find(tx, code)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, repository.ErrNotFound
+	}
+	if stored.Version != version {
+		return nil, repository.ErrVersionMismatch
+	}
+	return stored, nil
+}
+
+// put stores the coupon, dropping the coupon previously holding its code and
+// the code previously held by its ID.
+func put(tx *bbolt.Tx, coupon domain.Coupon) error {
+	data, err := document.Encode(coupon)
+	if err != nil {
+		return err
+	}
+
+	coupons := tx.Bucket(couponsBucket)
+	codes := tx.Bucket(codesBucket)
+	id := []byte(coupon.ID)
+
+	if previous := codes.Get([]byte(coupon.Code)); previous != nil && string(previous) != coupon.ID {
+		if err := coupons.Delete(previous); err != nil {
+			return err
+		}
+	}
+
+	if existing := coupons.Get(id); existing != nil {
+		old, err := document.Decode(existing)
+		if err != nil {
+			return err
+		}
+		if old.Code != coupon.Code {
+			if err := codes.Delete([]byte(old.Code)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := coupons.Put(id, data); err != nil {
+		return err
+	}
+	return codes.Put([]byte(coupon.Code), id)
+}
+
+// FindByCustomer scans every coupon, which is fine for the small data sets
+// an embedded store is meant for.
+func (r *Repository) FindByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	coupons := make([]domain.Coupon, 0)
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(couponsBucket).ForEach(func(_, data []byte) error {
+			coupon, err := document.Decode(data)
+			if err != nil {
+				return err
+			}
+			if coupon.Assignment.AssignedTo(customer) {
+				coupons = append(coupons, *coupon)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].Code < coupons[j].Code
+	})
+
+	return coupons, nil
+}
+
+// FindActivatable scans every coupon like FindByCustomer.
+func (r *Repository) FindActivatable(ctx context.Context) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	coupons := make([]domain.Coupon, 0)
+	err := r.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(couponsBucket).ForEach(func(_, data []byte) error {
+			coupon, err := document.Decode(data)
+			if err != nil {
+				return err
+			}
+			if coupon.RequiresActivation && !coupon.Assignment.Restricted() {
+				coupons = append(coupons, *coupon)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].Code < coupons[j].Code
+	})
+
+	return coupons, nil
+}
+
+// contextWriter stops a backup once the context is done, e.g. when the
+// client downloading it goes away.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
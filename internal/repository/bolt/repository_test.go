@@ -0,0 +1,226 @@
+package bolt_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/bolt"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/repositorytest"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func openRepository(t *testing.T) *bolt.Repository {
+	repo, err := bolt.Open(filepath.Join(t.TempDir(), "coupons.bolt"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func newRepository(t *testing.T) service.Repository {
+	return openRepository(t)
+}
+
+func TestRepository(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRepository in long mode.")
+	}
+
+	repositorytest.Run(t, newRepository)
+}
+
+func TestRedemptions(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedemptions in long mode.")
+	}
+
+	repositorytest.Redemptions(t, func(t *testing.T) service.RedemptionRepository {
+		return openRepository(t).Redemptions()
+	})
+}
+
+func TestRedemptionsCustomerWithNUL(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedemptionsCustomerWithNUL in long mode.")
+	}
+
+	ctx := context.Background()
+	repo := openRepository(t).Redemptions()
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+	// Customer IDs are opaque, so one holding a NUL byte and the code of another
+	// redemption must not share its history.
+	for _, redemption := range []domain.Redemption{
+		{CustomerID: "c1\x00daily", Code: "x", RedeemedAt: now},
+		{CustomerID: "c1", Code: "daily\x00x", RedeemedAt: now},
+	} {
+		if _, err := repo.Record(ctx, redemption, nil); err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+
+	count, err := repo.Count(ctx, "c1", "daily\x00x", now)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected count to be 1, got %d", count)
+	}
+
+	for customerID, want := range map[string]bool{"c1": true, "c1\x00daily": true, "c1\x00": false, "c2": false} {
+		redeemed, err := repo.HasRedeemed(ctx, customerID)
+		if err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+		if redeemed != want {
+			t.Errorf("expected %q to have redeemed to be %v, got %v", customerID, want, redeemed)
+		}
+	}
+}
+
+func TestCodeIndex(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCodeIndex in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		saved       []domain.Coupon
+		code        string
+		expectedErr error
+		wantID      string
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Code saved again under another ID",
+			saved:  []domain.Coupon{{ID: "id1", Code: "code"}, {ID: "id2", Code: "code"}},
+			code:   "code",
+			wantID: "id2",
+		},
+		{
+			name:        "ID saved again under another code",
+			saved:       []domain.Coupon{{ID: "id1", Code: "old"}, {ID: "id1", Code: "new"}},
+			code:        "old",
+			expectedErr: repository.ErrNotFound,
+		},
+		{
+			name:   "ID saved again finds new code",
+			saved:  []domain.Coupon{{ID: "id1", Code: "old"}, {ID: "id1", Code: "new"}},
+			code:   "new",
+			wantID: "id1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := openRepository(t)
+
+			for _, coupon := range tc.saved {
+				if err := repo.Save(ctx, coupon); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+
+			got, err := repo.FindByCode(ctx, tc.code)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected err to be %v, got %v", tc.expectedErr, err)
+			}
+			if tc.expectedErr == nil && got.ID != tc.wantID {
+				t.Errorf("expected ID to be %q, got %q", tc.wantID, got.ID)
+			}
+		})
+	}
+}
+
+func TestBackup(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestBackup in long mode.")
+	}
+
+	ctx := context.Background()
+	repo := openRepository(t)
+
+	coupon := domain.Coupon{ID: "id1", Code: "backed-up", Discount: 10}
+	if err := repo.Save(ctx, coupon); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	var backup bytes.Buffer
+	if err := repo.Backup(ctx, &backup); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	// Saves after the backup started are not part of it.
+	if err := repo.Save(ctx, domain.Coupon{ID: "id2", Code: "later", Discount: 10}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "restored.bolt")
+	if err := os.WriteFile(path, backup.Bytes(), 0o600); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	restored, err := bolt.Open(path)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	defer restored.Close()
+
+	got, err := restored.FindByCode(ctx, coupon.Code)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if got.ID != coupon.ID || got.Discount != coupon.Discount {
+		t.Errorf("expected coupon to be %v, got %v", coupon, *got)
+	}
+
+	if _, err := restored.FindByCode(ctx, "later"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrNotFound, err)
+	}
+}
+
+func TestBackupCancelled(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestBackupCancelled in long mode.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var backup bytes.Buffer
+	if err := openRepository(t).Backup(ctx, &backup); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected err to be %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestClosed in long mode.")
+	}
+
+	ctx := context.Background()
+	repo, err := bolt.Open(filepath.Join(t.TempDir(), "coupons.bolt"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	if _, err := repo.FindByCode(ctx, "code"); !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+	}
+	if err := repo.Save(ctx, domain.Coupon{ID: "id1", Code: "code"}); !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+	}
+}
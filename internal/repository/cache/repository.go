@@ -0,0 +1,280 @@
+// Package cache keeps recently looked up coupons in memory in front of any
+// coupon repository.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+// Backend is the coupon repository the cache wraps, such as any of the
+// repository packages. It has the methods of the service's repository, so
+// that the cache can stand in for it without depending on the service.
+type Backend interface {
+	FindByCode(context.Context, string) (*domain.Coupon, error)
+	FindByCodes(context.Context, []string) ([]domain.Coupon, error)
+	Save(context.Context, domain.Coupon) error
+	Insert(context.Context, domain.Coupon) error
+	Update(context.Context, domain.Coupon) error
+	Delete(context.Context, string, int) error
+	FindByCustomer(context.Context, domain.Customer) ([]domain.Coupon, error)
+	FindActivatable(context.Context) ([]domain.Coupon, error)
+}
+
+type Options struct {
+	// Size bounds the number of cached codes, known or not. The least
+	// recently used code is evicted first.
+	Size int
+	// TTL bounds how long a coupon is cached. Zero keeps it until it is
+	// evicted or written.
+	TTL time.Duration
+	// NegativeTTL bounds how long a code no coupon has is remembered as
+	// unknown. Zero disables caching unknown codes.
+	NegativeTTL time.Duration
+	// Now replaces the wall clock entries expire by.
+	Now func() time.Time
+}
+
+type entry struct {
+	code string
+	// coupon is nil for unknown codes.
+	coupon  *domain.Coupon
+	expires time.Time
+}
+
+// Repository answers lookups by code from a bounded LRU cache and passes
+// everything else to the repository it wraps. Writes through it invalidate
+// the code written, while writes made elsewhere, such as by other instances,
+// are seen once the entry expires.
+//
+// Cached coupons are shared with callers as shallow copies, whose slices must
+// not be modified in place.
+type Repository struct {
+	next Backend
+	opts Options
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// generation counts invalidations, so that a lookup racing with a write
+	// does not cache what it read before the write.
+	generation uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// New wraps next with a cache of opts.Size codes, which has to be positive.
+func New(next Backend, opts Options) *Repository {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &Repository{
+		next:    next,
+		opts:    opts,
+		entries: make(map[string]*list.Element, opts.Size),
+		lru:     list.New(),
+	}
+}
+
+// Stats returns how often lookups were answered from the cache, counting
+// every code of FindByCodes, and how many codes are cached.
+func (r *Repository) Stats() domain.CacheStats {
+	r.mu.Lock()
+	entries := r.lru.Len()
+	r.mu.Unlock()
+
+	return domain.CacheStats{
+		Hits:    r.hits.Load(),
+		Misses:  r.misses.Load(),
+		Entries: entries,
+	}
+}
+
+func (r *Repository) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if coupon, ok := r.lookup(code); ok {
+		r.hits.Add(1)
+		if coupon == nil {
+			return nil, repository.ErrNotFound
+		}
+		found := *coupon
+		return &found, nil
+	}
+	r.misses.Add(1)
+
+	generation := r.currentGeneration()
+	coupon, err := r.next.FindByCode(ctx, code)
+	switch {
+	case err == nil:
+		cached := *coupon
+		r.store(generation, code, &cached)
+	case errors.Is(err, repository.ErrNotFound):
+		r.store(generation, code, nil)
+	}
+	return coupon, err
+}
+
+func (r *Repository) FindByCodes(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	generation := r.currentGeneration()
+
+	known := make(map[string]*domain.Coupon, len(codes))
+	missing := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if coupon, ok := r.lookup(code); ok {
+			r.hits.Add(1)
+			known[code] = coupon
+			continue
+		}
+		r.misses.Add(1)
+		missing = append(missing, code)
+	}
+
+	if len(missing) > 0 {
+		found, err := r.next.FindByCodes(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for i := range found {
+			known[found[i].Code] = &found[i]
+		}
+		// Codes left out are unknown and cached as such.
+		for _, code := range missing {
+			r.store(generation, code, known[code])
+		}
+	}
+
+	coupons := make([]domain.Coupon, 0, len(codes))
+	for _, code := range codes {
+		if coupon := known[code]; coupon != nil {
+			coupons = append(coupons, *coupon)
+		}
+	}
+	return coupons, nil
+}
+
+func (r *Repository) Save(ctx context.Context, coupon domain.Coupon) error {
+	defer r.invalidate(coupon.Code)
+	return r.next.Save(ctx, coupon)
+}
+
+func (r *Repository) Insert(ctx context.Context, coupon domain.Coupon) error {
+	defer r.invalidate(coupon.Code)
+	return r.next.Insert(ctx, coupon)
+}
+
+func (r *Repository) Update(ctx context.Context, coupon domain.Coupon) error {
+	// A version mismatch means the cached coupon may be stale as well, so
+	// the code is invalidated whatever the outcome.
+	defer r.invalidate(coupon.Code)
+	return r.next.Update(ctx, coupon)
+}
+
+func (r *Repository) Delete(ctx context.Context, code string, version int) error {
+	defer r.invalidate(code)
+	return r.next.Delete(ctx, code, version)
+}
+
+// FindByCustomer is not cached, as the coupons of a customer change with
+// writes to any code.
+func (r *Repository) FindByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	return r.next.FindByCustomer(ctx, customer)
+}
+
+// FindActivatable is not cached for the same reason.
+func (r *Repository) FindActivatable(ctx context.Context) ([]domain.Coupon, error) {
+	return r.next.FindActivatable(ctx)
+}
+
+// lookup returns the cached coupon with the code, or nil if the code is
+// cached as unknown, and whether the code is cached at all.
+func (r *Repository) lookup(code string) (*domain.Coupon, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.entries[code]
+	if !ok {
+		return nil, false
+	}
+
+	e := element.Value.(*entry)
+	if !e.expires.IsZero() && !r.opts.Now().Before(e.expires) {
+		r.remove(element)
+		return nil, false
+	}
+
+	r.lru.MoveToFront(element)
+	return e.coupon, true
+}
+
+func (r *Repository) currentGeneration() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.generation
+}
+
+// store caches the coupon with the code, or the code as unknown if coupon is
+// nil, unless a write invalidated the cache since generation.
+func (r *Repository) store(generation uint64, code string, coupon *domain.Coupon) {
+	ttl := r.opts.TTL
+	if coupon == nil {
+		if r.opts.NegativeTTL <= 0 {
+			return
+		}
+		ttl = r.opts.NegativeTTL
+	}
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = r.opts.Now().Add(ttl)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if generation != r.generation {
+		return
+	}
+
+	if element, ok := r.entries[code]; ok {
+		element.Value = &entry{code: code, coupon: coupon, expires: expires}
+		r.lru.MoveToFront(element)
+		return
+	}
+
+	r.entries[code] = r.lru.PushFront(&entry{code: code, coupon: coupon, expires: expires})
+	for r.lru.Len() > r.opts.Size {
+		r.remove(r.lru.Back())
+	}
+}
+
+func (r *Repository) invalidate(code string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	if element, ok := r.entries[code]; ok {
+		r.remove(element)
+	}
+}
+
+func (r *Repository) remove(element *list.Element) {
+	r.lru.Remove(element)
+	delete(r.entries, element.Value.(*entry).code)
+}
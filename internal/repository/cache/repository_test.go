@@ -0,0 +1,340 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/cache"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/repositorytest"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+// counting records the codes and customers looked up in the repository
+// behind the cache.
+type counting struct {
+	cache.Backend
+	lookups   []string
+	customers []string
+	// afterLookup, if set, runs once FindByCode has read the coupon.
+	afterLookup func()
+}
+
+func (c *counting) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	c.lookups = append(c.lookups, code)
+	coupon, err := c.Backend.FindByCode(ctx, code)
+	if c.afterLookup != nil {
+		c.afterLookup()
+	}
+	return coupon, err
+}
+
+func (c *counting) FindByCodes(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	c.lookups = append(c.lookups, codes...)
+	return c.Backend.FindByCodes(ctx, codes)
+}
+
+func (c *counting) FindByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	c.customers = append(c.customers, customer.ID)
+	return c.Backend.FindByCustomer(ctx, customer)
+}
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func TestRepository(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRepository in long mode.")
+	}
+
+	// A small cache that remembers unknown codes has every write invalidate
+	// entries the suite looked up before.
+	repositorytest.Run(t, func(*testing.T) service.Repository {
+		return cache.New(memory.New(), cache.Options{Size: 4, TTL: time.Minute, NegativeTTL: time.Minute})
+	})
+}
+
+func TestFindByCode(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestFindByCode in long mode.")
+	}
+
+	type step struct {
+		// advance moves the clock before the lookup.
+		advance     time.Duration
+		code        string
+		expectedErr error
+	}
+
+	type testCase struct {
+		name        string
+		opts        cache.Options
+		steps       []step
+		wantLookups []string
+		wantStats   domain.CacheStats
+	}
+
+	testCases := []testCase{
+		{
+			name:        "Repeated lookups hit the cache",
+			opts:        cache.Options{Size: 2, TTL: time.Minute},
+			steps:       []step{{code: "a"}, {code: "a"}, {code: "a"}},
+			wantLookups: []string{"a"},
+			wantStats:   domain.CacheStats{Hits: 2, Misses: 1, Entries: 1},
+		},
+		{
+			name:        "Expired coupons are looked up again",
+			opts:        cache.Options{Size: 2, TTL: time.Minute},
+			steps:       []step{{code: "a"}, {advance: 59 * time.Second, code: "a"}, {advance: time.Second, code: "a"}},
+			wantLookups: []string{"a", "a"},
+			wantStats:   domain.CacheStats{Hits: 1, Misses: 2, Entries: 1},
+		},
+		{
+			name:        "Coupons without TTL do not expire",
+			opts:        cache.Options{Size: 2},
+			steps:       []step{{code: "a"}, {advance: 24 * time.Hour, code: "a"}},
+			wantLookups: []string{"a"},
+			wantStats:   domain.CacheStats{Hits: 1, Misses: 1, Entries: 1},
+		},
+		{
+			name: "Unknown codes are cached",
+			opts: cache.Options{Size: 2, TTL: time.Minute, NegativeTTL: time.Second},
+			steps: []step{
+				{code: "unknown", expectedErr: repository.ErrNotFound},
+				{code: "unknown", expectedErr: repository.ErrNotFound},
+				{advance: time.Second, code: "unknown", expectedErr: repository.ErrNotFound},
+			},
+			wantLookups: []string{"unknown", "unknown"},
+			wantStats:   domain.CacheStats{Hits: 1, Misses: 2, Entries: 1},
+		},
+		{
+			name: "Unknown codes are not cached without negative TTL",
+			opts: cache.Options{Size: 2, TTL: time.Minute},
+			steps: []step{
+				{code: "unknown", expectedErr: repository.ErrNotFound},
+				{code: "unknown", expectedErr: repository.ErrNotFound},
+			},
+			wantLookups: []string{"unknown", "unknown"},
+			wantStats:   domain.CacheStats{Hits: 0, Misses: 2, Entries: 0},
+		},
+		{
+			name:        "Least recently used code is evicted",
+			opts:        cache.Options{Size: 2, TTL: time.Minute},
+			steps:       []step{{code: "a"}, {code: "b"}, {code: "a"}, {code: "c"}, {code: "a"}, {code: "b"}},
+			wantLookups: []string{"a", "b", "c", "b"},
+			wantStats:   domain.CacheStats{Hits: 2, Misses: 4, Entries: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			backend := &counting{Backend: memory.New()}
+			for _, code := range []string{"a", "b", "c"} {
+				if err := backend.Insert(ctx, domain.Coupon{ID: code, Code: code}); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+
+			clock := &clock{now: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+			tc.opts.Now = clock.Now
+			repo := cache.New(backend, tc.opts)
+
+			for _, step := range tc.steps {
+				clock.now = clock.now.Add(step.advance)
+				coupon, err := repo.FindByCode(ctx, step.code)
+				if !errors.Is(err, step.expectedErr) {
+					t.Errorf("expected err to be %v, got %v", step.expectedErr, err)
+				}
+				if err == nil && coupon.Code != step.code {
+					t.Errorf("expected code to be %v, got %v", step.code, coupon.Code)
+				}
+			}
+
+			if !reflect.DeepEqual(backend.lookups, tc.wantLookups) {
+				t.Errorf("expected lookups to be %v, got %v", tc.wantLookups, backend.lookups)
+			}
+			if stats := repo.Stats(); stats != tc.wantStats {
+				t.Errorf("expected stats to be %+v, got %+v", tc.wantStats, stats)
+			}
+		})
+	}
+}
+
+func TestFindByCodes(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestFindByCodes in long mode.")
+	}
+
+	ctx := context.Background()
+	backend := &counting{Backend: memory.New()}
+	for _, code := range []string{"a", "b"} {
+		if err := backend.Insert(ctx, domain.Coupon{ID: code, Code: code}); err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+
+	repo := cache.New(backend, cache.Options{Size: 8, TTL: time.Minute, NegativeTTL: time.Minute})
+
+	if _, err := repo.FindByCode(ctx, "a"); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	coupons, err := repo.FindByCodes(ctx, []string{"b", "unknown", "a"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if got := codes(coupons); !reflect.DeepEqual(got, []string{"b", "a"}) {
+		t.Errorf("expected codes to be %v, got %v", []string{"b", "a"}, got)
+	}
+
+	// Every code is cached now, the unknown one included.
+	coupons, err = repo.FindByCodes(ctx, []string{"unknown", "a", "b"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if got := codes(coupons); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected codes to be %v, got %v", []string{"a", "b"}, got)
+	}
+
+	wantLookups := []string{"a", "b", "unknown"}
+	if !reflect.DeepEqual(backend.lookups, wantLookups) {
+		t.Errorf("expected lookups to be %v, got %v", wantLookups, backend.lookups)
+	}
+	wantStats := domain.CacheStats{Hits: 4, Misses: 3, Entries: 3}
+	if stats := repo.Stats(); stats != wantStats {
+		t.Errorf("expected stats to be %+v, got %+v", wantStats, stats)
+	}
+}
+
+func TestInvalidation(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestInvalidation in long mode.")
+	}
+
+	ctx := context.Background()
+	repo := cache.New(memory.New(), cache.Options{Size: 8, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	expectDiscount := func(want int) {
+		t.Helper()
+		coupon, err := repo.FindByCode(ctx, "code")
+		if err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+		if coupon.Discount != want {
+			t.Errorf("expected discount to be %v, got %v", want, coupon.Discount)
+		}
+	}
+
+	if _, err := repo.FindByCode(ctx, "code"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected err to be %v, got %v", repository.ErrNotFound, err)
+	}
+
+	if err := repo.Insert(ctx, domain.Coupon{ID: "1", Code: "code", Discount: 10}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expectDiscount(10)
+
+	if err := repo.Save(ctx, domain.Coupon{ID: "1", Code: "code", Discount: 20}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expectDiscount(20)
+
+	if err := repo.Update(ctx, domain.Coupon{ID: "1", Code: "code", Discount: 30, Version: 2}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	expectDiscount(30)
+
+	if err := repo.Delete(ctx, "code", 3); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if _, err := repo.FindByCode(ctx, "code"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrNotFound, err)
+	}
+}
+
+func TestLookupRacingWrite(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestLookupRacingWrite in long mode.")
+	}
+
+	ctx := context.Background()
+	backend := &counting{Backend: memory.New()}
+	if err := backend.Insert(ctx, domain.Coupon{ID: "1", Code: "code", Discount: 10}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	repo := cache.New(backend, cache.Options{Size: 8, TTL: time.Hour})
+
+	// The coupon is changed after the lookup read it but before it is cached.
+	backend.afterLookup = func() {
+		backend.afterLookup = nil
+		if err := repo.Save(ctx, domain.Coupon{ID: "1", Code: "code", Discount: 20}); err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+	if _, err := repo.FindByCode(ctx, "code"); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	coupon, err := repo.FindByCode(ctx, "code")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if coupon.Discount != 20 {
+		t.Errorf("expected discount to be %v, got %v", 20, coupon.Discount)
+	}
+}
+
+func TestFindByCustomerNotCached(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestFindByCustomerNotCached in long mode.")
+	}
+
+	ctx := context.Background()
+	backend := &counting{Backend: memory.New()}
+	repo := cache.New(backend, cache.Options{Size: 8, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	if err := repo.Insert(ctx, domain.Coupon{ID: "1", Code: "first", Assignment: domain.Assignment{CustomerIDs: []string{"alice"}}}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if _, err := repo.FindByCustomer(ctx, domain.Customer{ID: "alice"}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	// A coupon assigned behind the cache, such as by another instance, is
+	// found right away.
+	if err := backend.Insert(ctx, domain.Coupon{ID: "2", Code: "second", Assignment: domain.Assignment{CustomerIDs: []string{"alice"}}}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	coupons, err := repo.FindByCustomer(ctx, domain.Customer{ID: "alice"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	if want := []string{"first", "second"}; !reflect.DeepEqual(codes(coupons), want) {
+		t.Errorf("expected codes to be %v, got %v", want, codes(coupons))
+	}
+	if want := []string{"alice", "alice"}; !reflect.DeepEqual(backend.customers, want) {
+		t.Errorf("expected customer lookups to be %v, got %v", want, backend.customers)
+	}
+}
+
+func codes(coupons []domain.Coupon) []string {
+	codes := make([]string, 0, len(coupons))
+	for _, coupon := range coupons {
+		codes = append(codes, coupon.Code)
+	}
+	return codes
+}
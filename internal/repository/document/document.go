@@ -0,0 +1,31 @@
+// Package document encodes coupons as JSON documents for the repositories
+// that store them whole.
+package document
+
+import (
+	"encoding/json"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+// record is the stored form of a coupon. Params is left out when empty, as
+// a null document would not decode back to nil.
+type record struct {
+	domain.Coupon
+	Params json.RawMessage `json:",omitempty"`
+}
+
+func Encode(coupon domain.Coupon) ([]byte, error) {
+	return json.Marshal(record{Coupon: coupon, Params: coupon.Params})
+}
+
+func Decode(data []byte) (*domain.Coupon, error) {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+
+	coupon := rec.Coupon
+	coupon.Params = rec.Params
+	return &coupon, nil
+}
@@ -0,0 +1,587 @@
+package memory
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/document"
+)
+
+const (
+	snapshotFile = "snapshot"
+	logFile      = "wal"
+	// oldLogFile is the log moved aside by a compaction until the snapshot
+	// covering it is written.
+	oldLogFile = "wal.old"
+
+	// headerSize is the length and the CRC-32C checksum of the payload that
+	// precede every record.
+	headerSize = 8
+	maxRecord  = 16 << 20
+)
+
+var (
+	ErrCorrupt         = errors.New("corrupt log record")
+	ErrInvalidSyncMode = errors.New("invalid sync mode")
+	crcTable           = crc32.MakeTable(crc32.Castagnoli)
+
+	// errShortHeader and errShortPayload report a record running past the
+	// end of the file.
+	errShortHeader  = errors.New("record header runs past the end of the file")
+	errShortPayload = errors.New("record payload runs past the end of the file")
+)
+
+// SyncMode decides when appended log records are flushed to disk.
+type SyncMode string
+
+const (
+	// SyncAlways flushes every record before Save returns.
+	SyncAlways SyncMode = "always"
+	// SyncInterval flushes in the background every SyncInterval, so a crash
+	// loses at most that much.
+	SyncInterval SyncMode = "interval"
+	// SyncNever leaves flushing to the operating system.
+	SyncNever SyncMode = "never"
+)
+
+type DurableOptions struct {
+	Sync         SyncMode
+	SyncInterval time.Duration
+	// CompactAfter is the number of log records after which a snapshot is
+	// written in the background and the log is truncated. Zero only compacts
+	// when opening.
+	CompactAfter int
+	// Logger receives the errors of background compactions. Nil discards
+	// them.
+	Logger *zap.SugaredLogger
+}
+
+// appendLog is the part of *os.File the log is written through.
+type appendLog interface {
+	io.Writer
+	io.Seeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// tombstone is the log record of a deleted coupon. Coupon documents have no
+// Deleted field, which tells the two apart.
+type tombstone struct {
+	Code    string
+	Deleted bool
+}
+
+// durability persists the coupons of a repository as a snapshot followed
+// by a write-ahead log of every change since.
+type durability struct {
+	dir     string
+	opts    DurableOptions
+	log     appendLog
+	records int
+	// closed makes saves after Close fail rather than go unlogged.
+	closed bool
+	// failed is set when a failed append could not be rolled back, after
+	// which the end of the log is unknown and every save fails.
+	failed error
+	// compacting is set while a snapshot is written in the background, and
+	// rotated while the old log it is to cover exists.
+	compacting  bool
+	rotated     bool
+	compactions *sync.WaitGroup
+
+	// mu guards the log file between Save and the background sync.
+	mu   *sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// OpenDurable returns a repository persisted in dir, created if needed. The
+// snapshot and the log are replayed first; a torn last log record, left by a
+// crash while appending, is truncated.
+func OpenDurable(dir string, opts DurableOptions) (*Repository, error) {
+	switch opts.Sync {
+	case SyncAlways, SyncNever:
+	case SyncInterval:
+		if opts.SyncInterval <= 0 {
+			return nil, ErrInvalidSyncMode
+		}
+	default:
+		return nil, ErrInvalidSyncMode
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	repo := New()
+
+	if err := replayFile(filepath.Join(dir, snapshotFile), repo.entries, false); err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+
+	// A log moved aside by an interrupted compaction precedes the current
+	// one. It was flushed before it was moved, so it cannot be torn.
+	if err := replayFile(filepath.Join(dir, oldLogFile), repo.entries, false); err != nil {
+		return nil, fmt.Errorf("old log: %w", err)
+	}
+
+	if err := replayFile(filepath.Join(dir, logFile), repo.entries, true); err != nil {
+		return nil, fmt.Errorf("log: %w", err)
+	}
+
+	if opts.Logger == nil {
+		opts.Logger = zap.NewNop().Sugar()
+	}
+
+	d := &durability{dir: dir, opts: opts, mu: &sync.Mutex{}, compactions: &sync.WaitGroup{}}
+
+	// Compacting on open leaves an empty log, so records appended from now
+	// on never follow a truncated tail.
+	if err := d.compact(repo.entries); err != nil {
+		return nil, err
+	}
+
+	if opts.Sync == SyncInterval {
+		d.stop = make(chan struct{})
+		d.done = make(chan struct{})
+		go d.syncLoop()
+	}
+
+	repo.durability = d
+	return repo, nil
+}
+
+// Close stops the background sync and flushes and closes the log. It is a
+// no-op for repositories that are not durable.
+func (r *Repository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d := r.durability
+	if d == nil || d.closed {
+		return nil
+	}
+
+	if d.stop != nil {
+		close(d.stop)
+		<-d.done
+	}
+
+	d.compactions.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+
+	if err := d.log.Sync(); err != nil {
+		d.log.Close()
+		return err
+	}
+	return d.log.Close()
+}
+
+// append writes the coupon to the log.
+func (d *durability) append(coupon domain.Coupon) error {
+	payload, err := document.Encode(coupon)
+	if err != nil {
+		return err
+	}
+	return d.write(payload)
+}
+
+// appendDelete writes a tombstone for the code to the log.
+func (d *durability) appendDelete(code string) error {
+	payload, err := json.Marshal(tombstone{Code: code, Deleted: true})
+	if err != nil {
+		return err
+	}
+	return d.write(payload)
+}
+
+// write appends a record to the log, flushing it if every record is to be
+// synced. A record that fails to be written or flushed is cut off again, so
+// it neither resurfaces on replay nor leaves torn bytes that later records
+// would follow.
+func (d *durability) write(payload []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return repository.Unavailable(os.ErrClosed)
+	}
+	if d.failed != nil {
+		return repository.Unavailable(d.failed)
+	}
+
+	// A write to the log fails only when the disk or file does.
+	offset, err := d.log.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return repository.Unavailable(err)
+	}
+
+	if _, err := d.log.Write(frame(payload)); err != nil {
+		return repository.Unavailable(d.rollback(offset, err))
+	}
+
+	if d.opts.Sync == SyncAlways {
+		if err := d.log.Sync(); err != nil {
+			return repository.Unavailable(d.rollback(offset, err))
+		}
+	}
+
+	d.records++
+	return nil
+}
+
+// rollback cuts the log back to offset after err and flushes the cut. If
+// that fails too, the log is marked as failed.
+func (d *durability) rollback(offset int64, err error) error {
+	rollbackErr := d.log.Truncate(offset)
+	if rollbackErr == nil {
+		_, rollbackErr = d.log.Seek(offset, io.SeekStart)
+	}
+	if rollbackErr == nil {
+		rollbackErr = d.log.Sync()
+	}
+
+	if rollbackErr != nil {
+		d.failed = fmt.Errorf("log could not be rolled back: %w", errors.Join(err, rollbackErr))
+		return d.failed
+	}
+	return err
+}
+
+func (d *durability) due() bool {
+	return d.opts.CompactAfter > 0 && d.records >= d.opts.CompactAfter
+}
+
+// compact writes every entry to a new snapshot and starts an empty log. The
+// snapshot replaces the old one atomically, and replaying the old log on top
+// of it is harmless, so a crash at any point loses nothing.
+func (d *durability) compact(entries map[string]domain.Coupon) error {
+	if err := d.snapshot(entries); err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(filepath.Join(d.dir, logFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.log != nil {
+		d.log.Close()
+	}
+	d.log = log
+	d.records = 0
+	d.failed = nil
+
+	return nil
+}
+
+// compactInBackground moves the log aside and starts writing a snapshot of
+// the entries, which covers exactly the old log, without holding up saves.
+// It must be called with the lock of the repository held, and does nothing
+// while the previous compaction is running.
+func (d *durability) compactInBackground(entries map[string]domain.Coupon) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.compacting || d.closed || d.failed != nil {
+		return
+	}
+
+	// The old log left by a failed compaction is covered by the next
+	// snapshot too, so the log is only moved aside again once it is gone.
+	if !d.rotated {
+		if err := d.rotate(); err != nil {
+			d.opts.Logger.Errorw("rotating the coupon log failed", "error", err)
+			return
+		}
+	}
+
+	d.compacting = true
+	d.compactions.Add(1)
+	go func(entries map[string]domain.Coupon) {
+		defer d.compactions.Done()
+
+		err := d.snapshot(entries)
+
+		d.mu.Lock()
+		d.compacting = false
+		d.rotated = d.rotated && err != nil
+		d.mu.Unlock()
+
+		if err != nil {
+			d.opts.Logger.Errorw("compacting the coupon log failed", "error", err)
+		}
+	}(maps.Clone(entries))
+}
+
+// rotate flushes the log, moves it aside as the old log and starts an empty
+// one. It must be called with mu held.
+func (d *durability) rotate() error {
+	if err := d.log.Sync(); err != nil {
+		return err
+	}
+
+	current, old := filepath.Join(d.dir, logFile), filepath.Join(d.dir, oldLogFile)
+	if err := os.Rename(current, old); err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(current, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		// Appends keep going to the moved file, which has to be the log
+		// again for them to be replayed.
+		if renameErr := os.Rename(old, current); renameErr != nil {
+			d.failed = errors.Join(err, renameErr)
+		}
+		return err
+	}
+
+	d.log.Close()
+	d.log = log
+	d.records = 0
+	d.rotated = true
+
+	// Records appended from now on are lost if the directory is not synced
+	// and the new log disappears in a crash.
+	if err := syncDir(d.dir); err != nil {
+		d.failed = err
+		return err
+	}
+	return nil
+}
+
+// snapshot replaces the snapshot with one of the entries and removes the
+// old log, which the snapshot covers.
+func (d *durability) snapshot(entries map[string]domain.Coupon) error {
+	tmp := filepath.Join(d.dir, snapshotFile+".tmp")
+	if err := writeSnapshot(tmp, entries); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, filepath.Join(d.dir, snapshotFile)); err != nil {
+		return err
+	}
+
+	if err := syncDir(d.dir); err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(d.dir, oldLogFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (d *durability) syncLoop() {
+	defer close(d.done)
+
+	ticker := time.NewTicker(d.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			_ = d.log.Sync()
+			d.mu.Unlock()
+		}
+	}
+}
+
+func writeSnapshot(path string, entries map[string]domain.Coupon) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, coupon := range entries {
+		payload, err := document.Encode(coupon)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(frame(payload)); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// replayFile saves every record of the file into entries. A missing file is
+// empty. If truncateTorn is set, an incomplete or mismatching last record is
+// cut off the file; anywhere else, or in a snapshot, it is ErrCorrupt.
+func replayFile(path string, entries map[string]domain.Coupon, truncateTorn bool) error {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	size := info.Size()
+
+	r := bufio.NewReader(file)
+	var offset int64
+	for offset < size {
+		payload, n, err := readRecord(r)
+		if err != nil {
+			torn, tornErr := tornRecord(file, offset, n, size, err)
+			if tornErr != nil {
+				return tornErr
+			}
+			if !truncateTorn || !torn {
+				return fmt.Errorf("%w at offset %d", ErrCorrupt, offset)
+			}
+			if err := file.Truncate(offset); err != nil {
+				return err
+			}
+			return file.Sync()
+		}
+
+		if err := replayRecord(payload, entries); err != nil {
+			return fmt.Errorf("%w at offset %d: %v", ErrCorrupt, offset, err)
+		}
+
+		offset += n
+	}
+
+	return nil
+}
+
+// tornRecord tells whether the record at offset, which failed to be read
+// with err, is the torn last record of the file of the given size. Only a
+// record cut short by a crash while appending is: it either ends exactly at
+// the end of the file, or runs past it with nothing but its own bytes after
+// its header. A corrupt length running past valid records is not.
+func tornRecord(file *os.File, offset, n, size int64, err error) (bool, error) {
+	switch {
+	case errors.Is(err, errShortHeader):
+		return true, nil
+	case errors.Is(err, errShortPayload):
+		rest := make([]byte, size-offset-headerSize)
+		if _, err := file.ReadAt(rest, offset+headerSize); err != nil {
+			return false, err
+		}
+		return !containsRecord(rest), nil
+	default:
+		return offset+n == size, nil
+	}
+}
+
+// containsRecord tells whether a complete record with a matching checksum
+// starts anywhere in data. Records are never empty, which rules out runs of
+// zeros.
+func containsRecord(data []byte) bool {
+	for start := 0; start+headerSize < len(data); start++ {
+		length := binary.LittleEndian.Uint32(data[start : start+4])
+		end := start + headerSize + int(length)
+		if length == 0 || length > maxRecord || end > len(data) {
+			continue
+		}
+		if crc32.Checksum(data[start+headerSize:end], crcTable) == binary.LittleEndian.Uint32(data[start+4:start+8]) {
+			return true
+		}
+	}
+	return false
+}
+
+// replayRecord applies a coupon or a tombstone to entries.
+func replayRecord(payload []byte, entries map[string]domain.Coupon) error {
+	var deleted tombstone
+	if err := json.Unmarshal(payload, &deleted); err != nil {
+		return err
+	}
+	if deleted.Deleted {
+		delete(entries, deleted.Code)
+		return nil
+	}
+
+	coupon, err := document.Decode(payload)
+	if err != nil {
+		return err
+	}
+	entries[coupon.Code] = *coupon
+	return nil
+}
+
+// readRecord returns the payload of the next record and the number of
+// bytes the record takes up.
+func readRecord(r io.Reader) ([]byte, int64, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, 0, errShortHeader
+	}
+
+	length := binary.LittleEndian.Uint32(header[0:4])
+	checksum := binary.LittleEndian.Uint32(header[4:8])
+	if length > maxRecord {
+		return nil, headerSize, ErrCorrupt
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, headerSize, errShortPayload
+	}
+
+	n := int64(headerSize) + int64(length)
+	if crc32.Checksum(payload, crcTable) != checksum {
+		return nil, n, ErrCorrupt
+	}
+
+	return payload, n, nil
+}
+
+func frame(payload []byte) []byte {
+	record := make([]byte, headerSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(payload, crcTable))
+	copy(record[headerSize:], payload)
+	return record
+}
+
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+// faultyLog fails the next write after writing half of it, or the next sync,
+// or the truncate rolling either back.
+type faultyLog struct {
+	appendLog
+	failWrite    bool
+	failSync     bool
+	failTruncate bool
+}
+
+func (f *faultyLog) Write(p []byte) (int, error) {
+	if f.failWrite {
+		f.failWrite = false
+		n, _ := f.appendLog.Write(p[:len(p)/2])
+		return n, errors.New("disk full")
+	}
+	return f.appendLog.Write(p)
+}
+
+func (f *faultyLog) Sync() error {
+	if f.failSync {
+		f.failSync = false
+		return errors.New("sync failed")
+	}
+	return f.appendLog.Sync()
+}
+
+func (f *faultyLog) Truncate(size int64) error {
+	if f.failTruncate {
+		return errors.New("truncate failed")
+	}
+	return f.appendLog.Truncate(size)
+}
+
+func TestDurableFailedAppend(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableFailedAppend in long mode.")
+	}
+
+	tests := []struct {
+		name  string
+		fault faultyLog
+		// wantFailed is set if saves keep failing after the failed one.
+		wantFailed bool
+	}{
+		{name: "torn write", fault: faultyLog{failWrite: true}},
+		{name: "failed sync", fault: faultyLog{failSync: true}},
+		{name: "failed rollback", fault: faultyLog{failWrite: true, failTruncate: true}, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			opts := DurableOptions{Sync: SyncAlways}
+
+			repo, err := OpenDurable(dir, opts)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			if err := repo.Save(ctx, domain.Coupon{ID: "before", Code: "before"}); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			fault := tt.fault
+			fault.appendLog = repo.durability.log
+			repo.durability.log = &fault
+
+			err = repo.Save(ctx, domain.Coupon{ID: "failed", Code: "failed"})
+			if !errors.Is(err, repository.ErrUnavailable) {
+				t.Fatalf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+			}
+
+			err = repo.Save(ctx, domain.Coupon{ID: "after", Code: "after"})
+			if tt.wantFailed {
+				if !errors.Is(err, repository.ErrUnavailable) {
+					t.Errorf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if err := repo.Close(); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			repo, err = OpenDurable(dir, opts)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			defer repo.Close()
+
+			for code, wantErr := range map[string]error{"before": nil, "failed": repository.ErrNotFound, "after": nil} {
+				if _, err := repo.FindByCode(ctx, code); !errors.Is(err, wantErr) {
+					t.Errorf("expected err for %q to be %v, got %v", code, wantErr, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package memory_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/repositorytest"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func openDurable(t *testing.T, dir string, opts memory.DurableOptions) *memory.Repository {
+	repo, err := memory.OpenDurable(dir, opts)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	return repo
+}
+
+func newDurableRepository(t *testing.T) service.Repository {
+	repo := openDurable(t, t.TempDir(), memory.DurableOptions{Sync: memory.SyncAlways, CompactAfter: 2})
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestDurableRepository(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableRepository in long mode.")
+	}
+
+	repositorytest.Run(t, newDurableRepository)
+}
+
+func TestDurableReopen(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableReopen in long mode.")
+	}
+
+	tests := []struct {
+		name string
+		opts memory.DurableOptions
+	}{
+		{name: "sync always", opts: memory.DurableOptions{Sync: memory.SyncAlways}},
+		{name: "sync on interval", opts: memory.DurableOptions{Sync: memory.SyncInterval, SyncInterval: time.Millisecond}},
+		{name: "sync never", opts: memory.DurableOptions{Sync: memory.SyncNever}},
+		{name: "compacts every record", opts: memory.DurableOptions{Sync: memory.SyncAlways, CompactAfter: 1}},
+		{name: "compacts every other record", opts: memory.DurableOptions{Sync: memory.SyncAlways, CompactAfter: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			repo := openDurable(t, dir, tt.opts)
+			saved := []domain.Coupon{
+				{ID: "id1", Code: "first", Discount: 10},
+				{ID: "id2", Code: "second", Discount: 20},
+				{ID: "id3", Code: "first", Discount: 30},
+			}
+			for _, coupon := range saved {
+				if err := repo.Save(ctx, coupon); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+			if err := repo.Save(ctx, domain.Coupon{ID: "id4", Code: "deleted"}); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if err := repo.Delete(ctx, "deleted", 1); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if err := repo.Close(); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			repo = openDurable(t, dir, tt.opts)
+			defer repo.Close()
+
+			for _, want := range saved[1:] {
+				got, err := repo.FindByCode(ctx, want.Code)
+				if err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+				if got.ID != want.ID || got.Discount != want.Discount {
+					t.Errorf("expected coupon to be %v, got %v", want, *got)
+				}
+			}
+
+			if _, err := repo.FindByCode(ctx, "deleted"); !errors.Is(err, repository.ErrNotFound) {
+				t.Errorf("expected err to be %v, got %v", repository.ErrNotFound, err)
+			}
+		})
+	}
+}
+
+func TestDurableTornRecord(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableTornRecord in long mode.")
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(log []byte) []byte
+		wantErr error
+	}{
+		{
+			name:    "truncated header",
+			corrupt: func(log []byte) []byte { return append(log, 0x10, 0x00) },
+		},
+		{
+			name:    "truncated payload",
+			corrupt: func(log []byte) []byte { return log[:len(log)-3] },
+		},
+		{
+			name: "checksum mismatch in last record",
+			corrupt: func(log []byte) []byte {
+				log[len(log)-2] ^= 0xff
+				return log
+			},
+		},
+		{
+			name: "checksum mismatch before last record",
+			corrupt: func(log []byte) []byte {
+				log[10] ^= 0xff
+				return log
+			},
+			wantErr: memory.ErrCorrupt,
+		},
+		{
+			name: "length running past the end before last record",
+			corrupt: func(log []byte) []byte {
+				binary.LittleEndian.PutUint32(log[0:4], 1<<20)
+				return log
+			},
+			wantErr: memory.ErrCorrupt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			opts := memory.DurableOptions{Sync: memory.SyncAlways}
+
+			repo := openDurable(t, dir, opts)
+			for _, code := range []string{"kept", "torn"} {
+				if err := repo.Save(ctx, domain.Coupon{ID: code, Code: code, Discount: 10}); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+			if err := repo.Close(); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			path := filepath.Join(dir, "wal")
+			log, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if err := os.WriteFile(path, tt.corrupt(log), 0o644); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			repo, err = memory.OpenDurable(dir, opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected err to be %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			defer repo.Close()
+
+			if _, err := repo.FindByCode(ctx, "kept"); err != nil {
+				t.Errorf("expected err to be nil, got %v", err)
+			}
+
+			// The torn record is dropped, and appending after it works.
+			if err := repo.Save(ctx, domain.Coupon{ID: "next", Code: "next", Discount: 10}); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if err := repo.Close(); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			repo = openDurable(t, dir, opts)
+			defer repo.Close()
+
+			for _, code := range []string{"kept", "next"} {
+				if _, err := repo.FindByCode(ctx, code); err != nil {
+					t.Errorf("expected err for %q to be nil, got %v", code, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDurableInterruptedCompaction(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableInterruptedCompaction in long mode.")
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	opts := memory.DurableOptions{Sync: memory.SyncAlways}
+
+	repo := openDurable(t, dir, opts)
+	if err := repo.Save(ctx, domain.Coupon{ID: "id1", Code: "old"}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	// A crash after the log was moved aside, but before the snapshot
+	// covering it was written, leaves both logs behind.
+	if err := os.Rename(filepath.Join(dir, "wal"), filepath.Join(dir, "wal.old")); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	repo = openDurable(t, dir, opts)
+	if err := repo.Save(ctx, domain.Coupon{ID: "id2", Code: "new"}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "wal.old")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected old log to be removed, got %v", err)
+	}
+
+	repo = openDurable(t, dir, opts)
+	defer repo.Close()
+
+	for _, code := range []string{"old", "new"} {
+		if _, err := repo.FindByCode(ctx, code); err != nil {
+			t.Errorf("expected err for %q to be nil, got %v", code, err)
+		}
+	}
+}
+
+func TestDurableFailedCompaction(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableFailedCompaction in long mode.")
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	core, logs := observer.New(zap.ErrorLevel)
+	opts := memory.DurableOptions{Sync: memory.SyncAlways, CompactAfter: 1, Logger: zap.New(core).Sugar()}
+
+	repo := openDurable(t, dir, opts)
+
+	// The snapshot cannot be written while a directory is in its way.
+	tmp := filepath.Join(dir, "snapshot.tmp")
+	if err := os.Mkdir(tmp, 0o755); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	codes := []string{"first", "second", "third"}
+	for _, code := range codes {
+		if err := repo.Save(ctx, domain.Coupon{ID: code, Code: code}); err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	if logs.FilterMessage("compacting the coupon log failed").Len() == 0 {
+		t.Errorf("expected the failed compaction to be logged, got %v", logs.All())
+	}
+
+	if err := os.Remove(tmp); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	repo = openDurable(t, dir, opts)
+	defer repo.Close()
+
+	for _, code := range codes {
+		if _, err := repo.FindByCode(ctx, code); err != nil {
+			t.Errorf("expected err for %q to be nil, got %v", code, err)
+		}
+	}
+}
+
+func TestDurableClosed(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDurableClosed in long mode.")
+	}
+
+	repo := openDurable(t, t.TempDir(), memory.DurableOptions{Sync: memory.SyncAlways})
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	err := repo.Save(context.Background(), domain.Coupon{ID: "id1", Code: "code"})
+	if !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+	}
+}
+
+func TestOpenDurableInvalidSync(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestOpenDurableInvalidSync in long mode.")
+	}
+
+	tests := []struct {
+		name string
+		opts memory.DurableOptions
+	}{
+		{name: "unknown mode", opts: memory.DurableOptions{Sync: "sometimes"}},
+		{name: "interval without duration", opts: memory.DurableOptions{Sync: memory.SyncInterval}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := memory.OpenDurable(t.TempDir(), tt.opts); !errors.Is(err, memory.ErrInvalidSyncMode) {
+				t.Errorf("expected err to be %v, got %v", memory.ErrInvalidSyncMode, err)
+			}
+		})
+	}
+}
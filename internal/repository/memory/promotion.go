@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+type Promotions struct {
+	entries map[string]domain.Promotion
+	mu      *sync.Mutex
+}
+
+func NewPromotions() *Promotions {
+	return &Promotions{
+		entries: make(map[string]domain.Promotion),
+		mu:      &sync.Mutex{},
+	}
+}
+
+func (r *Promotions) FindAll(_ context.Context) ([]domain.Promotion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	promotions := make([]domain.Promotion, 0, len(r.entries))
+	for _, promotion := range r.entries {
+		promotions = append(promotions, promotion)
+	}
+
+	sort.Slice(promotions, func(i, j int) bool {
+		if promotions[i].Name != promotions[j].Name {
+			return promotions[i].Name < promotions[j].Name
+		}
+		return promotions[i].ID < promotions[j].ID
+	})
+
+	return promotions, nil
+}
+
+func (r *Promotions) Save(_ context.Context, promotion domain.Promotion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[promotion.ID] = promotion
+	return nil
+}
@@ -0,0 +1,65 @@
+package memory_test
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+)
+
+func TestPromotions(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPromotions in long mode.")
+	}
+
+	summer := domain.Promotion{Coupon: domain.Coupon{ID: "p1", Discount: 5}, Name: "summer", Active: true}
+	autumn := domain.Promotion{Coupon: domain.Coupon{ID: "p2", Discount: 10}, Name: "autumn", Active: true}
+	updated := domain.Promotion{Coupon: domain.Coupon{ID: "p1", Discount: 5}, Name: "summer", Active: false}
+
+	type testCase struct {
+		name string
+		save []domain.Promotion
+		want []domain.Promotion
+	}
+
+	testCases := []testCase{
+		{
+			name: "Empty repository",
+			want: []domain.Promotion{},
+		},
+		{
+			name: "Promotions sorted by name",
+			save: []domain.Promotion{summer, autumn},
+			want: []domain.Promotion{autumn, summer},
+		},
+		{
+			name: "Saving replaces promotion with same ID",
+			save: []domain.Promotion{updated},
+			want: []domain.Promotion{autumn, updated},
+		},
+	}
+
+	ctx := context.Background()
+	repo := memory.NewPromotions()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, promotion := range tc.save {
+				if err := repo.Save(ctx, promotion); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+
+			promotions, err := repo.FindAll(ctx)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if !reflect.DeepEqual(tc.want, promotions) {
+				t.Errorf("expected promotions to be %v, got %v", tc.want, promotions)
+			}
+		})
+	}
+}
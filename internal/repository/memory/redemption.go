@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+type redemptionKey struct {
+	customerID string
+	code       string
+}
+
+type Redemptions struct {
+	entries map[redemptionKey][]domain.Redemption
+	mu      *sync.Mutex
+}
+
+func NewRedemptions() *Redemptions {
+	return &Redemptions{
+		entries: make(map[redemptionKey][]domain.Redemption),
+		mu:      &sync.Mutex{},
+	}
+}
+
+func (r *Redemptions) Count(_ context.Context, customerID, code string, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.count(redemptionKey{customerID: customerID, code: code}, since), nil
+}
+
+func (r *Redemptions) Record(_ context.Context, redemption domain.Redemption, windows []domain.UsageWindow) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := redemptionKey{customerID: redemption.CustomerID, code: redemption.Code}
+	for _, window := range windows {
+		if r.count(key, window.Since) >= window.Max {
+			return false, nil
+		}
+	}
+
+	r.entries[key] = append(r.entries[key], redemption)
+	return true, nil
+}
+
+func (r *Redemptions) HasRedeemed(_ context.Context, customerID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.entries {
+		if key.customerID == customerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *Redemptions) count(key redemptionKey, since time.Time) int {
+	count := 0
+	for _, redemption := range r.entries[key] {
+		if !redemption.RedeemedAt.Before(since) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,20 @@
+package memory_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/repositorytest"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func TestRedemptions(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedemptions in long mode.")
+	}
+
+	repositorytest.Redemptions(t, func(*testing.T) service.RedemptionRepository {
+		return memory.NewRedemptions()
+	})
+}
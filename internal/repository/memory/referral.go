@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+type Referrals struct {
+	codes       map[string]string
+	referrers   map[string]string
+	referrals   map[string]domain.Referral
+	firstOrders map[string]string
+	mu          *sync.Mutex
+}
+
+func NewReferrals() *Referrals {
+	return &Referrals{
+		codes:       make(map[string]string),
+		referrers:   make(map[string]string),
+		referrals:   make(map[string]domain.Referral),
+		firstOrders: make(map[string]string),
+		mu:          &sync.Mutex{},
+	}
+}
+
+func (r *Referrals) SaveCode(_ context.Context, referrerID, code string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.codes[referrerID]; ok {
+		return existing, nil
+	}
+
+	r.codes[referrerID] = code
+	r.referrers[code] = referrerID
+	return code, nil
+}
+
+func (r *Referrals) FindCode(_ context.Context, referrerID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	code, ok := r.codes[referrerID]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return code, nil
+}
+
+func (r *Referrals) FindReferrer(_ context.Context, code string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	referrerID, ok := r.referrers[code]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return referrerID, nil
+}
+
+func (r *Referrals) Add(_ context.Context, referral domain.Referral) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.referrals[referral.RefereeID]; ok {
+		return false, nil
+	}
+
+	r.referrals[referral.RefereeID] = referral
+	return true, nil
+}
+
+func (r *Referrals) Remove(_ context.Context, refereeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.referrals, refereeID)
+	return nil
+}
+
+func (r *Referrals) FindByReferrer(_ context.Context, referrerID string) ([]domain.Referral, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	referrals := make([]domain.Referral, 0)
+	for _, referral := range r.referrals {
+		if referral.ReferrerID == referrerID {
+			referrals = append(referrals, referral)
+		}
+	}
+
+	sort.Slice(referrals, func(i, j int) bool {
+		return referrals[i].RefereeID < referrals[j].RefereeID
+	})
+
+	return referrals, nil
+}
+
+func (r *Referrals) Complete(_ context.Context, refereeID, rewardCode string, maxRewards int) (domain.Referral, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	referral, ok := r.referrals[refereeID]
+	if !ok {
+		return domain.Referral{}, false, repository.ErrNotFound
+	}
+
+	if referral.Status != domain.ReferralPending {
+		return referral, false, nil
+	}
+
+	if maxRewards > 0 && r.rewarded(referral.ReferrerID) >= maxRewards {
+		referral.Status = domain.ReferralCapped
+		r.referrals[refereeID] = referral
+		return referral, false, nil
+	}
+
+	referral.Status = domain.ReferralRewarded
+	referral.RewardCode = rewardCode
+	r.referrals[refereeID] = referral
+	return referral, true, nil
+}
+
+func (r *Referrals) Reopen(_ context.Context, refereeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	referral, ok := r.referrals[refereeID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	referral.Status = domain.ReferralPending
+	referral.RewardCode = ""
+	r.referrals[refereeID] = referral
+	return nil
+}
+
+func (r *Referrals) SaveFirstOrder(_ context.Context, customerID, orderID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.firstOrders[customerID]; ok {
+		return existing, nil
+	}
+
+	r.firstOrders[customerID] = orderID
+	return orderID, nil
+}
+
+func (r *Referrals) FindFirstOrder(_ context.Context, customerID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	orderID, ok := r.firstOrders[customerID]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return orderID, nil
+}
+
+func (r *Referrals) rewarded(referrerID string) int {
+	rewarded := 0
+	for _, referral := range r.referrals {
+		if referral.ReferrerID == referrerID && referral.Status == domain.ReferralRewarded {
+			rewarded++
+		}
+	}
+	return rewarded
+}
@@ -0,0 +1,141 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+)
+
+func TestReferralsSaveCode(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReferralsSaveCode in long mode.")
+	}
+
+	ctx := context.Background()
+	repo := memory.NewReferrals()
+
+	code, err := repo.SaveCode(ctx, "c1", "REF1")
+	if err != nil || code != "REF1" {
+		t.Fatalf("expected code REF1 and nil error, got %q and %v", code, err)
+	}
+
+	code, err = repo.SaveCode(ctx, "c1", "REF2")
+	if err != nil || code != "REF1" {
+		t.Fatalf("expected existing code REF1 and nil error, got %q and %v", code, err)
+	}
+
+	referrerID, err := repo.FindReferrer(ctx, "REF1")
+	if err != nil || referrerID != "c1" {
+		t.Fatalf("expected referrer c1 and nil error, got %q and %v", referrerID, err)
+	}
+
+	if _, err := repo.FindReferrer(ctx, "REF2"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected err to be %v, got %v", repository.ErrNotFound, err)
+	}
+}
+
+func TestReferralsSaveFirstOrder(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReferralsSaveFirstOrder in long mode.")
+	}
+
+	ctx := context.Background()
+	repo := memory.NewReferrals()
+
+	if _, err := repo.FindFirstOrder(ctx, "c1"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expected err to be %v, got %v", repository.ErrNotFound, err)
+	}
+
+	orderID, err := repo.SaveFirstOrder(ctx, "c1", "o1")
+	if err != nil || orderID != "o1" {
+		t.Fatalf("expected order o1 and nil error, got %q and %v", orderID, err)
+	}
+
+	orderID, err = repo.SaveFirstOrder(ctx, "c1", "o2")
+	if err != nil || orderID != "o1" {
+		t.Fatalf("expected first order o1 and nil error, got %q and %v", orderID, err)
+	}
+
+	orderID, err = repo.FindFirstOrder(ctx, "c1")
+	if err != nil || orderID != "o1" {
+		t.Fatalf("expected first order o1 and nil error, got %q and %v", orderID, err)
+	}
+}
+
+func TestReferralsComplete(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReferralsComplete in long mode.")
+	}
+
+	type testCase struct {
+		name         string
+		refereeID    string
+		wantStatus   domain.ReferralStatus
+		wantRewarded bool
+		expectedErr  error
+	}
+
+	testCases := []testCase{
+		{
+			name:         "First referral rewarded",
+			refereeID:    "r1",
+			wantStatus:   domain.ReferralRewarded,
+			wantRewarded: true,
+		},
+		{
+			name:       "Completed referral stays rewarded",
+			refereeID:  "r1",
+			wantStatus: domain.ReferralRewarded,
+		},
+		{
+			name:       "Referral beyond cap",
+			refereeID:  "r2",
+			wantStatus: domain.ReferralCapped,
+		},
+		{
+			name:        "Unknown referee",
+			refereeID:   "r3",
+			expectedErr: repository.ErrNotFound,
+		},
+	}
+
+	ctx := context.Background()
+	repo := memory.NewReferrals()
+	for _, refereeID := range []string{"r1", "r2"} {
+		added, err := repo.Add(ctx, domain.Referral{Code: "REF1", ReferrerID: "c1", RefereeID: refereeID, Status: domain.ReferralPending})
+		if err != nil || !added {
+			t.Fatalf("expected referral to be added, got %v and %v", added, err)
+		}
+	}
+
+	if added, _ := repo.Add(ctx, domain.Referral{Code: "REF9", ReferrerID: "c9", RefereeID: "r1"}); added {
+		t.Fatalf("expected referee to be referred only once")
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			referral, rewarded, err := repo.Complete(ctx, tc.refereeID, "REWARD-"+tc.refereeID, 1)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected err to be %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if rewarded != tc.wantRewarded {
+				t.Errorf("expected rewarded to be %v, got %v", tc.wantRewarded, rewarded)
+			}
+			if referral.Status != tc.wantStatus {
+				t.Errorf("expected status to be %v, got %v", tc.wantStatus, referral.Status)
+			}
+		})
+	}
+}
@@ -2,17 +2,19 @@ package memory
 
 import (
 	"context"
-	"errors"
+	"sort"
 	"sync"
 
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
 )
 
-var ErrNotFound = errors.New("coupon not found")
-
 type Repository struct {
 	entries map[string]domain.Coupon
 	mu      *sync.Mutex
+
+	// durability is nil unless the repository was opened with OpenDurable.
+	durability *durability
 }
 
 func New() *Repository {
@@ -22,20 +24,182 @@ func New() *Repository {
 	}
 }
 
-func (r *Repository) FindByCode(_ context.Context, code string) (*domain.Coupon, error) {
+func (r *Repository) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if coupon, ok := r.entries[code]; ok {
 		return &coupon, nil
 	}
-	return nil, ErrNotFound
+	return nil, repository.ErrNotFound
+}
+
+func (r *Repository) FindByCodes(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coupons := make([]domain.Coupon, 0, len(codes))
+	for _, code := range codes {
+		if coupon, ok := r.entries[code]; ok {
+			coupons = append(coupons, coupon)
+		}
+	}
+	return coupons, nil
+}
+
+func (r *Repository) Save(ctx context.Context, coupon domain.Coupon) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coupon.Version = r.entries[coupon.Code].Version + 1
+	return r.store(coupon)
 }
 
-func (r *Repository) Save(_ context.Context, coupon domain.Coupon) error {
+// Insert stores the coupon unless its code is taken, in which case it
+// returns repository.ErrAlreadyExists.
+func (r *Repository) Insert(ctx context.Context, coupon domain.Coupon) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.entries[coupon.Code]; ok {
+		return repository.ErrAlreadyExists
+	}
+	coupon.Version = 1
+	return r.store(coupon)
+}
+
+func (r *Repository) Update(ctx context.Context, coupon domain.Coupon) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkVersion(coupon.Code, coupon.Version); err != nil {
+		return err
+	}
+	coupon.Version++
+	return r.store(coupon)
+}
+
+func (r *Repository) Delete(ctx context.Context, code string, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.entries[coupon.Code] = coupon
+	if err := r.checkVersion(code, version); err != nil {
+		return err
+	}
+	return r.remove(code)
+}
+
+// checkVersion must be called with mu held.
+func (r *Repository) checkVersion(code string, version int) error {
+	stored, ok := r.entries[code]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if stored.Version != version {
+		return repository.ErrVersionMismatch
+	}
 	return nil
 }
+
+// store must be called with mu held.
+func (r *Repository) store(coupon domain.Coupon) error {
+	return r.commit(func(d *durability) error { return d.append(coupon) }, func() {
+		r.entries[coupon.Code] = coupon
+	})
+}
+
+// remove must be called with mu held.
+func (r *Repository) remove(code string) error {
+	return r.commit(func(d *durability) error { return d.appendDelete(code) }, func() {
+		delete(r.entries, code)
+	})
+}
+
+// commit applies a change to the entries, after writing it to the log if
+// the repository is durable. It must be called with mu held.
+func (r *Repository) commit(log func(*durability) error, apply func()) error {
+	if r.durability == nil {
+		apply()
+		return nil
+	}
+
+	if err := log(r.durability); err != nil {
+		return err
+	}
+	apply()
+
+	// The change is already in the log, so compacting it, or failing to,
+	// is left to the background.
+	if r.durability.due() {
+		r.durability.compactInBackground(r.entries)
+	}
+	return nil
+}
+
+func (r *Repository) FindByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coupons := make([]domain.Coupon, 0)
+	for _, coupon := range r.entries {
+		if coupon.Assignment.AssignedTo(customer) {
+			coupons = append(coupons, coupon)
+		}
+	}
+
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].Code < coupons[j].Code
+	})
+
+	return coupons, nil
+}
+
+func (r *Repository) FindActivatable(ctx context.Context) ([]domain.Coupon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coupons := make([]domain.Coupon, 0)
+	for _, coupon := range r.entries {
+		if coupon.RequiresActivation && !coupon.Assignment.Restricted() {
+			coupons = append(coupons, coupon)
+		}
+	}
+
+	sort.Slice(coupons, func(i, j int) bool {
+		return coupons[i].Code < coupons[j].Code
+	})
+
+	return coupons, nil
+}
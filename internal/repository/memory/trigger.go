@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+type issuanceKey struct {
+	triggerID string
+	orderID   string
+}
+
+type Triggers struct {
+	entries   map[string]domain.Trigger
+	issuances map[issuanceKey]domain.Issuance
+	mu        *sync.Mutex
+}
+
+func NewTriggers() *Triggers {
+	return &Triggers{
+		entries:   make(map[string]domain.Trigger),
+		issuances: make(map[issuanceKey]domain.Issuance),
+		mu:        &sync.Mutex{},
+	}
+}
+
+func (r *Triggers) FindAll(_ context.Context) ([]domain.Trigger, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	triggers := make([]domain.Trigger, 0, len(r.entries))
+	for _, trigger := range r.entries {
+		triggers = append(triggers, trigger)
+	}
+
+	sort.Slice(triggers, func(i, j int) bool {
+		if triggers[i].Name != triggers[j].Name {
+			return triggers[i].Name < triggers[j].Name
+		}
+		return triggers[i].ID < triggers[j].ID
+	})
+
+	return triggers, nil
+}
+
+func (r *Triggers) Save(_ context.Context, trigger domain.Trigger) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[trigger.ID] = trigger
+	return nil
+}
+
+// Reserve records the issuance unless the trigger already issued a coupon
+// for the order, in which case the existing issuance is returned and the
+// second result is false.
+func (r *Triggers) Reserve(_ context.Context, issuance domain.Issuance) (domain.Issuance, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := issuanceKey{triggerID: issuance.TriggerID, orderID: issuance.OrderID}
+	if existing, ok := r.issuances[key]; ok {
+		return existing, false, nil
+	}
+
+	r.issuances[key] = issuance
+	return issuance, true, nil
+}
+
+func (r *Triggers) Release(_ context.Context, issuance domain.Issuance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := issuanceKey{triggerID: issuance.TriggerID, orderID: issuance.OrderID}
+	if existing, ok := r.issuances[key]; ok && existing.Code == issuance.Code {
+		delete(r.issuances, key)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package memory_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+)
+
+func TestTriggersReserve(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestTriggersReserve in long mode.")
+	}
+
+	first := domain.Issuance{TriggerID: "t1", OrderID: "o1", Code: "CODE1"}
+	second := domain.Issuance{TriggerID: "t1", OrderID: "o1", Code: "CODE2"}
+	otherTrigger := domain.Issuance{TriggerID: "t2", OrderID: "o1", Code: "CODE3"}
+
+	type testCase struct {
+		name         string
+		issuance     domain.Issuance
+		release      *domain.Issuance
+		want         domain.Issuance
+		wantReserved bool
+	}
+
+	testCases := []testCase{
+		{
+			name:         "First issuance for order",
+			issuance:     first,
+			want:         first,
+			wantReserved: true,
+		},
+		{
+			name:     "Repeated issuance returns existing one",
+			issuance: second,
+			want:     first,
+		},
+		{
+			name:         "Other trigger for same order",
+			issuance:     otherTrigger,
+			want:         otherTrigger,
+			wantReserved: true,
+		},
+		{
+			name:     "Release of another code keeps issuance",
+			release:  &second,
+			issuance: second,
+			want:     first,
+		},
+		{
+			name:         "Released issuance can be reserved again",
+			release:      &first,
+			issuance:     second,
+			want:         second,
+			wantReserved: true,
+		},
+	}
+
+	ctx := context.Background()
+	repo := memory.NewTriggers()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.release != nil {
+				if err := repo.Release(ctx, *tc.release); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+
+			got, reserved, err := repo.Reserve(ctx, tc.issuance)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if reserved != tc.wantReserved {
+				t.Errorf("expected reserved to be %v, got %v", tc.wantReserved, reserved)
+			}
+			if got != tc.want {
+				t.Errorf("expected issuance to be %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type Wallet struct {
+	entries map[string]map[string]struct{}
+	mu      *sync.Mutex
+}
+
+func NewWallet() *Wallet {
+	return &Wallet{
+		entries: make(map[string]map[string]struct{}),
+		mu:      &sync.Mutex{},
+	}
+}
+
+func (w *Wallet) Activate(_ context.Context, customerID string, code string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	codes, ok := w.entries[customerID]
+	if !ok {
+		codes = make(map[string]struct{})
+		w.entries[customerID] = codes
+	}
+	codes[code] = struct{}{}
+	return nil
+}
+
+func (w *Wallet) Deactivate(_ context.Context, customerID string, code string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.entries[customerID], code)
+	if len(w.entries[customerID]) == 0 {
+		delete(w.entries, customerID)
+	}
+	return nil
+}
+
+func (w *Wallet) IsActive(_ context.Context, customerID string, code string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.entries[customerID][code]
+	return ok, nil
+}
+
+func (w *Wallet) FindActive(_ context.Context, customerID string) ([]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	codes := make([]string, 0, len(w.entries[customerID]))
+	for code := range w.entries[customerID] {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes, nil
+}
@@ -0,0 +1,90 @@
+package memory_test
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+)
+
+func TestWallet(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestWallet in long mode.")
+	}
+
+	type testCase struct {
+		name       string
+		customerID string
+		activate   []string
+		deactivate []string
+		want       []string
+	}
+
+	testCases := []testCase{
+		{
+			name:       "Activated coupons",
+			customerID: "c1",
+			activate:   []string{"b", "a"},
+			want:       []string{"a", "b"},
+		},
+		{
+			name:       "Activating twice",
+			customerID: "c2",
+			activate:   []string{"a", "a"},
+			want:       []string{"a"},
+		},
+		{
+			name:       "Deactivated coupon",
+			customerID: "c3",
+			activate:   []string{"a", "b"},
+			deactivate: []string{"a", "unknown"},
+			want:       []string{"b"},
+		},
+		{
+			name:       "Empty wallet",
+			customerID: "c4",
+			want:       []string{},
+		},
+	}
+
+	ctx := context.Background()
+	wallet := memory.NewWallet()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, code := range tc.activate {
+				if err := wallet.Activate(ctx, tc.customerID, code); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+			for _, code := range tc.deactivate {
+				if err := wallet.Deactivate(ctx, tc.customerID, code); err != nil {
+					t.Fatalf("expected err to be nil, got %v", err)
+				}
+			}
+
+			codes, err := wallet.FindActive(ctx, tc.customerID)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if !reflect.DeepEqual(tc.want, codes) {
+				t.Errorf("expected codes to be %v, got %v", tc.want, codes)
+			}
+
+			for _, code := range tc.want {
+				active, _ := wallet.IsActive(ctx, tc.customerID, code)
+				if !active {
+					t.Errorf("expected %s to be active", code)
+				}
+			}
+			for _, code := range tc.deactivate {
+				active, _ := wallet.IsActive(ctx, tc.customerID, code)
+				if active {
+					t.Errorf("expected %s to be inactive", code)
+				}
+			}
+		})
+	}
+}
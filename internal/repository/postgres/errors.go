@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+// storageError marks errors of the connection or the server, rather than of
+// the query, as repository.ErrUnavailable. Other errors are returned as is.
+func storageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Connection exceptions, insufficient resources and operator
+		// intervention such as a server shutting down.
+		if strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") ||
+			strings.HasPrefix(pgErr.Code, "57P") {
+			return repository.Unavailable(err)
+		}
+		return err
+	}
+
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) || pgconn.SafeToRetry(err) {
+		return repository.Unavailable(err)
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLock is the key of the advisory lock held while migrating.
+const migrationLock = 7310541
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// migrate applies every migration not recorded in schema_migrations yet,
+// each in its own transaction. An advisory lock keeps instances starting at
+// the same time from migrating concurrently.
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	pending, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLock); err != nil {
+		return err
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLock)
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if err := apply(ctx, conn, m); err != nil {
+			return fmt.Errorf("migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func apply(ctx context.Context, conn *pgxpool.Conn, m migration) error {
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		var applied bool
+		err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.version).
+			Scan(&applied)
+		if err != nil || applied {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, m.sql); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", m.version)
+		return err
+	})
+}
+
+// loadMigrations returns the embedded migrations ordered by version. File
+// names start with the version, e.g. 0001_create_coupons.sql.
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version", entry.Name())
+		}
+
+		content, err := fs.ReadFile(migrations, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		loaded = append(loaded, migration{version: version, name: entry.Name(), sql: string(content)})
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].version < loaded[j].version
+	})
+
+	return loaded, nil
+}
@@ -0,0 +1,10 @@
+CREATE TABLE coupons (
+    id           TEXT PRIMARY KEY,
+    code         TEXT NOT NULL CONSTRAINT coupons_code_key UNIQUE,
+    data         JSON NOT NULL,
+    customer_ids TEXT[] NOT NULL DEFAULT '{}',
+    segments     TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX coupons_customer_ids_idx ON coupons USING GIN (customer_ids);
+CREATE INDEX coupons_segments_idx ON coupons USING GIN (segments);
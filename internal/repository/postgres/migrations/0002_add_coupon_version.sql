@@ -0,0 +1,2 @@
+-- Coupons stored before versioning count as version 1.
+ALTER TABLE coupons ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
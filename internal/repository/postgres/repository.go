@@ -0,0 +1,235 @@
+// Package postgres stores coupons in PostgreSQL through a pgx connection
+// pool.
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/document"
+)
+
+// Repository stores every coupon as a JSON document in a row unique by
+// code. Assigned customers and segments are kept in indexed array columns
+// for looking up the coupons of a customer.
+type Repository struct {
+	pool *pgxpool.Pool
+}
+
+// Open connects to the database and migrates it to the latest schema.
+func Open(ctx context.Context, cfg *pgxpool.Config) (*Repository, error) {
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(ctx, pool); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return &Repository{pool: pool}, nil
+}
+
+func (r *Repository) Close() {
+	r.pool.Close()
+}
+
+func (r *Repository) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	var data []byte
+	var version int
+	err := r.pool.QueryRow(ctx, "SELECT data, version FROM coupons WHERE code = $1", code).Scan(&data, &version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, storageError(err)
+	}
+
+	return decode(data, version)
+}
+
+func (r *Repository) FindByCodes(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	rows, err := r.pool.Query(ctx, "SELECT data, version FROM coupons WHERE code = ANY ($1)", nonNil(codes))
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	found := make(map[string]domain.Coupon, len(codes))
+	var data []byte
+	var version int
+	_, err = pgx.ForEachRow(rows, []any{&data, &version}, func() error {
+		coupon, err := decode(data, version)
+		if err != nil {
+			return err
+		}
+		found[coupon.Code] = *coupon
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	coupons := make([]domain.Coupon, 0, len(codes))
+	for _, code := range codes {
+		if coupon, ok := found[code]; ok {
+			coupons = append(coupons, coupon)
+		}
+	}
+	return coupons, nil
+}
+
+func (r *Repository) Save(ctx context.Context, coupon domain.Coupon) error {
+	data, err := document.Encode(coupon)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `INSERT INTO coupons (id, code, data, customer_ids, segments)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (code) DO UPDATE SET
+			id = EXCLUDED.id,
+			data = EXCLUDED.data,
+			customer_ids = EXCLUDED.customer_ids,
+			segments = EXCLUDED.segments,
+			version = coupons.version + 1`,
+		coupon.ID, coupon.Code, string(data), nonNil(coupon.Assignment.CustomerIDs), nonNil(coupon.Assignment.Segments))
+	return storageError(err)
+}
+
+// Insert stores the coupon unless its code is taken, in which case it
+// returns repository.ErrAlreadyExists.
+func (r *Repository) Insert(ctx context.Context, coupon domain.Coupon) error {
+	data, err := document.Encode(coupon)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pool.Exec(ctx, `INSERT INTO coupons (id, code, data, customer_ids, segments)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (code) DO NOTHING`,
+		coupon.ID, coupon.Code, string(data), nonNil(coupon.Assignment.CustomerIDs), nonNil(coupon.Assignment.Segments))
+	if err != nil {
+		return storageError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrAlreadyExists
+	}
+	return nil
+}
+
+func (r *Repository) Update(ctx context.Context, coupon domain.Coupon) error {
+	data, err := document.Encode(coupon)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.pool.Exec(ctx, `UPDATE coupons SET
+			id = $1,
+			data = $3,
+			customer_ids = $4,
+			segments = $5,
+			version = version + 1
+		WHERE code = $2 AND version = $6`,
+		coupon.ID, coupon.Code, string(data), nonNil(coupon.Assignment.CustomerIDs), nonNil(coupon.Assignment.Segments),
+		coupon.Version)
+	if err != nil {
+		return storageError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return r.missingOrStale(ctx, coupon.Code)
+	}
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, code string, version int) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM coupons WHERE code = $1 AND version = $2", code, version)
+	if err != nil {
+		return storageError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return r.missingOrStale(ctx, code)
+	}
+	return nil
+}
+
+// missingOrStale tells why a write conditional on the version of the coupon
+// with the code changed no row.
+func (r *Repository) missingOrStale(ctx context.Context, code string) error {
+	var version int
+	err := r.pool.QueryRow(ctx, "SELECT version FROM coupons WHERE code = $1", code).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repository.ErrNotFound
+		}
+		return storageError(err)
+	}
+	return repository.ErrVersionMismatch
+}
+
+func (r *Repository) FindByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	return r.query(ctx, `SELECT data, version FROM coupons
+		WHERE ($1 <> '' AND $1 = ANY (customer_ids)) OR segments && $2
+		ORDER BY code`,
+		customer.ID, nonNil(customer.Segments))
+}
+
+// FindActivatable reads whether a coupon requires activation from its
+// document, as there is no column for it.
+func (r *Repository) FindActivatable(ctx context.Context) ([]domain.Coupon, error) {
+	return r.query(ctx, `SELECT data, version FROM coupons
+		WHERE customer_ids = '{}' AND segments = '{}' AND (data->>'RequiresActivation')::boolean
+		ORDER BY code`)
+}
+
+// query reads the coupons the query selects as data and version.
+func (r *Repository) query(ctx context.Context, query string, args ...any) ([]domain.Coupon, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	coupons, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (domain.Coupon, error) {
+		var data []byte
+		var version int
+		if err := row.Scan(&data, &version); err != nil {
+			return domain.Coupon{}, err
+		}
+
+		coupon, err := decode(data, version)
+		if err != nil {
+			return domain.Coupon{}, err
+		}
+		return *coupon, nil
+	})
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	return coupons, nil
+}
+
+// decode reads a coupon document. The version column takes precedence over
+// the document, as Save bumps it without knowing the version it replaces.
+func decode(data []byte, version int) (*domain.Coupon, error) {
+	coupon, err := document.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	coupon.Version = version
+	return coupon, nil
+}
+
+// nonNil turns nil into an empty slice, which pgx encodes as an empty array
+// rather than NULL.
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
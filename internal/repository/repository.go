@@ -0,0 +1,30 @@
+// Package repository defines the errors every storage backend reports, so
+// that callers can tell them apart without knowing the backend.
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound means the requested record does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means a write contradicts the stored state, e.g. a code
+	// that is taken already.
+	ErrConflict = errors.New("conflict")
+	// ErrAlreadyExists is the conflict of inserting a coupon whose code is
+	// taken.
+	ErrAlreadyExists = fmt.Errorf("coupon already exists: %w", ErrConflict)
+	// ErrVersionMismatch is the conflict of writing a coupon whose stored
+	// version changed since it was read.
+	ErrVersionMismatch = fmt.Errorf("coupon version mismatch: %w", ErrConflict)
+	// ErrUnavailable means the storage itself failed, e.g. a lost connection
+	// or a full disk. Retrying later may succeed.
+	ErrUnavailable = errors.New("storage unavailable")
+)
+
+// Unavailable marks err as a storage failure, keeping it in the chain.
+func Unavailable(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnavailable, err)
+}
@@ -0,0 +1,773 @@
+// Package repositorytest holds the conformance suite every implementation
+// of service.Repository has to pass, so that backends behave the same.
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+// Factory returns a new, empty repository for a test.
+type Factory func(t *testing.T) service.Repository
+
+// Run runs the whole conformance suite, each test against a new repository.
+func Run(t *testing.T, newRepo Factory) {
+	t.Run("FindByCode", func(t *testing.T) { FindByCode(t, newRepo) })
+	t.Run("FindByCodes", func(t *testing.T) { FindByCodes(t, newRepo) })
+	t.Run("Save", func(t *testing.T) { Save(t, newRepo) })
+	t.Run("SaveRoundTrip", func(t *testing.T) { SaveRoundTrip(t, newRepo) })
+	t.Run("FindByCustomer", func(t *testing.T) { FindByCustomer(t, newRepo) })
+	t.Run("FindActivatable", func(t *testing.T) { FindActivatable(t, newRepo) })
+	t.Run("ConcurrentSaves", func(t *testing.T) { ConcurrentSaves(t, newRepo) })
+	t.Run("CancelledContext", func(t *testing.T) { CancelledContext(t, newRepo) })
+	t.Run("CreateIfAbsent", func(t *testing.T) { CreateIfAbsent(t, newRepo) })
+	t.Run("Versions", func(t *testing.T) { Versions(t, newRepo) })
+	t.Run("ConcurrentUpdates", func(t *testing.T) { ConcurrentUpdates(t, newRepo) })
+}
+
+func FindByCode(t *testing.T, newRepo Factory) {
+	type testCase struct {
+		name        string
+		code        string
+		expectedErr error
+		want        *domain.Coupon
+	}
+
+	testCases := []testCase{
+		{
+			name:        "Coupon found",
+			code:        "test",
+			expectedErr: nil,
+			want: &domain.Coupon{
+				ID:             "test",
+				Code:           "test",
+				Discount:       0,
+				MinBasketValue: 0,
+				Version:        1,
+			},
+		},
+		{
+			name:        "Coupon not found",
+			code:        "not found",
+			expectedErr: repository.ErrNotFound,
+			want:        nil,
+		},
+		{
+			name:        "Empty coupon code",
+			code:        "",
+			expectedErr: repository.ErrNotFound,
+			want:        nil,
+		},
+	}
+
+	ctx := context.Background()
+	repo := newRepo(t)
+	_ = repo.Save(ctx, domain.Coupon{
+		ID:             "test",
+		Code:           "test",
+		Discount:       0,
+		MinBasketValue: 0,
+	})
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coupon, err := repo.FindByCode(ctx, tc.code)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("expected err to be %v, got %v", tc.expectedErr, err)
+					return
+				}
+			}
+			if !reflect.DeepEqual(tc.want, coupon) {
+				t.Errorf("expected coupon to be %v, got %v", tc.want, coupon)
+			}
+		})
+	}
+}
+
+func FindByCodes(t *testing.T, newRepo Factory) {
+	type testCase struct {
+		name  string
+		codes []string
+		want  []string
+	}
+
+	testCases := []testCase{
+		{
+			name:  "All codes found",
+			codes: []string{"b", "a", "c"},
+			want:  []string{"b", "a", "c"},
+		},
+		{
+			name:  "Missing codes left out",
+			codes: []string{"missing", "c", "", "a"},
+			want:  []string{"c", "a"},
+		},
+		{
+			name:  "No codes",
+			codes: []string{},
+			want:  []string{},
+		},
+		{
+			name:  "No code found",
+			codes: []string{"missing"},
+			want:  []string{},
+		},
+	}
+
+	ctx := context.Background()
+	repo := newRepo(t)
+	for _, code := range []string{"a", "b", "c"} {
+		if err := repo.Save(ctx, domain.Coupon{ID: "id-" + code, Code: code, Discount: 10}); err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coupons, err := repo.FindByCodes(ctx, tc.codes)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			got := make([]string, 0, len(coupons))
+			for _, coupon := range coupons {
+				if coupon.ID != "id-"+coupon.Code || coupon.Discount != 10 {
+					t.Errorf("expected coupon %q to be stored, got %v", coupon.Code, coupon)
+				}
+				got = append(got, coupon.Code)
+			}
+			if !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("expected codes to be %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func Save(t *testing.T, newRepo Factory) {
+	type testCase struct {
+		name        string
+		coupon      domain.Coupon
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Successful save",
+			coupon: domain.Coupon{
+				ID:             "test",
+				Code:           "test",
+				Discount:       0,
+				MinBasketValue: 0,
+			},
+			expectedErr: nil,
+		},
+	}
+
+	repo := newRepo(t)
+	ctx := context.Background()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.Save(ctx, tc.coupon)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected err to be %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+// SaveRoundTrip checks that every field of a coupon survives storing it,
+// and that saving a code again replaces the coupon.
+func SaveRoundTrip(t *testing.T, newRepo Factory) {
+	coupon := domain.Coupon{
+		ID:             "id1",
+		Code:           "full",
+		Discount:       20,
+		MinBasketValue: 50,
+		Assignment: domain.Assignment{
+			CustomerIDs: []string{"c1", "c2"},
+			Segments:    []string{"gold"},
+		},
+		RequiresActivation: true,
+		Schedule: domain.Schedule{
+			Weekdays: []time.Weekday{time.Saturday, time.Sunday},
+			Windows:  []domain.TimeWindow{{Start: 22 * 60, End: 2 * 60}},
+		},
+		Eligibility: domain.Eligibility{
+			Channels: domain.Restriction{Allow: []string{"online"}},
+			Regions:  domain.Restriction{Deny: []string{"AT"}},
+		},
+		UsageLimits: []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay, Rolling: true}},
+		Conditions: []domain.Condition{{Type: domain.ConditionAny, Conditions: []domain.Condition{
+			{Type: domain.ConditionContainsSKU, SKU: "tea-20"},
+			{Type: domain.ConditionMinQuantity, Category: "drinks", Min: 3},
+		}}},
+		Expression: `customer.tier == "gold"`,
+		Strategy:   "percent",
+		Params:     json.RawMessage(`{"max":500}`),
+	}
+
+	ctx := context.Background()
+	repo := newRepo(t)
+
+	if err := repo.Save(ctx, coupon); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	got, err := repo.FindByCode(ctx, coupon.Code)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	coupon.Version = 1
+	if !reflect.DeepEqual(&coupon, got) {
+		t.Errorf("expected coupon to be %+v, got %+v", coupon, *got)
+	}
+
+	replaced := domain.Coupon{ID: "id2", Code: coupon.Code, Discount: 5}
+	if err := repo.Save(ctx, replaced); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	got, err = repo.FindByCode(ctx, coupon.Code)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	replaced.Version = 2
+	if !reflect.DeepEqual(&replaced, got) {
+		t.Errorf("expected coupon to be %+v, got %+v", replaced, *got)
+	}
+
+	coupons, err := repo.FindByCustomer(ctx, domain.Customer{ID: "c1"})
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if len(coupons) != 0 {
+		t.Errorf("expected replaced coupon to be unassigned, got %v", coupons)
+	}
+}
+
+func FindByCustomer(t *testing.T, newRepo Factory) {
+	personal := domain.Coupon{
+		ID:         "personal",
+		Code:       "personal",
+		Assignment: domain.Assignment{CustomerIDs: []string{"c1"}},
+		Version:    1,
+	}
+	segment := domain.Coupon{
+		ID:         "segment",
+		Code:       "segment",
+		Assignment: domain.Assignment{Segments: []string{"gold"}},
+		Version:    1,
+	}
+	public := domain.Coupon{
+		ID:   "public",
+		Code: "public",
+	}
+
+	type testCase struct {
+		name     string
+		customer domain.Customer
+		want     []domain.Coupon
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Directly assigned coupon",
+			customer: domain.Customer{ID: "c1"},
+			want:     []domain.Coupon{personal},
+		},
+		{
+			name:     "Directly and segment assigned coupons",
+			customer: domain.Customer{ID: "c1", Segments: []string{"gold"}},
+			want:     []domain.Coupon{personal, segment},
+		},
+		{
+			name:     "No assigned coupons",
+			customer: domain.Customer{ID: "c2"},
+			want:     []domain.Coupon{},
+		},
+	}
+
+	ctx := context.Background()
+	repo := newRepo(t)
+	for _, coupon := range []domain.Coupon{personal, segment, public} {
+		_ = repo.Save(ctx, coupon)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			coupons, err := repo.FindByCustomer(ctx, tc.customer)
+			if err != nil {
+				t.Errorf("expected err to be nil, got %v", err)
+				return
+			}
+			if !reflect.DeepEqual(tc.want, coupons) {
+				t.Errorf("expected coupons to be %v, got %v", tc.want, coupons)
+			}
+		})
+	}
+}
+
+// FindActivatable checks that only unassigned coupons requiring activation
+// are found.
+func FindActivatable(t *testing.T, newRepo Factory) {
+	activatable := []domain.Coupon{
+		{ID: "a1", Code: "a1", RequiresActivation: true, Version: 1},
+		{ID: "a2", Code: "a2", RequiresActivation: true, Version: 1},
+	}
+	others := []domain.Coupon{
+		{ID: "assigned", Code: "assigned", RequiresActivation: true, Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}},
+		{ID: "segment", Code: "segment", RequiresActivation: true, Assignment: domain.Assignment{Segments: []string{"gold"}}},
+		{ID: "public", Code: "public"},
+	}
+
+	ctx := context.Background()
+	repo := newRepo(t)
+	for _, coupon := range append(others, activatable...) {
+		if err := repo.Save(ctx, coupon); err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+
+	coupons, err := repo.FindActivatable(ctx)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if !reflect.DeepEqual(activatable, coupons) {
+		t.Errorf("expected coupons to be %v, got %v", activatable, coupons)
+	}
+}
+
+// ConcurrentSaves checks that concurrent saves of distinct codes all land,
+// and that concurrent saves of one code leave one of them whole.
+func ConcurrentSaves(t *testing.T, newRepo Factory) {
+	const writers = 16
+
+	ctx := context.Background()
+	repo := newRepo(t)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repo.Save(ctx, domain.Coupon{ID: fmt.Sprintf("own%d", i), Code: fmt.Sprintf("own%d", i), Discount: i})
+			errs <- repo.Save(ctx, domain.Coupon{ID: fmt.Sprintf("shared%d", i), Code: "shared", Discount: i})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+	}
+
+	for i := 0; i < writers; i++ {
+		coupon, err := repo.FindByCode(ctx, fmt.Sprintf("own%d", i))
+		if err != nil {
+			t.Fatalf("expected err to be nil, got %v", err)
+		}
+		if coupon.Discount != i {
+			t.Errorf("expected discount to be %d, got %d", i, coupon.Discount)
+		}
+	}
+
+	shared, err := repo.FindByCode(ctx, "shared")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if want := fmt.Sprintf("shared%d", shared.Discount); shared.ID != want {
+		t.Errorf("expected shared coupon to be written by one save, got ID %q with discount %d", shared.ID, shared.Discount)
+	}
+}
+
+// CancelledContext checks that every method fails with the context error
+// once the context is cancelled, and that a cancelled save stores nothing.
+func CancelledContext(t *testing.T, newRepo Factory) {
+	repo := newRepo(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	coupon := domain.Coupon{ID: "cancelled", Code: "cancelled"}
+
+	if err := repo.Save(ctx, coupon); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Save err to be %v, got %v", context.Canceled, err)
+	}
+
+	if _, err := repo.FindByCode(ctx, coupon.Code); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected FindByCode err to be %v, got %v", context.Canceled, err)
+	}
+
+	if _, err := repo.FindByCodes(ctx, []string{coupon.Code}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected FindByCodes err to be %v, got %v", context.Canceled, err)
+	}
+
+	if _, err := repo.FindByCustomer(ctx, domain.Customer{ID: "c1"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected FindByCustomer err to be %v, got %v", context.Canceled, err)
+	}
+
+	if _, err := repo.FindActivatable(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected FindActivatable err to be %v, got %v", context.Canceled, err)
+	}
+
+	if err := repo.Insert(ctx, coupon); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Insert err to be %v, got %v", context.Canceled, err)
+	}
+
+	if err := repo.Update(ctx, coupon); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Update err to be %v, got %v", context.Canceled, err)
+	}
+
+	if err := repo.Delete(ctx, coupon.Code, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Delete err to be %v, got %v", context.Canceled, err)
+	}
+
+	if _, err := repo.FindByCode(context.Background(), coupon.Code); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrNotFound, err)
+	}
+}
+
+// CreateIfAbsent checks that of many concurrent inserts of one code exactly
+// one succeeds and is stored, while the others fail with ErrAlreadyExists.
+func CreateIfAbsent(t *testing.T, newRepo Factory) {
+	const writers = 16
+
+	repo := newRepo(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Insert(ctx, domain.Coupon{ID: fmt.Sprintf("id%d", i), Code: "contended", Discount: i})
+		}(i)
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err != nil {
+			if !errors.Is(err, repository.ErrAlreadyExists) {
+				t.Errorf("expected err to be %v, got %v", repository.ErrAlreadyExists, err)
+			}
+			continue
+		}
+		if winner != -1 {
+			t.Fatalf("expected one insert to succeed, got %d and %d", winner, i)
+		}
+		winner = i
+	}
+	if winner == -1 {
+		t.Fatalf("expected one insert to succeed, got %v", errs)
+	}
+
+	coupon, err := repo.FindByCode(ctx, "contended")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if want := fmt.Sprintf("id%d", winner); coupon.ID != want || coupon.Discount != winner {
+		t.Errorf("expected stored coupon to be %q, got %+v", want, *coupon)
+	}
+}
+
+// Versions checks that every write bumps the version of a coupon, and that
+// updates and deletes only succeed from the stored version.
+func Versions(t *testing.T, newRepo Factory) {
+	type testCase struct {
+		name        string
+		write       func(repository service.Repository) error
+		expectedErr error
+		// wantVersion is the stored version afterwards, zero if deleted.
+		wantVersion  int
+		wantDiscount int
+	}
+
+	ctx := context.Background()
+	stored := domain.Coupon{ID: "id1", Code: "versioned", Discount: 10}
+	changed := domain.Coupon{ID: "id1", Code: "versioned", Discount: 20}
+
+	testCases := []testCase{
+		{
+			name:         "Insert stores version 1",
+			write:        func(service.Repository) error { return nil },
+			wantVersion:  1,
+			wantDiscount: 10,
+		},
+		{
+			name: "Save stores the next version",
+			write: func(repo service.Repository) error {
+				return repo.Save(ctx, changed)
+			},
+			wantVersion:  2,
+			wantDiscount: 20,
+		},
+		{
+			name: "Update from the stored version",
+			write: func(repo service.Repository) error {
+				update := changed
+				update.Version = 1
+				return repo.Update(ctx, update)
+			},
+			wantVersion:  2,
+			wantDiscount: 20,
+		},
+		{
+			name: "Update from a stale version",
+			write: func(repo service.Repository) error {
+				update := changed
+				update.Version = 0
+				return repo.Update(ctx, update)
+			},
+			expectedErr:  repository.ErrVersionMismatch,
+			wantVersion:  1,
+			wantDiscount: 10,
+		},
+		{
+			name: "Update from a newer version",
+			write: func(repo service.Repository) error {
+				update := changed
+				update.Version = 2
+				return repo.Update(ctx, update)
+			},
+			expectedErr:  repository.ErrVersionMismatch,
+			wantVersion:  1,
+			wantDiscount: 10,
+		},
+		{
+			name: "Update of a missing coupon",
+			write: func(repo service.Repository) error {
+				return repo.Update(ctx, domain.Coupon{ID: "id2", Code: "missing", Version: 1})
+			},
+			expectedErr:  repository.ErrNotFound,
+			wantVersion:  1,
+			wantDiscount: 10,
+		},
+		{
+			name: "Update twice from the same version",
+			write: func(repo service.Repository) error {
+				update := changed
+				update.Version = 1
+				if err := repo.Update(ctx, update); err != nil {
+					return err
+				}
+				return repo.Update(ctx, update)
+			},
+			expectedErr:  repository.ErrVersionMismatch,
+			wantVersion:  2,
+			wantDiscount: 20,
+		},
+		{
+			name: "Delete from the stored version",
+			write: func(repo service.Repository) error {
+				return repo.Delete(ctx, stored.Code, 1)
+			},
+		},
+		{
+			name: "Delete from a stale version",
+			write: func(repo service.Repository) error {
+				return repo.Delete(ctx, stored.Code, 2)
+			},
+			expectedErr:  repository.ErrVersionMismatch,
+			wantVersion:  1,
+			wantDiscount: 10,
+		},
+		{
+			name: "Delete of a missing coupon",
+			write: func(repo service.Repository) error {
+				return repo.Delete(ctx, "missing", 1)
+			},
+			expectedErr:  repository.ErrNotFound,
+			wantVersion:  1,
+			wantDiscount: 10,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newRepo(t)
+			if err := repo.Insert(ctx, stored); err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+
+			if err := tc.write(repo); !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected err to be %v, got %v", tc.expectedErr, err)
+			}
+
+			coupon, err := repo.FindByCode(ctx, stored.Code)
+			if tc.wantVersion == 0 {
+				if !errors.Is(err, repository.ErrNotFound) {
+					t.Errorf("expected err to be %v, got %v", repository.ErrNotFound, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if coupon.Version != tc.wantVersion || coupon.Discount != tc.wantDiscount {
+				t.Errorf("expected version %d with discount %d, got %+v", tc.wantVersion, tc.wantDiscount, *coupon)
+			}
+
+			coupons, err := repo.FindByCodes(ctx, []string{stored.Code})
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if len(coupons) != 1 || coupons[0].Version != tc.wantVersion {
+				t.Errorf("expected FindByCodes to return version %d, got %+v", tc.wantVersion, coupons)
+			}
+		})
+	}
+}
+
+// ConcurrentUpdates checks that of many concurrent updates from the same
+// version exactly one succeeds, while the others fail with
+// ErrVersionMismatch.
+func ConcurrentUpdates(t *testing.T, newRepo Factory) {
+	const writers = 16
+
+	repo := newRepo(t)
+	ctx := context.Background()
+
+	if err := repo.Insert(ctx, domain.Coupon{ID: "id", Code: "contended"}); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Update(ctx, domain.Coupon{ID: "id", Code: "contended", Discount: i + 1, Version: 1})
+		}(i)
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, err := range errs {
+		if err != nil {
+			if !errors.Is(err, repository.ErrVersionMismatch) {
+				t.Errorf("expected err to be %v, got %v", repository.ErrVersionMismatch, err)
+			}
+			continue
+		}
+		if winner != -1 {
+			t.Fatalf("expected one update to succeed, got %d and %d", winner, i)
+		}
+		winner = i
+	}
+	if winner == -1 {
+		t.Fatalf("expected one update to succeed, got %v", errs)
+	}
+
+	coupon, err := repo.FindByCode(ctx, "contended")
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if coupon.Discount != winner+1 || coupon.Version != 2 {
+		t.Errorf("expected update %d to be stored as version 2, got %+v", winner, *coupon)
+	}
+}
+
+// RedemptionFactory returns a new, empty redemption history for a test.
+type RedemptionFactory func(t *testing.T) service.RedemptionRepository
+
+func Redemptions(t *testing.T, newRepo RedemptionFactory) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	perDay := []domain.UsageWindow{{Since: day, Max: 2}}
+
+	type testCase struct {
+		name         string
+		redemption   domain.Redemption
+		windows      []domain.UsageWindow
+		wantRecorded bool
+		wantCount    int
+	}
+
+	testCases := []testCase{
+		{
+			name:         "Redemption before window",
+			redemption:   domain.Redemption{Code: "daily", CustomerID: "c1", RedeemedAt: day.Add(-time.Minute)},
+			wantRecorded: true,
+			wantCount:    0,
+		},
+		{
+			name:         "First redemption in window",
+			redemption:   domain.Redemption{Code: "daily", CustomerID: "c1", RedeemedAt: day},
+			windows:      perDay,
+			wantRecorded: true,
+			wantCount:    1,
+		},
+		{
+			name:         "Other customer",
+			redemption:   domain.Redemption{Code: "daily", CustomerID: "c2", RedeemedAt: day.Add(time.Hour)},
+			windows:      perDay,
+			wantRecorded: true,
+			wantCount:    1,
+		},
+		{
+			name:         "Second redemption in window",
+			redemption:   domain.Redemption{Code: "daily", CustomerID: "c1", RedeemedAt: day.Add(2 * time.Hour)},
+			windows:      perDay,
+			wantRecorded: true,
+			wantCount:    2,
+		},
+		{
+			name:       "Redemption exceeding window",
+			redemption: domain.Redemption{Code: "daily", CustomerID: "c1", RedeemedAt: day.Add(3 * time.Hour)},
+			windows:    perDay,
+			wantCount:  2,
+		},
+	}
+
+	ctx := context.Background()
+	repo := newRepo(t)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorded, err := repo.Record(ctx, tc.redemption, tc.windows)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if recorded != tc.wantRecorded {
+				t.Errorf("expected recorded to be %v, got %v", tc.wantRecorded, recorded)
+			}
+
+			count, err := repo.Count(ctx, "c1", "daily", day)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if count != tc.wantCount {
+				t.Errorf("expected count to be %d, got %d", tc.wantCount, count)
+			}
+		})
+	}
+
+	t.Run("Customers with redemptions", func(t *testing.T) {
+		for customerID, want := range map[string]bool{"c1": true, "c2": true, "c": false, "c3": false} {
+			redeemed, err := repo.HasRedeemed(ctx, customerID)
+			if err != nil {
+				t.Fatalf("expected err to be nil, got %v", err)
+			}
+			if redeemed != want {
+				t.Errorf("expected %s to have redeemed to be %v, got %v", customerID, want, redeemed)
+			}
+		}
+	})
+}
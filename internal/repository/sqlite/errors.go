@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+// storageError marks errors of the database itself, rather than of the
+// query, as repository.ErrUnavailable. Other errors are returned as is.
+func storageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		// Extended result codes keep the primary code in the low byte.
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED, sqlite3.SQLITE_IOERR, sqlite3.SQLITE_FULL,
+			sqlite3.SQLITE_CANTOPEN, sqlite3.SQLITE_READONLY:
+			return repository.Unavailable(err)
+		}
+		return err
+	}
+
+	// database/sql does not export the error of a closed database.
+	if errors.Is(err, sql.ErrConnDone) || err.Error() == "sql: database is closed" {
+		return repository.Unavailable(err)
+	}
+
+	return err
+}
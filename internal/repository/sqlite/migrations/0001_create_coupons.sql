@@ -0,0 +1,14 @@
+CREATE TABLE coupons (
+    code TEXT PRIMARY KEY,
+    id   TEXT NOT NULL,
+    data TEXT NOT NULL
+);
+
+CREATE TABLE coupon_assignments (
+    code  TEXT NOT NULL REFERENCES coupons (code) ON DELETE CASCADE,
+    kind  TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (code, kind, value)
+);
+
+CREATE INDEX coupon_assignments_value ON coupon_assignments (kind, value);
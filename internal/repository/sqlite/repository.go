@@ -0,0 +1,315 @@
+// Package sqlite stores coupons in a SQLite database, using a pure Go
+// driver so no CGO is needed.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/document"
+)
+
+const (
+	assignedCustomer = "customer"
+	assignedSegment  = "segment"
+)
+
+// Repository stores every coupon as a JSON document keyed by code. Its
+// assignments are kept in a separate table for looking up the coupons of a
+// customer.
+type Repository struct {
+	db *sql.DB
+}
+
+// Open opens the database at path, creating it if needed, and migrates it
+// to the latest schema.
+func Open(ctx context.Context, path string) (*Repository, error) {
+	db, err := sql.Open("sqlite", "file:"+path+
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Repository{db: db}, nil
+}
+
+func (r *Repository) Close() error {
+	return r.db.Close()
+}
+
+func (r *Repository) FindByCode(ctx context.Context, code string) (*domain.Coupon, error) {
+	var data string
+	var version int
+	err := r.db.QueryRowContext(ctx, "SELECT data, version FROM coupons WHERE code = ?", code).Scan(&data, &version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, storageError(err)
+	}
+
+	return decode(data, version)
+}
+
+func (r *Repository) FindByCodes(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	found, err := r.findByCodes(ctx, codes)
+	if err != nil {
+		return nil, storageError(err)
+	}
+
+	coupons := make([]domain.Coupon, 0, len(codes))
+	for _, code := range codes {
+		if coupon, ok := found[code]; ok {
+			coupons = append(coupons, coupon)
+		}
+	}
+	return coupons, nil
+}
+
+// findByCodes passes the codes as a single JSON array, which keeps the query
+// within the limit on bound parameters however many codes there are.
+func (r *Repository) findByCodes(ctx context.Context, codes []string) (map[string]domain.Coupon, error) {
+	list, err := json.Marshal(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT data, version FROM coupons WHERE code IN (SELECT value FROM json_each(?))", string(list))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[string]domain.Coupon, len(codes))
+	for rows.Next() {
+		var data string
+		var version int
+		if err := rows.Scan(&data, &version); err != nil {
+			return nil, err
+		}
+
+		coupon, err := decode(data, version)
+		if err != nil {
+			return nil, err
+		}
+		found[coupon.Code] = *coupon
+	}
+
+	return found, rows.Err()
+}
+
+func (r *Repository) Save(ctx context.Context, coupon domain.Coupon) error {
+	return storageError(r.write(ctx, coupon, nil, `INSERT INTO coupons (code, id, data) VALUES (?, ?, ?)
+		ON CONFLICT (code) DO UPDATE SET id = excluded.id, data = excluded.data, version = version + 1`))
+}
+
+// Insert stores the coupon unless its code is taken, in which case it
+// returns repository.ErrAlreadyExists.
+func (r *Repository) Insert(ctx context.Context, coupon domain.Coupon) error {
+	alreadyExists := func(*sql.Tx) error { return repository.ErrAlreadyExists }
+	return storageError(r.write(ctx, coupon, alreadyExists, `INSERT INTO coupons (code, id, data) VALUES (?, ?, ?)
+		ON CONFLICT (code) DO NOTHING`))
+}
+
+func (r *Repository) Update(ctx context.Context, coupon domain.Coupon) error {
+	unchanged := func(tx *sql.Tx) error { return missingOrStale(ctx, tx, coupon.Code) }
+	return storageError(r.write(ctx, coupon, unchanged, `UPDATE coupons SET id = ?2, data = ?3, version = version + 1
+		WHERE code = ?1 AND version = ?4`, coupon.Version))
+}
+
+func (r *Repository) Delete(ctx context.Context, code string, version int) error {
+	return storageError(r.delete(ctx, code, version))
+}
+
+// delete removes the coupon row, and its assignments with it.
+func (r *Repository) delete(ctx context.Context, code string, version int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM coupons WHERE code = ? AND version = ?", code, version)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return missingOrStale(ctx, tx, code)
+	}
+
+	return tx.Commit()
+}
+
+// write stores the coupon row with the given statement, which takes the
+// code, ID and document followed by args, and replaces its assignments in
+// the same transaction. If the statement changes no row, the error of
+// unchanged is returned.
+func (r *Repository) write(ctx context.Context, coupon domain.Coupon, unchanged func(*sql.Tx) error,
+	statement string, args ...any) error {
+	data, err := document.Encode(coupon)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, statement, append([]any{coupon.Code, coupon.ID, string(data)}, args...)...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 && unchanged != nil {
+		return unchanged(tx)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM coupon_assignments WHERE code = ?", coupon.Code); err != nil {
+		return err
+	}
+
+	assign := func(kind string, values []string) error {
+		for _, value := range values {
+			_, err := tx.ExecContext(ctx,
+				"INSERT OR IGNORE INTO coupon_assignments (code, kind, value) VALUES (?, ?, ?)",
+				coupon.Code, kind, value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := assign(assignedCustomer, coupon.Assignment.CustomerIDs); err != nil {
+		return err
+	}
+	if err := assign(assignedSegment, coupon.Assignment.Segments); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// missingOrStale tells why a write conditional on the version of the coupon
+// with the code changed no row.
+func missingOrStale(ctx context.Context, tx *sql.Tx, code string) error {
+	var version int
+	err := tx.QueryRowContext(ctx, "SELECT version FROM coupons WHERE code = ?", code).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrNotFound
+		}
+		return err
+	}
+	return repository.ErrVersionMismatch
+}
+
+func (r *Repository) FindByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	coupons, err := r.findByCustomer(ctx, customer)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return coupons, nil
+}
+
+func (r *Repository) findByCustomer(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]any, 0, len(customer.Segments)+2)
+
+	if customer.ID != "" {
+		conditions = append(conditions, "(kind = ? AND value = ?)")
+		args = append(args, assignedCustomer, customer.ID)
+	}
+
+	if len(customer.Segments) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(customer.Segments)), ", ")
+		conditions = append(conditions, "(kind = ? AND value IN ("+placeholders+"))")
+		args = append(args, assignedSegment)
+		for _, segment := range customer.Segments {
+			args = append(args, segment)
+		}
+	}
+
+	coupons := make([]domain.Coupon, 0)
+	if len(conditions) == 0 {
+		return coupons, nil
+	}
+
+	return r.query(ctx, `SELECT data, version FROM coupons WHERE code IN (
+		SELECT code FROM coupon_assignments WHERE `+strings.Join(conditions, " OR ")+`
+	) ORDER BY code`, args...)
+}
+
+// FindActivatable reads whether a coupon requires activation from its
+// document, as there is no column for it.
+func (r *Repository) FindActivatable(ctx context.Context) ([]domain.Coupon, error) {
+	coupons, err := r.query(ctx, `SELECT data, version FROM coupons
+		WHERE json_extract(data, '$.RequiresActivation')
+		AND NOT EXISTS (SELECT 1 FROM coupon_assignments WHERE coupon_assignments.code = coupons.code)
+		ORDER BY code`)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	return coupons, nil
+}
+
+// query reads the coupons the query selects as data and version.
+func (r *Repository) query(ctx context.Context, query string, args ...any) ([]domain.Coupon, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := make([]domain.Coupon, 0)
+	for rows.Next() {
+		var data string
+		var version int
+		if err := rows.Scan(&data, &version); err != nil {
+			return nil, err
+		}
+
+		coupon, err := decode(data, version)
+		if err != nil {
+			return nil, err
+		}
+		coupons = append(coupons, *coupon)
+	}
+
+	return coupons, rows.Err()
+}
+
+// decode reads a coupon document. The version column takes precedence over
+// the document, as Save bumps it without knowing the version it replaces.
+func decode(data string, version int) (*domain.Coupon, error) {
+	coupon, err := document.Decode([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	coupon.Version = version
+	return coupon, nil
+}
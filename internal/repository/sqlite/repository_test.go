@@ -0,0 +1,90 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/repositorytest"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/sqlite"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+)
+
+func newRepository(t *testing.T) service.Repository {
+	repo, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "coupons.db"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestRepository(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRepository in long mode.")
+	}
+
+	repositorytest.Run(t, newRepository)
+}
+
+func TestReopen(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReopen in long mode.")
+	}
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "coupons.db")
+
+	repo, err := sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	coupon := domain.Coupon{ID: "id1", Code: "persistent", Discount: 10}
+	if err := repo.Save(ctx, coupon); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	// Opening the migrated database again must not reapply migrations.
+	repo, err = sqlite.Open(ctx, path)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	defer repo.Close()
+
+	got, err := repo.FindByCode(ctx, coupon.Code)
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if got.ID != coupon.ID || got.Discount != coupon.Discount {
+		t.Errorf("expected coupon to be %v, got %v", coupon, *got)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestClosed in long mode.")
+	}
+
+	ctx := context.Background()
+	repo, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "coupons.db"))
+	if err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Fatalf("expected err to be nil, got %v", err)
+	}
+
+	if _, err := repo.FindByCode(ctx, "code"); !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+	}
+	if err := repo.Save(ctx, domain.Coupon{ID: "id1", Code: "code"}); !errors.Is(err, repository.ErrUnavailable) {
+		t.Errorf("expected err to be %v, got %v", repository.ErrUnavailable, err)
+	}
+}
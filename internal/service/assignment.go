@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+var (
+	ErrInvalidAssignment = errors.New("invalid assignment")
+	ErrInvalidCustomer   = errors.New("invalid customer")
+	ErrNotAssigned       = errors.New("coupon not assigned to customer")
+)
+
+func (s Service) GetCustomerCoupons(ctx context.Context, customer domain.Customer) ([]domain.Coupon, error) {
+	if customer.ID == "" {
+		return nil, ErrInvalidCustomer
+	}
+
+	return s.repo.FindByCustomer(ctx, customer)
+}
+
+func validAssignment(assignment domain.Assignment) bool {
+	for _, id := range assignment.CustomerIDs {
+		if id == "" {
+			return false
+		}
+	}
+
+	for _, segment := range assignment.Segments {
+		if segment == "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,131 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestGetCustomerCoupons(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetCustomerCoupons in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		customer    domain.Customer
+		setupMocks  func(*mocks.Repository, domain.Customer)
+		want        []domain.Coupon
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Successful retrieval",
+			customer: domain.Customer{ID: "c1", Segments: []string{"gold"}},
+			setupMocks: func(repo *mocks.Repository, customer domain.Customer) {
+				repo.On("FindByCustomer", mock.MatchedBy(func(ctx context.Context) bool { return true }), customer).
+					Return([]domain.Coupon{
+						{ID: "id1", Code: "test1", Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}},
+						{ID: "id2", Code: "test2", Assignment: domain.Assignment{Segments: []string{"gold"}}},
+					}, nil).
+					Once()
+			},
+			want: []domain.Coupon{
+				{ID: "id1", Code: "test1", Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}},
+				{ID: "id2", Code: "test2", Assignment: domain.Assignment{Segments: []string{"gold"}}},
+			},
+		},
+		{
+			name:        "Missing customer ID",
+			customer:    domain.Customer{Segments: []string{"gold"}},
+			setupMocks:  func(repo *mocks.Repository, customer domain.Customer) {},
+			expectedErr: service.ErrInvalidCustomer,
+		},
+		{
+			name:     "Repository error",
+			customer: domain.Customer{ID: "c1"},
+			setupMocks: func(repo *mocks.Repository, customer domain.Customer) {
+				repo.On("FindByCustomer", mock.MatchedBy(func(ctx context.Context) bool { return true }), customer).
+					Return(nil, errors.New("fatal error")).
+					Once()
+			},
+			expectedErr: errors.New("fatal error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo, tc.customer)
+			defer repo.AssertExpectations(t)
+
+			srv := service.New(repo)
+
+			got, err := srv.GetCustomerCoupons(context.Background(), tc.customer)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyAssignedCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyAssignedCoupon in long mode.")
+	}
+
+	coupon := &domain.Coupon{
+		ID:       "id1",
+		Code:     "LOYAL10",
+		Discount: 10,
+		Assignment: domain.Assignment{
+			CustomerIDs: []string{"c1"},
+			Segments:    []string{"gold"},
+		},
+	}
+
+	type testCase struct {
+		name        string
+		customer    domain.Customer
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "Assigned customer", customer: domain.Customer{ID: "c1"}},
+		{name: "Customer in assigned segment", customer: domain.Customer{ID: "c2", Segments: []string{"silver", "gold"}}},
+		{name: "Other customer", customer: domain.Customer{ID: "c2"}, expectedErr: service.ErrNotAssigned},
+		{name: "Anonymous basket", customer: domain.Customer{}, expectedErr: service.ErrNotAssigned},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), coupon.Code).
+				Return(coupon, nil).
+				Once()
+
+			srv := service.New(repo)
+
+			_, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100, Customer: tc.customer}, coupon.Code)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrBackupsDisabled = errors.New("backups not configured")
+
+// WithBackups enables streaming a copy of the coupon database.
+func WithBackups(backups BackupRepository) Option {
+	return func(s *Service) {
+		s.backups = backups
+	}
+}
+
+// Backup writes a consistent copy of the database to w.
+func (s Service) Backup(ctx context.Context, w io.Writer) error {
+	if s.backups == nil {
+		return ErrBackupsDisabled
+	}
+
+	return s.backups.Backup(ctx, w)
+}
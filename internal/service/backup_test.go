@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestBackup(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestBackup in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		setupMock   func() []service.Option
+		expectedErr error
+	}
+
+	backupErr := errors.New("backup failed")
+
+	testCases := []testCase{
+		{
+			name:        "Backups disabled",
+			setupMock:   func() []service.Option { return nil },
+			expectedErr: service.ErrBackupsDisabled,
+		},
+		{
+			name: "Backup written",
+			setupMock: func() []service.Option {
+				backups := mocks.NewBackupRepository(t)
+				backups.On("Backup", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(nil).
+					Once()
+				return []service.Option{service.WithBackups(backups)}
+			},
+		},
+		{
+			name: "Backup failed",
+			setupMock: func() []service.Option {
+				backups := mocks.NewBackupRepository(t)
+				backups.On("Backup", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(backupErr).
+					Once()
+				return []service.Option{service.WithBackups(backups)}
+			},
+			expectedErr: backupErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := service.New(mocks.NewRepository(t), tc.setupMock()...)
+
+			var backup bytes.Buffer
+			if err := srv.Backup(context.Background(), &backup); !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected err to be %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+var ErrCacheDisabled = errors.New("coupon cache not configured")
+
+// WithCache enables reporting the statistics of the cache in front of the
+// coupon repository.
+func WithCache(cache CacheRepository) Option {
+	return func(s *Service) {
+		s.cache = cache
+	}
+}
+
+// CacheStats returns the hits and misses of the coupon cache so far.
+func (s Service) CacheStats(ctx context.Context) (*domain.CacheStats, error) {
+	if s.cache == nil {
+		return nil, ErrCacheDisabled
+	}
+
+	stats := s.cache.Stats()
+	return &stats, nil
+}
@@ -0,0 +1,59 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestCacheStats(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCacheStats in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		setupMock   func() []service.Option
+		expectedErr error
+		want        *domain.CacheStats
+	}
+
+	testCases := []testCase{
+		{
+			name:        "Cache disabled",
+			setupMock:   func() []service.Option { return nil },
+			expectedErr: service.ErrCacheDisabled,
+		},
+		{
+			name: "Cache stats",
+			setupMock: func() []service.Option {
+				cache := mocks.NewCacheRepository(t)
+				cache.On("Stats").
+					Return(domain.CacheStats{Hits: 3, Misses: 1, Entries: 2}).
+					Once()
+				return []service.Option{service.WithCache(cache)}
+			},
+			want: &domain.CacheStats{Hits: 3, Misses: 1, Entries: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := service.New(mocks.NewRepository(t), tc.setupMock()...)
+
+			stats, err := srv.CacheStats(context.Background())
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("expected err to be %v, got %v", tc.expectedErr, err)
+			}
+			if !reflect.DeepEqual(stats, tc.want) {
+				t.Errorf("expected stats to be %+v, got %+v", tc.want, stats)
+			}
+		})
+	}
+}
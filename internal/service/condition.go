@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+// maxConditionDepth bounds how deeply all and any conditions may be nested.
+const maxConditionDepth = 4
+
+var (
+	ErrInvalidCondition  = errors.New("invalid condition")
+	ErrInvalidBasketLine = errors.New("invalid basket line")
+	ErrConditionsNotMet  = errors.New("basket does not meet coupon conditions")
+)
+
+func validConditions(conditions []domain.Condition, depth int) bool {
+	if depth > maxConditionDepth {
+		return false
+	}
+
+	for _, condition := range conditions {
+		switch condition.Type {
+		case domain.ConditionAll, domain.ConditionAny:
+			if len(condition.Conditions) == 0 || !validConditions(condition.Conditions, depth+1) {
+				return false
+			}
+		case domain.ConditionMinQuantity, domain.ConditionMinValue:
+			if condition.Min <= 0 {
+				return false
+			}
+		case domain.ConditionContainsSKU:
+			if strings.TrimSpace(condition.SKU) == "" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func validLines(lines []domain.Line) bool {
+	for _, line := range lines {
+		if line.Quantity <= 0 || line.UnitPrice < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func checkConditions(coupon *domain.Coupon, basket domain.Basket) error {
+	for _, condition := range coupon.Conditions {
+		if !condition.Matches(basket.Lines) {
+			return ErrConditionsNotMet
+		}
+	}
+	return nil
+}
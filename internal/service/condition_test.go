@@ -0,0 +1,156 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestApplyCouponConditions(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyCouponConditions in long mode.")
+	}
+
+	threeDrinks := domain.Condition{Type: domain.ConditionMinQuantity, Category: "drinks", Min: 3}
+	fiftyWithoutDeposit := domain.Condition{Type: domain.ConditionMinValue, Min: 50}
+	coffeeOrTea := domain.Condition{Type: domain.ConditionAny, Conditions: []domain.Condition{
+		{Type: domain.ConditionContainsSKU, SKU: "coffee-500g"},
+		{Type: domain.ConditionContainsSKU, SKU: "tea-20"},
+	}}
+
+	water := domain.Line{SKU: "water-1l", Category: "drinks", Quantity: 3, UnitPrice: 10}
+	deposit := domain.Line{SKU: "pfand-025", Category: "drinks", Quantity: 3, UnitPrice: 25, Deposit: true}
+	tea := domain.Line{SKU: "TEA-20", Category: "food", Quantity: 1, UnitPrice: 30}
+
+	type testCase struct {
+		name        string
+		conditions  []domain.Condition
+		lines       []domain.Line
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:       "Enough items of the category",
+			conditions: []domain.Condition{threeDrinks},
+			lines:      []domain.Line{water},
+		},
+		{
+			name:        "Deposits do not count as items",
+			conditions:  []domain.Condition{threeDrinks},
+			lines:       []domain.Line{{SKU: "water-1l", Category: "drinks", Quantity: 2, UnitPrice: 10}, deposit},
+			expectedErr: service.ErrConditionsNotMet,
+		},
+		{
+			name:        "Deposits do not count toward the minimum value",
+			conditions:  []domain.Condition{fiftyWithoutDeposit},
+			lines:       []domain.Line{water, deposit},
+			expectedErr: service.ErrConditionsNotMet,
+		},
+		{
+			name:       "Minimum value over eligible lines",
+			conditions: []domain.Condition{fiftyWithoutDeposit},
+			lines:      []domain.Line{water, tea, deposit},
+		},
+		{
+			name:       "Any of the SKUs",
+			conditions: []domain.Condition{coffeeOrTea},
+			lines:      []domain.Line{water, tea},
+		},
+		{
+			name:        "None of the SKUs",
+			conditions:  []domain.Condition{coffeeOrTea},
+			lines:       []domain.Line{water},
+			expectedErr: service.ErrConditionsNotMet,
+		},
+		{
+			name:        "Every condition has to match",
+			conditions:  []domain.Condition{threeDrinks, coffeeOrTea},
+			lines:       []domain.Line{water},
+			expectedErr: service.ErrConditionsNotMet,
+		},
+		{
+			name:        "Basket without lines",
+			conditions:  []domain.Condition{threeDrinks},
+			expectedErr: service.ErrConditionsNotMet,
+		},
+		{
+			name:  "No conditions",
+			lines: []domain.Line{deposit},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "bundle").
+				Return(&domain.Coupon{Code: "bundle", Discount: 10, Conditions: tc.conditions}, nil).
+				Once()
+
+			srv := service.New(repo)
+
+			basket, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100, Lines: tc.lines}, "bundle")
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, 10, basket.AppliedDiscount)
+		})
+	}
+}
+
+func TestApplyCouponInvalidLines(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyCouponInvalidLines in long mode.")
+	}
+
+	srv := service.New(mocks.NewRepository(t))
+
+	_, err := srv.ApplyCoupon(context.Background(), domain.Basket{
+		Value: 100,
+		Lines: []domain.Line{{SKU: "water-1l", Quantity: 0, UnitPrice: 10}},
+	}, "bundle")
+	assert.ErrorIs(t, err, service.ErrInvalidBasketLine)
+}
+
+func TestCreateCouponConditions(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateCouponConditions in long mode.")
+	}
+
+	nested := domain.Condition{Type: domain.ConditionContainsSKU, SKU: "tea-20"}
+	for i := 0; i < 4; i++ {
+		nested = domain.Condition{Type: domain.ConditionAll, Conditions: []domain.Condition{nested}}
+	}
+
+	testCases := map[string]domain.Condition{
+		"Unknown type":      {Type: "maxQuantity", Min: 3},
+		"Missing minimum":   {Type: domain.ConditionMinQuantity, Category: "drinks"},
+		"Missing SKU":       {Type: domain.ConditionContainsSKU, SKU: " "},
+		"Empty any":         {Type: domain.ConditionAny},
+		"Invalid nested":    {Type: domain.ConditionAll, Conditions: []domain.Condition{{Type: domain.ConditionMinValue}}},
+		"Nested too deeply": nested,
+	}
+
+	for name, condition := range testCases {
+		t.Run(name, func(t *testing.T) {
+			srv := service.New(mocks.NewRepository(t))
+
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{
+				Code:       "bundle",
+				Discount:   10,
+				Conditions: []domain.Condition{condition},
+			})
+			assert.ErrorIs(t, err, service.ErrInvalidCondition)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/discount"
+)
+
+var (
+	ErrInvalidStrategy       = errors.New("invalid discount strategy")
+	ErrInvalidDiscountParams = errors.New("invalid discount parameters")
+	ErrDiscountNotApplicable = errors.New("discount not applicable")
+)
+
+// WithDiscounts replaces the registry of discount strategies coupons can
+// reference, e.g. to add custom strategies next to the built-in ones.
+func WithDiscounts(registry *discount.Registry) Option {
+	return func(s *Service) {
+		s.discounts = registry
+	}
+}
+
+func (s Service) strategy(name string) (discount.Strategy, error) {
+	if name == "" {
+		name = discount.Fixed
+	}
+
+	strategy, ok := s.discounts.Lookup(name)
+	if !ok {
+		return nil, ErrInvalidStrategy
+	}
+	return strategy, nil
+}
+
+// validateDiscount checks that the strategy of a new coupon exists and
+// accepts its discount and parameters. The strategy decides which discounts
+// make sense.
+func (s Service) validateDiscount(coupon domain.Coupon) error {
+	strategy, err := s.strategy(coupon.Strategy)
+	if err != nil {
+		return err
+	}
+
+	if err := strategy.Validate(discountCoupon(coupon)); err != nil {
+		if errors.Is(err, discount.ErrInvalidDiscount) {
+			return ErrInvalidDiscount
+		}
+		return ErrInvalidDiscountParams
+	}
+	return nil
+}
+
+// calculate returns the discount the strategy of the coupon grants for the
+// basket, which is never more than the basket value.
+func (s Service) calculate(coupon *domain.Coupon, basket domain.Basket) (int, error) {
+	strategy, err := s.strategy(coupon.Strategy)
+	if err != nil {
+		return 0, err
+	}
+
+	amount, err := strategy.Calculate(discountCoupon(*coupon), discountBasket(basket))
+	if err != nil {
+		if errors.Is(err, discount.ErrNotApplicable) {
+			return 0, ErrDiscountNotApplicable
+		}
+		return 0, fmt.Errorf("discount strategy %q: %w", coupon.Strategy, err)
+	}
+
+	if amount < 0 {
+		return 0, fmt.Errorf("discount strategy %q returned negative discount %d", coupon.Strategy, amount)
+	}
+
+	if amount > basket.Value {
+		return 0, ErrInvalidBasketValue
+	}
+
+	return amount, nil
+}
+
+func discountCoupon(coupon domain.Coupon) discount.Coupon {
+	return discount.Coupon{
+		Discount:       coupon.Discount,
+		MinBasketValue: coupon.MinBasketValue,
+		Params:         coupon.Params,
+	}
+}
+
+func discountBasket(basket domain.Basket) discount.Basket {
+	lines := make([]discount.Line, 0, len(basket.Lines))
+	for _, line := range basket.Lines {
+		lines = append(lines, discount.Line{
+			SKU:       line.SKU,
+			Category:  line.Category,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Deposit:   line.Deposit,
+		})
+	}
+
+	return discount.Basket{
+		Value:      basket.Value,
+		CustomerID: basket.Customer.ID,
+		Lines:      lines,
+	}
+}
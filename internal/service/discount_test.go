@@ -0,0 +1,230 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/discount"
+)
+
+// cheapestFree takes the cheapest eligible item off the basket.
+type cheapestFree struct{}
+
+func (cheapestFree) Validate(coupon discount.Coupon) error {
+	if len(coupon.Params) > 0 {
+		return discount.ErrInvalidParams
+	}
+	return nil
+}
+
+func (cheapestFree) Calculate(_ discount.Coupon, basket discount.Basket) (int, error) {
+	cheapest := 0
+	for _, line := range basket.Lines {
+		if !line.Deposit && (cheapest == 0 || line.UnitPrice < cheapest) {
+			cheapest = line.UnitPrice
+		}
+	}
+	if cheapest == 0 {
+		return 0, discount.ErrNotApplicable
+	}
+	return cheapest, nil
+}
+
+func TestApplyCouponStrategy(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyCouponStrategy in long mode.")
+	}
+
+	registry := discount.NewRegistry()
+	require.NoError(t, registry.Register("cheapestFree", cheapestFree{}))
+
+	lines := []domain.Line{
+		{SKU: "tea-20", Quantity: 1, UnitPrice: 300},
+		{SKU: "pfand-025", Quantity: 1, UnitPrice: 25, Deposit: true},
+		{SKU: "coffee-500g", Quantity: 1, UnitPrice: 900},
+	}
+
+	type testCase struct {
+		name        string
+		coupon      domain.Coupon
+		basket      domain.Basket
+		want        int
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Default fixed amount",
+			coupon: domain.Coupon{Code: "strategy", Discount: 10},
+			basket: domain.Basket{Value: 1225},
+			want:   10,
+		},
+		{
+			name:   "Built-in percentage",
+			coupon: domain.Coupon{Code: "strategy", Discount: 10, Strategy: discount.Percent},
+			basket: domain.Basket{Value: 1225},
+			want:   122,
+		},
+		{
+			name:   "Custom strategy",
+			coupon: domain.Coupon{Code: "strategy", Strategy: "cheapestFree"},
+			basket: domain.Basket{Value: 1225, Lines: lines},
+			want:   300,
+		},
+		{
+			name:        "Custom strategy not applicable",
+			coupon:      domain.Coupon{Code: "strategy", Strategy: "cheapestFree"},
+			basket:      domain.Basket{Value: 1225},
+			expectedErr: service.ErrDiscountNotApplicable,
+		},
+		{
+			name:        "Discount above basket value",
+			coupon:      domain.Coupon{Code: "strategy", Strategy: "cheapestFree"},
+			basket:      domain.Basket{Value: 200, Lines: lines[:1]},
+			expectedErr: service.ErrInvalidBasketValue,
+		},
+		{
+			name:        "Strategy no longer registered",
+			coupon:      domain.Coupon{Code: "strategy", Strategy: "bogo"},
+			basket:      domain.Basket{Value: 1225},
+			expectedErr: service.ErrInvalidStrategy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "strategy").
+				Return(&tc.coupon, nil).
+				Once()
+
+			srv := service.New(repo, service.WithDiscounts(registry))
+
+			basket, err := srv.ApplyCoupon(context.Background(), tc.basket, "strategy")
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, basket.AppliedDiscount)
+			assert.Equal(t, tc.basket.Value-tc.want, basket.Value)
+		})
+	}
+}
+
+func TestCreateCouponStrategy(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateCouponStrategy in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		coupon      domain.Coupon
+		setupMocks  func(*mocks.Repository)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Strategy with parameters",
+			coupon: domain.Coupon{Code: "strategy", Discount: 20, Strategy: discount.Percent, Params: json.RawMessage(`{"max":500}`)},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }),
+					mock.MatchedBy(func(coupon domain.Coupon) bool {
+						return coupon.Strategy == discount.Percent && string(coupon.Params) == `{"max":500}`
+					})).
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:        "Unknown strategy",
+			coupon:      domain.Coupon{Code: "strategy", Discount: 20, Strategy: "bogo"},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidStrategy,
+		},
+		{
+			name:        "Invalid parameters",
+			coupon:      domain.Coupon{Code: "strategy", Discount: 20, Strategy: discount.Percent, Params: json.RawMessage(`{"max":"500"}`)},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidDiscountParams,
+		},
+		{
+			name:        "Percentage above 100",
+			coupon:      domain.Coupon{Code: "strategy", Discount: 120, Strategy: discount.Percent},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidDiscount,
+		},
+		{
+			name:   "Custom strategy decides on the discount",
+			coupon: domain.Coupon{Code: "strategy", Discount: 500, Strategy: "cheapestFree"},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(nil).
+					Once()
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo)
+
+			registry := discount.NewRegistry()
+			require.NoError(t, registry.Register("cheapestFree", cheapestFree{}))
+			srv := service.New(repo, service.WithDiscounts(registry))
+
+			err := srv.CreateCoupon(context.Background(), tc.coupon)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
+
+func TestPromotionStrategy(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPromotionStrategy in long mode.")
+	}
+
+	registry := discount.NewRegistry()
+	require.NoError(t, registry.Register("cheapestFree", cheapestFree{}))
+
+	ctx := context.Background()
+	srv := service.New(mocks.NewRepository(t),
+		service.WithPromotions(memory.NewPromotions()),
+		service.WithDiscounts(registry))
+
+	_, err := srv.CreatePromotion(ctx, domain.Promotion{Name: "bogo", Active: true, Coupon: domain.Coupon{Strategy: "bogo"}})
+	assert.ErrorIs(t, err, service.ErrInvalidStrategy)
+
+	_, err = srv.CreatePromotion(ctx, domain.Promotion{Name: "free tea", Active: true,
+		Coupon: domain.Coupon{Strategy: "cheapestFree", Params: json.RawMessage(`{"sku":"tea-20"}`)}})
+	assert.ErrorIs(t, err, service.ErrInvalidDiscountParams)
+
+	promotion, err := srv.CreatePromotion(ctx, domain.Promotion{Name: "free tea", Active: true,
+		Coupon: domain.Coupon{Strategy: "cheapestFree"}})
+	require.NoError(t, err)
+
+	pricing, err := srv.PriceBasket(ctx, domain.Basket{Value: 1200, Lines: []domain.Line{
+		{SKU: "tea-20", Quantity: 1, UnitPrice: 300},
+		{SKU: "coffee-500g", Quantity: 1, UnitPrice: 900},
+	}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AppliedDiscount{{PromotionID: promotion.ID, Name: "free tea", Discount: 300}}, pricing.Discounts)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+var (
+	ErrInvalidEligibility = errors.New("invalid eligibility")
+	ErrChannelNotAllowed  = errors.New("coupon not valid in this channel")
+	ErrStoreNotAllowed    = errors.New("coupon not valid in this store")
+	ErrRegionNotAllowed   = errors.New("coupon not valid in this region")
+)
+
+func validEligibility(eligibility domain.Eligibility) bool {
+	for _, restriction := range []domain.Restriction{eligibility.Channels, eligibility.Stores, eligibility.Regions} {
+		if !validValues(restriction.Allow) || !validValues(restriction.Deny) {
+			return false
+		}
+	}
+	return true
+}
+
+func validValues(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			return false
+		}
+	}
+	return true
+}
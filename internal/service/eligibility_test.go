@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestApplyCouponEligibility(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyCouponEligibility in long mode.")
+	}
+
+	onlineOnly := domain.Eligibility{Channels: domain.Restriction{Allow: []string{"online"}}}
+	notInStore := domain.Eligibility{Stores: domain.Restriction{Deny: []string{"s13"}}}
+	germanyButBerlin := domain.Eligibility{
+		Regions: domain.Restriction{Allow: []string{"DE"}},
+		Stores:  domain.Restriction{Deny: []string{"berlin-1"}},
+	}
+
+	type testCase struct {
+		name        string
+		eligibility domain.Eligibility
+		basket      domain.Basket
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:        "Allowed channel",
+			eligibility: onlineOnly,
+			basket:      domain.Basket{Value: 100, Channel: "Online"},
+		},
+		{
+			name:        "Other channel",
+			eligibility: onlineOnly,
+			basket:      domain.Basket{Value: 100, Channel: "store"},
+			expectedErr: service.ErrChannelNotAllowed,
+		},
+		{
+			name:        "Missing channel with allow list",
+			eligibility: onlineOnly,
+			basket:      domain.Basket{Value: 100},
+			expectedErr: service.ErrChannelNotAllowed,
+		},
+		{
+			name:        "Denied store",
+			eligibility: notInStore,
+			basket:      domain.Basket{Value: 100, StoreID: "s13"},
+			expectedErr: service.ErrStoreNotAllowed,
+		},
+		{
+			name:        "Missing store with deny list",
+			eligibility: notInStore,
+			basket:      domain.Basket{Value: 100},
+		},
+		{
+			name:        "Allowed region",
+			eligibility: germanyButBerlin,
+			basket:      domain.Basket{Value: 100, Region: "de", StoreID: "munich-1"},
+		},
+		{
+			name:        "Denied store in allowed region",
+			eligibility: germanyButBerlin,
+			basket:      domain.Basket{Value: 100, Region: "DE", StoreID: "berlin-1"},
+			expectedErr: service.ErrStoreNotAllowed,
+		},
+		{
+			name:        "Other region",
+			eligibility: germanyButBerlin,
+			basket:      domain.Basket{Value: 100, Region: "AT", StoreID: "vienna-1"},
+			expectedErr: service.ErrRegionNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "local").
+				Return(&domain.Coupon{Code: "local", Discount: 10, Eligibility: tc.eligibility}, nil).
+				Once()
+
+			srv := service.New(repo)
+
+			basket, err := srv.ApplyCoupon(context.Background(), tc.basket, "local")
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, 10, basket.AppliedDiscount)
+		})
+	}
+}
+
+func TestCreateCouponEligibility(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateCouponEligibility in long mode.")
+	}
+
+	srv := service.New(mocks.NewRepository(t))
+
+	err := srv.CreateCoupon(context.Background(), domain.Coupon{
+		Code:        "local",
+		Discount:    10,
+		Eligibility: domain.Eligibility{Regions: domain.Restriction{Deny: []string{" "}}},
+	})
+	assert.ErrorIs(t, err, service.ErrInvalidEligibility)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+// ExplainCoupon evaluates every rule of the coupon against the basket,
+// without stopping at the first failing one, and reports the outcome of
+// each.
+func (s Service) ExplainCoupon(ctx context.Context, basket domain.Basket, code string) (*domain.Explanation, error) {
+	code = s.normalize(code)
+
+	if code == "" {
+		return nil, ErrInvalidCode
+	}
+
+	if basket.Value <= 0 {
+		return nil, ErrInvalidBasketValue
+	}
+
+	if !validTimeZone(basket.TimeZone) {
+		return nil, ErrInvalidTimeZone
+	}
+
+	if !validLines(basket.Lines) {
+		return nil, ErrInvalidBasketLine
+	}
+
+	coupon, err := s.findCoupon(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &domain.Explanation{
+		Code:     code,
+		Eligible: true,
+		Checks:   make([]domain.RuleCheck, 0),
+	}
+
+	for _, rule := range s.rules() {
+		check := domain.RuleCheck{Rule: rule.name, Passed: true}
+
+		if err := rule.check(ctx, coupon, basket); err != nil {
+			if !isRuleError(err) {
+				return nil, err
+			}
+			check.Passed = false
+			check.Reason = err.Error()
+			explanation.Eligible = false
+		}
+
+		explanation.Checks = append(explanation.Checks, check)
+	}
+
+	if explanation.Eligible {
+		if explanation.Discount, err = s.calculate(coupon, basket); err != nil {
+			return nil, err
+		}
+	}
+
+	return explanation, nil
+}
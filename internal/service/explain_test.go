@@ -0,0 +1,119 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestExplainCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestExplainCoupon in long mode.")
+	}
+
+	coupon := &domain.Coupon{
+		Code:           "local",
+		Discount:       10,
+		MinBasketValue: 50,
+		Eligibility: domain.Eligibility{
+			Channels: domain.Restriction{Allow: []string{"online"}},
+			Regions:  domain.Restriction{Deny: []string{"AT"}},
+		},
+	}
+
+	checks := func(failed map[string]error) []domain.RuleCheck {
+		rules := []string{"assignment", "activation", "schedule", "channel", "store", "region", "basketValue", "minBasketValue", "conditions", "expression", "usage"}
+		checks := make([]domain.RuleCheck, 0, len(rules))
+		for _, rule := range rules {
+			check := domain.RuleCheck{Rule: rule, Passed: true}
+			if err, ok := failed[rule]; ok {
+				check.Passed = false
+				check.Reason = err.Error()
+			}
+			checks = append(checks, check)
+		}
+		return checks
+	}
+
+	type testCase struct {
+		name        string
+		basket      domain.Basket
+		setupMocks  func(*mocks.Repository)
+		want        *domain.Explanation
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Eligible coupon",
+			basket: domain.Basket{Value: 100, Channel: "online", Region: "DE"},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "local").
+					Return(coupon, nil).
+					Once()
+			},
+			want: &domain.Explanation{Code: "local", Eligible: true, Discount: 10, Checks: checks(nil)},
+		},
+		{
+			name:   "Every failing rule reported",
+			basket: domain.Basket{Value: 40, Channel: "store", Region: "AT"},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "local").
+					Return(coupon, nil).
+					Once()
+			},
+			want: &domain.Explanation{Code: "local", Checks: checks(map[string]error{
+				"channel":        service.ErrChannelNotAllowed,
+				"region":         service.ErrRegionNotAllowed,
+				"minBasketValue": service.ErrMinBasketValue,
+			})},
+		},
+		{
+			name:   "Unknown coupon",
+			basket: domain.Basket{Value: 100},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "local").
+					Return(nil, repository.ErrNotFound).
+					Once()
+			},
+			expectedErr: service.ErrNotFound,
+		},
+		{
+			name:   "Repository error",
+			basket: domain.Basket{Value: 100},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "local").
+					Return(nil, errors.New("fatal error")).
+					Once()
+			},
+			expectedErr: errors.New("fatal error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo)
+
+			srv := service.New(repo)
+
+			got, err := srv.ExplainCoupon(context.Background(), tc.basket, "local")
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
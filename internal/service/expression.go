@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/expression"
+)
+
+var (
+	ErrExpressionsDisabled = errors.New("expressions not configured")
+	ErrInvalidExpression   = errors.New("invalid expression")
+	ErrExpressionNotMet    = errors.New("basket does not meet coupon expression")
+	ErrExpressionFailed    = errors.New("coupon expression could not be evaluated")
+)
+
+// WithExpressions enables coupon expressions, which are compiled by engine
+// when a coupon is created and evaluated from its cache afterwards.
+func WithExpressions(engine *expression.Engine) Option {
+	return func(s *Service) {
+		s.expressions = engine
+	}
+}
+
+// compileExpression validates the expression of a new coupon. The returned
+// error wraps ErrInvalidExpression with the compiler's description.
+func (s Service) compileExpression(expr string) error {
+	if expr == "" {
+		return nil
+	}
+
+	if s.expressions == nil {
+		return ErrExpressionsDisabled
+	}
+
+	if err := s.expressions.Compile(expr); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+	}
+	return nil
+}
+
+func (s Service) checkExpression(ctx context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+	if coupon.Expression == "" {
+		return nil
+	}
+
+	if s.expressions == nil {
+		return ErrExpressionsDisabled
+	}
+
+	location, err := basketLocation(basket.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.expressions.Eval(ctx, coupon.Expression, expressionInput(basket, s.now().In(location)))
+	if err != nil {
+		return ErrExpressionFailed
+	}
+	if !ok {
+		return ErrExpressionNotMet
+	}
+	return nil
+}
+
+func expressionInput(basket domain.Basket, now time.Time) expression.Input {
+	lines := make([]expression.Line, 0, len(basket.Lines))
+	for _, line := range basket.Lines {
+		lines = append(lines, expression.Line{
+			SKU:       line.SKU,
+			Category:  line.Category,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Deposit:   line.Deposit,
+		})
+	}
+
+	return expression.Input{
+		Basket: expression.Basket{Total: basket.Value, Lines: lines},
+		Customer: expression.Customer{
+			ID:       basket.Customer.ID,
+			Tier:     basket.Customer.Tier,
+			Segments: basket.Customer.Segments,
+		},
+		Context: expression.Context{
+			Channel:  basket.Channel,
+			Store:    basket.StoreID,
+			Region:   basket.Region,
+			TimeZone: basket.TimeZone,
+			Now:      now,
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/expression"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestApplyCouponExpression(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyCouponExpression in long mode.")
+	}
+
+	engine, err := expression.New(expression.DefaultCostLimit)
+	require.NoError(t, err)
+
+	// Friday 23:30 UTC is already Saturday in Berlin.
+	now := time.Date(2024, 10, 4, 23, 30, 0, 0, time.UTC)
+	gold := domain.Customer{ID: "c1", Tier: "gold"}
+
+	type testCase struct {
+		name        string
+		expr        string
+		basket      domain.Basket
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Matching basket and customer",
+			expr:   `basket.total >= 2000 && customer.tier == "gold"`,
+			basket: domain.Basket{Value: 2500, TimeZone: "UTC", Customer: gold},
+		},
+		{
+			name:        "Customer of another tier",
+			expr:        `basket.total >= 2000 && customer.tier == "gold"`,
+			basket:      domain.Basket{Value: 2500, TimeZone: "UTC", Customer: domain.Customer{ID: "c2", Tier: "silver"}},
+			expectedErr: service.ErrExpressionNotMet,
+		},
+		{
+			name:        "Anonymous customer without segments",
+			expr:        `"staff" in customer.segments`,
+			basket:      domain.Basket{Value: 2500, TimeZone: "UTC"},
+			expectedErr: service.ErrExpressionNotMet,
+		},
+		{
+			name: "Basket lines and context",
+			expr: `context.channel == "online" && basket.lines.exists(l, l.sku == "tea-20" && l.quantity >= 2)`,
+			basket: domain.Basket{Value: 2500, TimeZone: "UTC", Channel: "online", Lines: []domain.Line{
+				{SKU: "tea-20", Quantity: 2, UnitPrice: 300},
+			}},
+		},
+		{
+			name:   "Time in the basket time zone",
+			expr:   `context.now.getDayOfWeek(context.timeZone) == 6`,
+			basket: domain.Basket{Value: 2500, TimeZone: "Europe/Berlin"},
+		},
+		{
+			name:        "Missing time zone",
+			expr:        `context.now.getDayOfWeek(context.timeZone) == 6`,
+			basket:      domain.Basket{Value: 2500},
+			expectedErr: service.ErrInvalidTimeZone,
+		},
+		{
+			name:        "Failing evaluation",
+			expr:        `basket.total / (basket.total - 2500) > 0`,
+			basket:      domain.Basket{Value: 2500, TimeZone: "UTC"},
+			expectedErr: service.ErrExpressionFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "rule").
+				Return(&domain.Coupon{Code: "rule", Discount: 10, Expression: tc.expr}, nil).
+				Once()
+
+			srv := service.New(repo, service.WithExpressions(engine), service.WithClock(func() time.Time { return now }))
+
+			basket, err := srv.ApplyCoupon(context.Background(), tc.basket, "rule")
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, 10, basket.AppliedDiscount)
+		})
+	}
+}
+
+func TestCreateCouponExpression(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateCouponExpression in long mode.")
+	}
+
+	engine, err := expression.New(expression.DefaultCostLimit)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name        string
+		expr        string
+		opts        []service.Option
+		setupMocks  func(*mocks.Repository)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Valid expression",
+			expr: `customer.tier == "gold"`,
+			opts: []service.Option{service.WithExpressions(engine)},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }),
+					mock.MatchedBy(func(coupon domain.Coupon) bool { return coupon.Expression == `customer.tier == "gold"` })).
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:        "Unknown field",
+			expr:        `customer.level == "gold"`,
+			opts:        []service.Option{service.WithExpressions(engine)},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidExpression,
+		},
+		{
+			name:        "Not a boolean",
+			expr:        `basket.total * 2`,
+			opts:        []service.Option{service.WithExpressions(engine)},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidExpression,
+		},
+		{
+			name:        "Expressions not configured",
+			expr:        `customer.tier == "gold"`,
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrExpressionsDisabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo)
+
+			srv := service.New(repo, tc.opts...)
+
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "rule", Discount: 10, Expression: tc.expr})
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
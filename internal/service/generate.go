@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+const (
+	MaxBatchCount = 1_000_000
+
+	maxGenerateAttempts = 10
+)
+
+var (
+	ErrInvalidCount       = errors.New("invalid count")
+	ErrInvalidLength      = errors.New("invalid code length")
+	ErrInvalidAlphabet    = errors.New("invalid alphabet")
+	ErrInvalidPrefix      = errors.New("invalid prefix")
+	ErrCodeSpaceTooSmall  = errors.New("code space too small for count")
+	ErrCodeSpaceExhausted = errors.New("could not find unused code")
+	ErrJobNotFound        = errors.New("job not found")
+	ErrJobNotCompleted    = errors.New("job not completed")
+	ErrTooManyJobs        = errors.New("too many jobs running")
+	ErrJobsClosed         = errors.New("service is shutting down")
+)
+
+func (s Service) GenerateCoupons(_ context.Context, batch domain.CodeBatch) (*domain.Job, error) {
+	if batch.Count <= 0 || batch.Count > MaxBatchCount {
+		return nil, ErrInvalidCount
+	}
+
+	if batch.Discount < 0 || batch.Discount > 100 {
+		return nil, ErrInvalidDiscount
+	}
+
+	if batch.MinBasketValue < 0 {
+		return nil, ErrInvalidMinBasketValue
+	}
+
+	gen, err := s.batchGenerator(&batch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leave enough headroom that random draws keep finding unused codes.
+	if gen.Capacity()/2 < batch.Count {
+		return nil, ErrCodeSpaceTooSmall
+	}
+
+	job, err := s.jobs.create(batch.Count, s.now().UTC(), func(ctx context.Context, id string) {
+		s.generate(ctx, id, gen, batch)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// batchGenerator returns the generator for the codes of a batch, filling in
+// the alphabet of the batch when it is left empty.
+func (s Service) batchGenerator(batch *domain.CodeBatch) (*couponcode.Generator, error) {
+	checkDigit, err := s.batchCheckDigit(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := couponcode.NewGenerator(batch.Alphabet, batch.Length, batch.Prefix, checkDigit)
+	if err != nil {
+		switch err {
+		case couponcode.ErrInvalidAlphabet:
+			return nil, ErrInvalidAlphabet
+		case couponcode.ErrInvalidLength:
+			return nil, ErrInvalidLength
+		case couponcode.ErrInvalidPrefix:
+			return nil, ErrInvalidPrefix
+		default:
+			return nil, err
+		}
+	}
+
+	if err := s.validateBatchFormat(*batch, gen); err != nil {
+		return nil, err
+	}
+
+	return gen, nil
+}
+
+// batchCheckDigit resolves the check digit scheme of a batch. A scheme
+// configured on the service applies to every generated code, so the batch
+// alphabet has to be a subset of its alphabet.
+func (s Service) batchCheckDigit(batch *domain.CodeBatch) (couponcode.CheckDigit, error) {
+	if s.checkDigit != nil {
+		if batch.Alphabet == "" {
+			batch.Alphabet = s.checkDigit.Alphabet()
+		}
+		if !couponcode.Contains(s.checkDigit.Alphabet(), batch.Alphabet) {
+			return nil, ErrInvalidAlphabet
+		}
+		return s.checkDigit, nil
+	}
+
+	if batch.Alphabet == "" {
+		batch.Alphabet = couponcode.DefaultAlphabet
+	}
+	if !batch.CheckChar {
+		return nil, nil
+	}
+
+	luhn, err := couponcode.NewLuhn(batch.Alphabet)
+	if err != nil {
+		return nil, ErrInvalidAlphabet
+	}
+	return luhn, nil
+}
+
+// validateBatchFormat ensures generated codes come out normalized and
+// match the code policy, so they can be found again as generated.
+func (s Service) validateBatchFormat(batch domain.CodeBatch, gen *couponcode.Generator) error {
+	if !s.policy.Stable(batch.Alphabet) || s.policy.ValidateCharset(batch.Alphabet) != nil {
+		return ErrInvalidAlphabet
+	}
+
+	if !s.policy.Stable(batch.Prefix) {
+		return ErrInvalidPrefix
+	}
+
+	sample, err := gen.Generate()
+	if err != nil {
+		return err
+	}
+	if err := s.policy.Validate(sample); err != nil {
+		return ErrInvalidCodeFormat
+	}
+
+	return nil
+}
+
+// generate stores the coupons of the batch until they are all stored or ctx
+// is cancelled.
+func (s Service) generate(ctx context.Context, jobID string, gen *couponcode.Generator, batch domain.CodeBatch) {
+	seen := make(map[string]struct{}, batch.Count)
+
+	s.jobs.start(jobID)
+
+	for generated := 0; generated < batch.Count; generated++ {
+		if err := ctx.Err(); err != nil {
+			s.jobs.finish(jobID, err, s.now().UTC())
+			return
+		}
+
+		code, err := s.insertUnused(gen, seen, func(code string) error {
+			return s.repo.Insert(ctx, domain.Coupon{
+				ID:             uuid.NewString(),
+				Code:           code,
+				Discount:       batch.Discount,
+				MinBasketValue: batch.MinBasketValue,
+			})
+		})
+		if err != nil {
+			s.jobs.finish(jobID, err, s.now().UTC())
+			return
+		}
+
+		seen[code] = struct{}{}
+		s.jobs.add(jobID, code)
+	}
+
+	s.jobs.finish(jobID, nil, s.now().UTC())
+}
+
+// insertUnused draws codes until insert stores a coupon under one, and draws
+// again while insert fails with repository.ErrAlreadyExists. Taken codes are
+// only detected by the insert, so a code issued concurrently elsewhere is
+// never overwritten. Codes in seen are not tried.
+func (s Service) insertUnused(gen *couponcode.Generator, seen map[string]struct{},
+	insert func(code string) error) (string, error) {
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		code, err := gen.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := seen[code]; ok {
+			continue
+		}
+
+		err = insert(code)
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return code, nil
+	}
+
+	return "", ErrCodeSpaceExhausted
+}
+
+// rollback undoes what was recorded for a coupon whose insert failed with
+// err. If undoing fails as well, err is kept only as text, so that a taken
+// code whose record is left behind is not drawn again.
+func rollback(err error, undo func() error) error {
+	if undoErr := undo(); undoErr != nil {
+		return fmt.Errorf("%w after %v", undoErr, err)
+	}
+	return err
+}
+
+// Close cancels the code generation jobs still running and waits for them to
+// stop.
+func (s Service) Close() {
+	s.jobs.close()
+}
+
+func (s Service) GetJob(_ context.Context, id string) (*domain.Job, error) {
+	job, _, ok := s.jobs.get(id, s.now().UTC())
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return &job, nil
+}
+
+func (s Service) GetJobCodes(_ context.Context, id string) ([]string, error) {
+	job, codes, ok := s.jobs.get(id, s.now().UTC())
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	if job.Status != domain.JobCompleted {
+		return nil, ErrJobNotCompleted
+	}
+	return codes, nil
+}
@@ -0,0 +1,268 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func waitForJob(t *testing.T, srv service.Service, id string) *domain.Job {
+	t.Helper()
+
+	var job *domain.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = srv.GetJob(context.Background(), id)
+		require.NoError(t, err)
+		return job.Finished()
+	}, 5*time.Second, 10*time.Millisecond, "expected job %s to finish", id)
+
+	return job
+}
+
+func TestGenerateCoupons(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGenerateCoupons in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		batch       domain.CodeBatch
+		setupMocks  func(*mocks.Repository)
+		wantStatus  domain.JobStatus
+		wantCodes   int
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "Successful generation",
+			batch: domain.CodeBatch{Count: 20, Length: 8, Prefix: "XMAS", CheckChar: true, Discount: 10},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }),
+					mock.MatchedBy(func(coupon domain.Coupon) bool {
+						return coupon.ID != "" &&
+							couponcode.Valid(couponcode.DefaultAlphabet, coupon.Code) &&
+							coupon.Discount == 10
+					})).
+					Return(nil).
+					Times(20)
+			},
+			wantStatus: domain.JobCompleted,
+			wantCodes:  20,
+		},
+		{
+			name:  "Existing codes are skipped",
+			batch: domain.CodeBatch{Count: 1, Length: 8, Discount: 10},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(repository.ErrAlreadyExists).
+					Twice()
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(nil).
+					Once()
+			},
+			wantStatus: domain.JobCompleted,
+			wantCodes:  1,
+		},
+		{
+			name:  "Codes taken on every attempt fail the job",
+			batch: domain.CodeBatch{Count: 1, Length: 8, Discount: 10},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(repository.ErrAlreadyExists).
+					Times(10)
+			},
+			wantStatus: domain.JobFailed,
+		},
+		{
+			name:  "Repository failure fails the job",
+			batch: domain.CodeBatch{Count: 5, Length: 8, Discount: 10},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(errors.New("fatal error")).
+					Once()
+			},
+			wantStatus: domain.JobFailed,
+		},
+		{
+			name:        "Count out of range",
+			batch:       domain.CodeBatch{Count: service.MaxBatchCount + 1, Length: 8},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidCount,
+		},
+		{
+			name:        "Ambiguous alphabet",
+			batch:       domain.CodeBatch{Count: 10, Length: 8, Alphabet: "ABC0"},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidAlphabet,
+		},
+		{
+			name:        "Invalid length",
+			batch:       domain.CodeBatch{Count: 10, Length: 1},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidLength,
+		},
+		{
+			name:        "Prefix outside alphabet",
+			batch:       domain.CodeBatch{Count: 10, Length: 8, Prefix: "SPRING"},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidPrefix,
+		},
+		{
+			name:        "Code space too small",
+			batch:       domain.CodeBatch{Count: 10, Length: 4, Alphabet: "AB"},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrCodeSpaceTooSmall,
+		},
+		{
+			name:        "Invalid discount",
+			batch:       domain.CodeBatch{Count: 10, Length: 8, Discount: 200},
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidDiscount,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo)
+
+			srv := service.New(repo)
+			ctx := context.Background()
+
+			job, err := srv.GenerateCoupons(ctx, tc.batch)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+			require.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.batch.Count, job.Total)
+
+			job = waitForJob(t, srv, job.ID)
+			assert.Equal(t, tc.wantStatus, job.Status, "expected job status %s, got: %s (%s)", tc.wantStatus, job.Status, job.Error)
+
+			codes, err := srv.GetJobCodes(ctx, job.ID)
+			if tc.wantStatus != domain.JobCompleted {
+				assert.ErrorIs(t, err, service.ErrJobNotCompleted)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, codes, tc.wantCodes)
+			assert.Equal(t, tc.wantCodes, job.Done)
+		})
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetJob in long mode.")
+	}
+
+	repo := mocks.NewRepository(t)
+	srv := service.New(repo)
+	ctx := context.Background()
+
+	_, err := srv.GetJob(ctx, "unknown")
+	assert.ErrorIs(t, err, service.ErrJobNotFound)
+
+	_, err = srv.GetJobCodes(ctx, "unknown")
+	assert.ErrorIs(t, err, service.ErrJobNotFound)
+
+	release := make(chan time.Time)
+	repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		WaitUntil(release).
+		Return(nil).
+		Once()
+
+	job, err := srv.GenerateCoupons(ctx, domain.CodeBatch{Count: 1, Length: 8})
+	require.NoError(t, err)
+
+	_, err = srv.GetJobCodes(ctx, job.ID)
+	assert.ErrorIs(t, err, service.ErrJobNotCompleted)
+
+	close(release)
+	job = waitForJob(t, srv, job.ID)
+	assert.Equal(t, domain.JobCompleted, job.Status)
+}
+
+func TestJobLimits(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestJobLimits in long mode.")
+	}
+
+	now := time.Date(2024, 10, 4, 12, 0, 0, 0, time.UTC)
+	repo := mocks.NewRepository(t)
+	srv := service.New(repo, service.WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	release := make(chan time.Time)
+	repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		WaitUntil(release).
+		Return(nil).
+		Times(service.MaxActiveJobs)
+
+	jobs := make([]*domain.Job, 0, service.MaxActiveJobs)
+	for i := 0; i < service.MaxActiveJobs; i++ {
+		job, err := srv.GenerateCoupons(ctx, domain.CodeBatch{Count: 1, Length: 8})
+		require.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+
+	_, err := srv.GenerateCoupons(ctx, domain.CodeBatch{Count: 1, Length: 8})
+	assert.ErrorIs(t, err, service.ErrTooManyJobs)
+
+	close(release)
+	for _, job := range jobs {
+		assert.Equal(t, domain.JobCompleted, waitForJob(t, srv, job.ID).Status)
+	}
+
+	now = now.Add(24*time.Hour + time.Second)
+	_, err = srv.GetJob(ctx, jobs[0].ID)
+	assert.ErrorIs(t, err, service.ErrJobNotFound, "expected finished jobs to be evicted after the retention")
+}
+
+func TestCloseCancelsJobs(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCloseCancelsJobs in long mode.")
+	}
+
+	repo := mocks.NewRepository(t)
+	srv := service.New(repo)
+	ctx := context.Background()
+
+	inserting := make(chan struct{})
+	repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		Return(func(ctx context.Context, _ domain.Coupon) error {
+			close(inserting)
+			<-ctx.Done()
+			return ctx.Err()
+		}).
+		Once()
+
+	job, err := srv.GenerateCoupons(ctx, domain.CodeBatch{Count: 5, Length: 8})
+	require.NoError(t, err)
+
+	<-inserting
+	srv.Close()
+
+	job, err = srv.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobFailed, job.Status)
+	assert.Equal(t, context.Canceled.Error(), job.Error)
+
+	_, err = srv.GenerateCoupons(ctx, domain.CodeBatch{Count: 1, Length: 8})
+	assert.ErrorIs(t, err, service.ErrJobsClosed)
+}
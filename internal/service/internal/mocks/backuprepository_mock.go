@@ -0,0 +1,84 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BackupRepository is an autogenerated mock type for the BackupRepository type
+type BackupRepository struct {
+	mock.Mock
+}
+
+type BackupRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *BackupRepository) EXPECT() *BackupRepository_Expecter {
+	return &BackupRepository_Expecter{mock: &_m.Mock}
+}
+
+// Backup provides a mock function with given fields: _a0, _a1
+func (_m *BackupRepository) Backup(_a0 context.Context, _a1 io.Writer) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Backup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BackupRepository_Backup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backup'
+type BackupRepository_Backup_Call struct {
+	*mock.Call
+}
+
+// Backup is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 io.Writer
+func (_e *BackupRepository_Expecter) Backup(_a0 interface{}, _a1 interface{}) *BackupRepository_Backup_Call {
+	return &BackupRepository_Backup_Call{Call: _e.mock.On("Backup", _a0, _a1)}
+}
+
+func (_c *BackupRepository_Backup_Call) Run(run func(_a0 context.Context, _a1 io.Writer)) *BackupRepository_Backup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(io.Writer))
+	})
+	return _c
+}
+
+func (_c *BackupRepository_Backup_Call) Return(_a0 error) *BackupRepository_Backup_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BackupRepository_Backup_Call) RunAndReturn(run func(context.Context, io.Writer) error) *BackupRepository_Backup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewBackupRepository creates a new instance of BackupRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBackupRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BackupRepository {
+	mock := &BackupRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,80 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CacheRepository is an autogenerated mock type for the CacheRepository type
+type CacheRepository struct {
+	mock.Mock
+}
+
+type CacheRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *CacheRepository) EXPECT() *CacheRepository_Expecter {
+	return &CacheRepository_Expecter{mock: &_m.Mock}
+}
+
+// Stats provides a mock function with given fields:
+func (_m *CacheRepository) Stats() domain.CacheStats {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 domain.CacheStats
+	if rf, ok := ret.Get(0).(func() domain.CacheStats); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.CacheStats)
+	}
+
+	return r0
+}
+
+// CacheRepository_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type CacheRepository_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+func (_e *CacheRepository_Expecter) Stats() *CacheRepository_Stats_Call {
+	return &CacheRepository_Stats_Call{Call: _e.mock.On("Stats")}
+}
+
+func (_c *CacheRepository_Stats_Call) Run(run func()) *CacheRepository_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *CacheRepository_Stats_Call) Return(_a0 domain.CacheStats) *CacheRepository_Stats_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *CacheRepository_Stats_Call) RunAndReturn(run func() domain.CacheStats) *CacheRepository_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCacheRepository creates a new instance of CacheRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCacheRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CacheRepository {
+	mock := &CacheRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,142 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PromotionRepository is an autogenerated mock type for the PromotionRepository type
+type PromotionRepository struct {
+	mock.Mock
+}
+
+type PromotionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PromotionRepository) EXPECT() *PromotionRepository_Expecter {
+	return &PromotionRepository_Expecter{mock: &_m.Mock}
+}
+
+// FindAll provides a mock function with given fields: _a0
+func (_m *PromotionRepository) FindAll(_a0 context.Context) ([]domain.Promotion, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []domain.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Promotion, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Promotion); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PromotionRepository_FindAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAll'
+type PromotionRepository_FindAll_Call struct {
+	*mock.Call
+}
+
+// FindAll is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *PromotionRepository_Expecter) FindAll(_a0 interface{}) *PromotionRepository_FindAll_Call {
+	return &PromotionRepository_FindAll_Call{Call: _e.mock.On("FindAll", _a0)}
+}
+
+func (_c *PromotionRepository_FindAll_Call) Run(run func(_a0 context.Context)) *PromotionRepository_FindAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *PromotionRepository_FindAll_Call) Return(_a0 []domain.Promotion, _a1 error) *PromotionRepository_FindAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PromotionRepository_FindAll_Call) RunAndReturn(run func(context.Context) ([]domain.Promotion, error)) *PromotionRepository_FindAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: _a0, _a1
+func (_m *PromotionRepository) Save(_a0 context.Context, _a1 domain.Promotion) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Promotion) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PromotionRepository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type PromotionRepository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Promotion
+func (_e *PromotionRepository_Expecter) Save(_a0 interface{}, _a1 interface{}) *PromotionRepository_Save_Call {
+	return &PromotionRepository_Save_Call{Call: _e.mock.On("Save", _a0, _a1)}
+}
+
+func (_c *PromotionRepository_Save_Call) Run(run func(_a0 context.Context, _a1 domain.Promotion)) *PromotionRepository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Promotion))
+	})
+	return _c
+}
+
+func (_c *PromotionRepository_Save_Call) Return(_a0 error) *PromotionRepository_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PromotionRepository_Save_Call) RunAndReturn(run func(context.Context, domain.Promotion) error) *PromotionRepository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPromotionRepository creates a new instance of PromotionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPromotionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PromotionRepository {
+	mock := &PromotionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,213 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RedemptionRepository is an autogenerated mock type for the RedemptionRepository type
+type RedemptionRepository struct {
+	mock.Mock
+}
+
+type RedemptionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RedemptionRepository) EXPECT() *RedemptionRepository_Expecter {
+	return &RedemptionRepository_Expecter{mock: &_m.Mock}
+}
+
+// Count provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *RedemptionRepository) Count(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time) (int, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (int, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) int); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedemptionRepository_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type RedemptionRepository_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+//   - _a3 time.Time
+func (_e *RedemptionRepository_Expecter) Count(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *RedemptionRepository_Count_Call {
+	return &RedemptionRepository_Count_Call{Call: _e.mock.On("Count", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *RedemptionRepository_Count_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time)) *RedemptionRepository_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RedemptionRepository_Count_Call) Return(_a0 int, _a1 error) *RedemptionRepository_Count_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RedemptionRepository_Count_Call) RunAndReturn(run func(context.Context, string, string, time.Time) (int, error)) *RedemptionRepository_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasRedeemed provides a mock function with given fields: _a0, _a1
+func (_m *RedemptionRepository) HasRedeemed(_a0 context.Context, _a1 string) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasRedeemed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedemptionRepository_HasRedeemed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasRedeemed'
+type RedemptionRepository_HasRedeemed_Call struct {
+	*mock.Call
+}
+
+// HasRedeemed is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *RedemptionRepository_Expecter) HasRedeemed(_a0 interface{}, _a1 interface{}) *RedemptionRepository_HasRedeemed_Call {
+	return &RedemptionRepository_HasRedeemed_Call{Call: _e.mock.On("HasRedeemed", _a0, _a1)}
+}
+
+func (_c *RedemptionRepository_HasRedeemed_Call) Run(run func(_a0 context.Context, _a1 string)) *RedemptionRepository_HasRedeemed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RedemptionRepository_HasRedeemed_Call) Return(_a0 bool, _a1 error) *RedemptionRepository_HasRedeemed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RedemptionRepository_HasRedeemed_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *RedemptionRepository_HasRedeemed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function with given fields: _a0, _a1, _a2
+func (_m *RedemptionRepository) Record(_a0 context.Context, _a1 domain.Redemption, _a2 []domain.UsageWindow) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Redemption, []domain.UsageWindow) (bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Redemption, []domain.UsageWindow) bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Redemption, []domain.UsageWindow) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedemptionRepository_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type RedemptionRepository_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Redemption
+//   - _a2 []domain.UsageWindow
+func (_e *RedemptionRepository_Expecter) Record(_a0 interface{}, _a1 interface{}, _a2 interface{}) *RedemptionRepository_Record_Call {
+	return &RedemptionRepository_Record_Call{Call: _e.mock.On("Record", _a0, _a1, _a2)}
+}
+
+func (_c *RedemptionRepository_Record_Call) Run(run func(_a0 context.Context, _a1 domain.Redemption, _a2 []domain.UsageWindow)) *RedemptionRepository_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Redemption), args[2].([]domain.UsageWindow))
+	})
+	return _c
+}
+
+func (_c *RedemptionRepository_Record_Call) Return(_a0 bool, _a1 error) *RedemptionRepository_Record_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RedemptionRepository_Record_Call) RunAndReturn(run func(context.Context, domain.Redemption, []domain.UsageWindow) (bool, error)) *RedemptionRepository_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRedemptionRepository creates a new instance of RedemptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRedemptionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RedemptionRepository {
+	mock := &RedemptionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,600 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReferralRepository is an autogenerated mock type for the ReferralRepository type
+type ReferralRepository struct {
+	mock.Mock
+}
+
+type ReferralRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ReferralRepository) EXPECT() *ReferralRepository_Expecter {
+	return &ReferralRepository_Expecter{mock: &_m.Mock}
+}
+
+// Add provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) Add(_a0 context.Context, _a1 domain.Referral) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Add")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Referral) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Referral) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Referral) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_Add_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Add'
+type ReferralRepository_Add_Call struct {
+	*mock.Call
+}
+
+// Add is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Referral
+func (_e *ReferralRepository_Expecter) Add(_a0 interface{}, _a1 interface{}) *ReferralRepository_Add_Call {
+	return &ReferralRepository_Add_Call{Call: _e.mock.On("Add", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_Add_Call) Run(run func(_a0 context.Context, _a1 domain.Referral)) *ReferralRepository_Add_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Referral))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_Add_Call) Return(_a0 bool, _a1 error) *ReferralRepository_Add_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_Add_Call) RunAndReturn(run func(context.Context, domain.Referral) (bool, error)) *ReferralRepository_Add_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Complete provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *ReferralRepository) Complete(_a0 context.Context, _a1 string, _a2 string, _a3 int) (domain.Referral, bool, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 domain.Referral
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) (domain.Referral, bool, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) domain.Referral); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(domain.Referral)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) bool); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int) error); ok {
+		r2 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ReferralRepository_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type ReferralRepository_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+//   - _a3 int
+func (_e *ReferralRepository_Expecter) Complete(_a0 interface{}, _a1 interface{}, _a2 interface{}, _a3 interface{}) *ReferralRepository_Complete_Call {
+	return &ReferralRepository_Complete_Call{Call: _e.mock.On("Complete", _a0, _a1, _a2, _a3)}
+}
+
+func (_c *ReferralRepository_Complete_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string, _a3 int)) *ReferralRepository_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_Complete_Call) Return(_a0 domain.Referral, _a1 bool, _a2 error) *ReferralRepository_Complete_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *ReferralRepository_Complete_Call) RunAndReturn(run func(context.Context, string, string, int) (domain.Referral, bool, error)) *ReferralRepository_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByReferrer provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) FindByReferrer(_a0 context.Context, _a1 string) ([]domain.Referral, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByReferrer")
+	}
+
+	var r0 []domain.Referral
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Referral, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Referral); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Referral)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_FindByReferrer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferrer'
+type ReferralRepository_FindByReferrer_Call struct {
+	*mock.Call
+}
+
+// FindByReferrer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ReferralRepository_Expecter) FindByReferrer(_a0 interface{}, _a1 interface{}) *ReferralRepository_FindByReferrer_Call {
+	return &ReferralRepository_FindByReferrer_Call{Call: _e.mock.On("FindByReferrer", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_FindByReferrer_Call) Run(run func(_a0 context.Context, _a1 string)) *ReferralRepository_FindByReferrer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_FindByReferrer_Call) Return(_a0 []domain.Referral, _a1 error) *ReferralRepository_FindByReferrer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_FindByReferrer_Call) RunAndReturn(run func(context.Context, string) ([]domain.Referral, error)) *ReferralRepository_FindByReferrer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindCode provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) FindCode(_a0 context.Context, _a1 string) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindCode")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_FindCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindCode'
+type ReferralRepository_FindCode_Call struct {
+	*mock.Call
+}
+
+// FindCode is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ReferralRepository_Expecter) FindCode(_a0 interface{}, _a1 interface{}) *ReferralRepository_FindCode_Call {
+	return &ReferralRepository_FindCode_Call{Call: _e.mock.On("FindCode", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_FindCode_Call) Run(run func(_a0 context.Context, _a1 string)) *ReferralRepository_FindCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_FindCode_Call) Return(_a0 string, _a1 error) *ReferralRepository_FindCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_FindCode_Call) RunAndReturn(run func(context.Context, string) (string, error)) *ReferralRepository_FindCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindFirstOrder provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) FindFirstOrder(_a0 context.Context, _a1 string) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindFirstOrder")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_FindFirstOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindFirstOrder'
+type ReferralRepository_FindFirstOrder_Call struct {
+	*mock.Call
+}
+
+// FindFirstOrder is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ReferralRepository_Expecter) FindFirstOrder(_a0 interface{}, _a1 interface{}) *ReferralRepository_FindFirstOrder_Call {
+	return &ReferralRepository_FindFirstOrder_Call{Call: _e.mock.On("FindFirstOrder", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_FindFirstOrder_Call) Run(run func(_a0 context.Context, _a1 string)) *ReferralRepository_FindFirstOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_FindFirstOrder_Call) Return(_a0 string, _a1 error) *ReferralRepository_FindFirstOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_FindFirstOrder_Call) RunAndReturn(run func(context.Context, string) (string, error)) *ReferralRepository_FindFirstOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindReferrer provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) FindReferrer(_a0 context.Context, _a1 string) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindReferrer")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_FindReferrer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindReferrer'
+type ReferralRepository_FindReferrer_Call struct {
+	*mock.Call
+}
+
+// FindReferrer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ReferralRepository_Expecter) FindReferrer(_a0 interface{}, _a1 interface{}) *ReferralRepository_FindReferrer_Call {
+	return &ReferralRepository_FindReferrer_Call{Call: _e.mock.On("FindReferrer", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_FindReferrer_Call) Run(run func(_a0 context.Context, _a1 string)) *ReferralRepository_FindReferrer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_FindReferrer_Call) Return(_a0 string, _a1 error) *ReferralRepository_FindReferrer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_FindReferrer_Call) RunAndReturn(run func(context.Context, string) (string, error)) *ReferralRepository_FindReferrer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Remove provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) Remove(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReferralRepository_Remove_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Remove'
+type ReferralRepository_Remove_Call struct {
+	*mock.Call
+}
+
+// Remove is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ReferralRepository_Expecter) Remove(_a0 interface{}, _a1 interface{}) *ReferralRepository_Remove_Call {
+	return &ReferralRepository_Remove_Call{Call: _e.mock.On("Remove", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_Remove_Call) Run(run func(_a0 context.Context, _a1 string)) *ReferralRepository_Remove_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_Remove_Call) Return(_a0 error) *ReferralRepository_Remove_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReferralRepository_Remove_Call) RunAndReturn(run func(context.Context, string) error) *ReferralRepository_Remove_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reopen provides a mock function with given fields: _a0, _a1
+func (_m *ReferralRepository) Reopen(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reopen")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReferralRepository_Reopen_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reopen'
+type ReferralRepository_Reopen_Call struct {
+	*mock.Call
+}
+
+// Reopen is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ReferralRepository_Expecter) Reopen(_a0 interface{}, _a1 interface{}) *ReferralRepository_Reopen_Call {
+	return &ReferralRepository_Reopen_Call{Call: _e.mock.On("Reopen", _a0, _a1)}
+}
+
+func (_c *ReferralRepository_Reopen_Call) Run(run func(_a0 context.Context, _a1 string)) *ReferralRepository_Reopen_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_Reopen_Call) Return(_a0 error) *ReferralRepository_Reopen_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReferralRepository_Reopen_Call) RunAndReturn(run func(context.Context, string) error) *ReferralRepository_Reopen_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveCode provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ReferralRepository) SaveCode(_a0 context.Context, _a1 string, _a2 string) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCode")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_SaveCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveCode'
+type ReferralRepository_SaveCode_Call struct {
+	*mock.Call
+}
+
+// SaveCode is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *ReferralRepository_Expecter) SaveCode(_a0 interface{}, _a1 interface{}, _a2 interface{}) *ReferralRepository_SaveCode_Call {
+	return &ReferralRepository_SaveCode_Call{Call: _e.mock.On("SaveCode", _a0, _a1, _a2)}
+}
+
+func (_c *ReferralRepository_SaveCode_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *ReferralRepository_SaveCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_SaveCode_Call) Return(_a0 string, _a1 error) *ReferralRepository_SaveCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_SaveCode_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *ReferralRepository_SaveCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveFirstOrder provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ReferralRepository) SaveFirstOrder(_a0 context.Context, _a1 string, _a2 string) (string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveFirstOrder")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReferralRepository_SaveFirstOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveFirstOrder'
+type ReferralRepository_SaveFirstOrder_Call struct {
+	*mock.Call
+}
+
+// SaveFirstOrder is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *ReferralRepository_Expecter) SaveFirstOrder(_a0 interface{}, _a1 interface{}, _a2 interface{}) *ReferralRepository_SaveFirstOrder_Call {
+	return &ReferralRepository_SaveFirstOrder_Call{Call: _e.mock.On("SaveFirstOrder", _a0, _a1, _a2)}
+}
+
+func (_c *ReferralRepository_SaveFirstOrder_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *ReferralRepository_SaveFirstOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *ReferralRepository_SaveFirstOrder_Call) Return(_a0 string, _a1 error) *ReferralRepository_SaveFirstOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReferralRepository_SaveFirstOrder_Call) RunAndReturn(run func(context.Context, string, string) (string, error)) *ReferralRepository_SaveFirstOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewReferralRepository creates a new instance of ReferralRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReferralRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReferralRepository {
+	mock := &ReferralRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,112 @@ func (_m *Repository) EXPECT() *Repository_Expecter {
 	return &Repository_Expecter{mock: &_m.Mock}
 }
 
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Repository) Delete(_a0 context.Context, _a1 string, _a2 int) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type Repository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 int
+func (_e *Repository_Expecter) Delete(_a0 interface{}, _a1 interface{}, _a2 interface{}) *Repository_Delete_Call {
+	return &Repository_Delete_Call{Call: _e.mock.On("Delete", _a0, _a1, _a2)}
+}
+
+func (_c *Repository_Delete_Call) Run(run func(_a0 context.Context, _a1 string, _a2 int)) *Repository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_Delete_Call) Return(_a0 error) *Repository_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Delete_Call) RunAndReturn(run func(context.Context, string, int) error) *Repository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindActivatable provides a mock function with given fields: _a0
+func (_m *Repository) FindActivatable(_a0 context.Context) ([]domain.Coupon, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActivatable")
+	}
+
+	var r0 []domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Coupon, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Coupon); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindActivatable_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindActivatable'
+type Repository_FindActivatable_Call struct {
+	*mock.Call
+}
+
+// FindActivatable is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *Repository_Expecter) FindActivatable(_a0 interface{}) *Repository_FindActivatable_Call {
+	return &Repository_FindActivatable_Call{Call: _e.mock.On("FindActivatable", _a0)}
+}
+
+func (_c *Repository_FindActivatable_Call) Run(run func(_a0 context.Context)) *Repository_FindActivatable_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Repository_FindActivatable_Call) Return(_a0 []domain.Coupon, _a1 error) *Repository_FindActivatable_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindActivatable_Call) RunAndReturn(run func(context.Context) ([]domain.Coupon, error)) *Repository_FindActivatable_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // FindByCode provides a mock function with given fields: _a0, _a1
 func (_m *Repository) FindByCode(_a0 context.Context, _a1 string) (*domain.Coupon, error) {
 	ret := _m.Called(_a0, _a1)
@@ -81,6 +187,171 @@ func (_c *Repository_FindByCode_Call) RunAndReturn(run func(context.Context, str
 	return _c
 }
 
+// FindByCodes provides a mock function with given fields: _a0, _a1
+func (_m *Repository) FindByCodes(_a0 context.Context, _a1 []string) ([]domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByCodes")
+	}
+
+	var r0 []domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Coupon, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Coupon); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindByCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByCodes'
+type Repository_FindByCodes_Call struct {
+	*mock.Call
+}
+
+// FindByCodes is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 []string
+func (_e *Repository_Expecter) FindByCodes(_a0 interface{}, _a1 interface{}) *Repository_FindByCodes_Call {
+	return &Repository_FindByCodes_Call{Call: _e.mock.On("FindByCodes", _a0, _a1)}
+}
+
+func (_c *Repository_FindByCodes_Call) Run(run func(_a0 context.Context, _a1 []string)) *Repository_FindByCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *Repository_FindByCodes_Call) Return(_a0 []domain.Coupon, _a1 error) *Repository_FindByCodes_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindByCodes_Call) RunAndReturn(run func(context.Context, []string) ([]domain.Coupon, error)) *Repository_FindByCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByCustomer provides a mock function with given fields: _a0, _a1
+func (_m *Repository) FindByCustomer(_a0 context.Context, _a1 domain.Customer) ([]domain.Coupon, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByCustomer")
+	}
+
+	var r0 []domain.Coupon
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) ([]domain.Coupon, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Customer) []domain.Coupon); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Coupon)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Customer) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_FindByCustomer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByCustomer'
+type Repository_FindByCustomer_Call struct {
+	*mock.Call
+}
+
+// FindByCustomer is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Customer
+func (_e *Repository_Expecter) FindByCustomer(_a0 interface{}, _a1 interface{}) *Repository_FindByCustomer_Call {
+	return &Repository_FindByCustomer_Call{Call: _e.mock.On("FindByCustomer", _a0, _a1)}
+}
+
+func (_c *Repository_FindByCustomer_Call) Run(run func(_a0 context.Context, _a1 domain.Customer)) *Repository_FindByCustomer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Customer))
+	})
+	return _c
+}
+
+func (_c *Repository_FindByCustomer_Call) Return(_a0 []domain.Coupon, _a1 error) *Repository_FindByCustomer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_FindByCustomer_Call) RunAndReturn(run func(context.Context, domain.Customer) ([]domain.Coupon, error)) *Repository_FindByCustomer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Insert provides a mock function with given fields: _a0, _a1
+func (_m *Repository) Insert(_a0 context.Context, _a1 domain.Coupon) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Coupon) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Insert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Insert'
+type Repository_Insert_Call struct {
+	*mock.Call
+}
+
+// Insert is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Coupon
+func (_e *Repository_Expecter) Insert(_a0 interface{}, _a1 interface{}) *Repository_Insert_Call {
+	return &Repository_Insert_Call{Call: _e.mock.On("Insert", _a0, _a1)}
+}
+
+func (_c *Repository_Insert_Call) Run(run func(_a0 context.Context, _a1 domain.Coupon)) *Repository_Insert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Coupon))
+	})
+	return _c
+}
+
+func (_c *Repository_Insert_Call) Return(_a0 error) *Repository_Insert_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Insert_Call) RunAndReturn(run func(context.Context, domain.Coupon) error) *Repository_Insert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function with given fields: _a0, _a1
 func (_m *Repository) Save(_a0 context.Context, _a1 domain.Coupon) error {
 	ret := _m.Called(_a0, _a1)
@@ -128,6 +399,53 @@ func (_c *Repository_Save_Call) RunAndReturn(run func(context.Context, domain.Co
 	return _c
 }
 
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *Repository) Update(_a0 context.Context, _a1 domain.Coupon) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Coupon) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type Repository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Coupon
+func (_e *Repository_Expecter) Update(_a0 interface{}, _a1 interface{}) *Repository_Update_Call {
+	return &Repository_Update_Call{Call: _e.mock.On("Update", _a0, _a1)}
+}
+
+func (_c *Repository_Update_Call) Run(run func(_a0 context.Context, _a1 domain.Coupon)) *Repository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Coupon))
+	})
+	return _c
+}
+
+func (_c *Repository_Update_Call) Return(_a0 error) *Repository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Update_Call) RunAndReturn(run func(context.Context, domain.Coupon) error) *Repository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
@@ -0,0 +1,253 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TriggerRepository is an autogenerated mock type for the TriggerRepository type
+type TriggerRepository struct {
+	mock.Mock
+}
+
+type TriggerRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *TriggerRepository) EXPECT() *TriggerRepository_Expecter {
+	return &TriggerRepository_Expecter{mock: &_m.Mock}
+}
+
+// FindAll provides a mock function with given fields: _a0
+func (_m *TriggerRepository) FindAll(_a0 context.Context) ([]domain.Trigger, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAll")
+	}
+
+	var r0 []domain.Trigger
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Trigger, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Trigger); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Trigger)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TriggerRepository_FindAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAll'
+type TriggerRepository_FindAll_Call struct {
+	*mock.Call
+}
+
+// FindAll is a helper method to define mock.On call
+//   - _a0 context.Context
+func (_e *TriggerRepository_Expecter) FindAll(_a0 interface{}) *TriggerRepository_FindAll_Call {
+	return &TriggerRepository_FindAll_Call{Call: _e.mock.On("FindAll", _a0)}
+}
+
+func (_c *TriggerRepository_FindAll_Call) Run(run func(_a0 context.Context)) *TriggerRepository_FindAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *TriggerRepository_FindAll_Call) Return(_a0 []domain.Trigger, _a1 error) *TriggerRepository_FindAll_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TriggerRepository_FindAll_Call) RunAndReturn(run func(context.Context) ([]domain.Trigger, error)) *TriggerRepository_FindAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function with given fields: _a0, _a1
+func (_m *TriggerRepository) Release(_a0 context.Context, _a1 domain.Issuance) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Issuance) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TriggerRepository_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type TriggerRepository_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Issuance
+func (_e *TriggerRepository_Expecter) Release(_a0 interface{}, _a1 interface{}) *TriggerRepository_Release_Call {
+	return &TriggerRepository_Release_Call{Call: _e.mock.On("Release", _a0, _a1)}
+}
+
+func (_c *TriggerRepository_Release_Call) Run(run func(_a0 context.Context, _a1 domain.Issuance)) *TriggerRepository_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Issuance))
+	})
+	return _c
+}
+
+func (_c *TriggerRepository_Release_Call) Return(_a0 error) *TriggerRepository_Release_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TriggerRepository_Release_Call) RunAndReturn(run func(context.Context, domain.Issuance) error) *TriggerRepository_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reserve provides a mock function with given fields: _a0, _a1
+func (_m *TriggerRepository) Reserve(_a0 context.Context, _a1 domain.Issuance) (domain.Issuance, bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 domain.Issuance
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Issuance) (domain.Issuance, bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Issuance) domain.Issuance); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.Issuance)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Issuance) bool); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, domain.Issuance) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// TriggerRepository_Reserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reserve'
+type TriggerRepository_Reserve_Call struct {
+	*mock.Call
+}
+
+// Reserve is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Issuance
+func (_e *TriggerRepository_Expecter) Reserve(_a0 interface{}, _a1 interface{}) *TriggerRepository_Reserve_Call {
+	return &TriggerRepository_Reserve_Call{Call: _e.mock.On("Reserve", _a0, _a1)}
+}
+
+func (_c *TriggerRepository_Reserve_Call) Run(run func(_a0 context.Context, _a1 domain.Issuance)) *TriggerRepository_Reserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Issuance))
+	})
+	return _c
+}
+
+func (_c *TriggerRepository_Reserve_Call) Return(_a0 domain.Issuance, _a1 bool, _a2 error) *TriggerRepository_Reserve_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *TriggerRepository_Reserve_Call) RunAndReturn(run func(context.Context, domain.Issuance) (domain.Issuance, bool, error)) *TriggerRepository_Reserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: _a0, _a1
+func (_m *TriggerRepository) Save(_a0 context.Context, _a1 domain.Trigger) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Trigger) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TriggerRepository_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type TriggerRepository_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 domain.Trigger
+func (_e *TriggerRepository_Expecter) Save(_a0 interface{}, _a1 interface{}) *TriggerRepository_Save_Call {
+	return &TriggerRepository_Save_Call{Call: _e.mock.On("Save", _a0, _a1)}
+}
+
+func (_c *TriggerRepository_Save_Call) Run(run func(_a0 context.Context, _a1 domain.Trigger)) *TriggerRepository_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.Trigger))
+	})
+	return _c
+}
+
+func (_c *TriggerRepository_Save_Call) Return(_a0 error) *TriggerRepository_Save_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *TriggerRepository_Save_Call) RunAndReturn(run func(context.Context, domain.Trigger) error) *TriggerRepository_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewTriggerRepository creates a new instance of TriggerRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTriggerRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TriggerRepository {
+	mock := &TriggerRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
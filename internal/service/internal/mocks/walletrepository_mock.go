@@ -0,0 +1,249 @@
+// Code generated by mockery v2.40.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WalletRepository is an autogenerated mock type for the WalletRepository type
+type WalletRepository struct {
+	mock.Mock
+}
+
+type WalletRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *WalletRepository) EXPECT() *WalletRepository_Expecter {
+	return &WalletRepository_Expecter{mock: &_m.Mock}
+}
+
+// Activate provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WalletRepository) Activate(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Activate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WalletRepository_Activate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Activate'
+type WalletRepository_Activate_Call struct {
+	*mock.Call
+}
+
+// Activate is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *WalletRepository_Expecter) Activate(_a0 interface{}, _a1 interface{}, _a2 interface{}) *WalletRepository_Activate_Call {
+	return &WalletRepository_Activate_Call{Call: _e.mock.On("Activate", _a0, _a1, _a2)}
+}
+
+func (_c *WalletRepository_Activate_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *WalletRepository_Activate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *WalletRepository_Activate_Call) Return(_a0 error) *WalletRepository_Activate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WalletRepository_Activate_Call) RunAndReturn(run func(context.Context, string, string) error) *WalletRepository_Activate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Deactivate provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WalletRepository) Deactivate(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deactivate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WalletRepository_Deactivate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Deactivate'
+type WalletRepository_Deactivate_Call struct {
+	*mock.Call
+}
+
+// Deactivate is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *WalletRepository_Expecter) Deactivate(_a0 interface{}, _a1 interface{}, _a2 interface{}) *WalletRepository_Deactivate_Call {
+	return &WalletRepository_Deactivate_Call{Call: _e.mock.On("Deactivate", _a0, _a1, _a2)}
+}
+
+func (_c *WalletRepository_Deactivate_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *WalletRepository_Deactivate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *WalletRepository_Deactivate_Call) Return(_a0 error) *WalletRepository_Deactivate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *WalletRepository_Deactivate_Call) RunAndReturn(run func(context.Context, string, string) error) *WalletRepository_Deactivate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindActive provides a mock function with given fields: _a0, _a1
+func (_m *WalletRepository) FindActive(_a0 context.Context, _a1 string) ([]string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActive")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletRepository_FindActive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindActive'
+type WalletRepository_FindActive_Call struct {
+	*mock.Call
+}
+
+// FindActive is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *WalletRepository_Expecter) FindActive(_a0 interface{}, _a1 interface{}) *WalletRepository_FindActive_Call {
+	return &WalletRepository_FindActive_Call{Call: _e.mock.On("FindActive", _a0, _a1)}
+}
+
+func (_c *WalletRepository_FindActive_Call) Run(run func(_a0 context.Context, _a1 string)) *WalletRepository_FindActive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *WalletRepository_FindActive_Call) Return(_a0 []string, _a1 error) *WalletRepository_FindActive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WalletRepository_FindActive_Call) RunAndReturn(run func(context.Context, string) ([]string, error)) *WalletRepository_FindActive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsActive provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WalletRepository) IsActive(_a0 context.Context, _a1 string, _a2 string) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsActive")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletRepository_IsActive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsActive'
+type WalletRepository_IsActive_Call struct {
+	*mock.Call
+}
+
+// IsActive is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+//   - _a2 string
+func (_e *WalletRepository_Expecter) IsActive(_a0 interface{}, _a1 interface{}, _a2 interface{}) *WalletRepository_IsActive_Call {
+	return &WalletRepository_IsActive_Call{Call: _e.mock.On("IsActive", _a0, _a1, _a2)}
+}
+
+func (_c *WalletRepository_IsActive_Call) Run(run func(_a0 context.Context, _a1 string, _a2 string)) *WalletRepository_IsActive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *WalletRepository_IsActive_Call) Return(_a0 bool, _a1 error) *WalletRepository_IsActive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *WalletRepository_IsActive_Call) RunAndReturn(run func(context.Context, string, string) (bool, error)) *WalletRepository_IsActive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewWalletRepository creates a new instance of WalletRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWalletRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WalletRepository {
+	mock := &WalletRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
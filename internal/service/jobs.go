@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+const (
+	// jobRetention is how long the codes of a finished job can be
+	// downloaded. Jobs are kept in memory and do not survive a restart.
+	jobRetention = 24 * time.Hour
+	// MaxActiveJobs bounds the jobs generating codes at once, each of which
+	// holds up to MaxBatchCount codes in memory.
+	MaxActiveJobs = 4
+)
+
+type job struct {
+	domain.Job
+	codes []string
+}
+
+type jobStore struct {
+	entries map[string]*job
+	mu      *sync.Mutex
+
+	// ctx is cancelled on close to stop the running jobs, which wg waits
+	// for.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+func newJobStore() *jobStore {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jobStore{
+		entries: make(map[string]*job),
+		mu:      &sync.Mutex{},
+		ctx:     ctx,
+		cancel:  cancel,
+		wg:      &sync.WaitGroup{},
+	}
+}
+
+// create adds a job and runs work for it in the background, with a context
+// cancelled when the store is closed.
+func (s *jobStore) create(total int, now time.Time, work func(ctx context.Context, id string)) (domain.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx.Err() != nil {
+		return domain.Job{}, ErrJobsClosed
+	}
+
+	s.evict(now)
+
+	active := 0
+	for _, j := range s.entries {
+		if !j.Finished() {
+			active++
+		}
+	}
+	if active >= MaxActiveJobs {
+		return domain.Job{}, ErrTooManyJobs
+	}
+
+	j := &job{
+		Job: domain.Job{
+			ID:        uuid.NewString(),
+			Status:    domain.JobPending,
+			Total:     total,
+			CreatedAt: now,
+		},
+		codes: make([]string, 0, total),
+	}
+	s.entries[j.ID] = j
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		work(s.ctx, j.ID)
+	}()
+
+	return j.Job, nil
+}
+
+func (s *jobStore) get(id string, now time.Time) (domain.Job, []string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evict(now)
+
+	j, ok := s.entries[id]
+	if !ok {
+		return domain.Job{}, nil, false
+	}
+	return j.Job, j.codes, true
+}
+
+// evict drops the jobs finished longer than the retention ago.
+func (s *jobStore) evict(now time.Time) {
+	for id, j := range s.entries {
+		if j.Finished() && now.Sub(j.FinishedAt) > jobRetention {
+			delete(s.entries, id)
+		}
+	}
+}
+
+func (s *jobStore) start(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[id].Status = domain.JobRunning
+}
+
+func (s *jobStore) add(id string, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.entries[id]
+	j.codes = append(j.codes, code)
+	j.Done = len(j.codes)
+}
+
+func (s *jobStore) finish(id string, err error, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := s.entries[id]
+	j.FinishedAt = now
+	if err != nil {
+		j.Status = domain.JobFailed
+		j.Error = err.Error()
+		return
+	}
+	j.Status = domain.JobCompleted
+}
+
+// close cancels the running jobs and waits for them to stop. No jobs can be
+// created afterwards.
+func (s *jobStore) close() {
+	s.mu.Lock()
+	s.cancel()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
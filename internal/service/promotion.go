@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+var (
+	ErrPromotionsDisabled = errors.New("promotions not configured")
+	ErrInvalidPromotion   = errors.New("invalid promotion")
+	ErrAlreadyApplied     = errors.New("code already applied")
+	ErrDiscountExceeded   = errors.New("discount exceeds remaining basket value")
+)
+
+// rejectableErrors make an entered code unusable without failing the whole
+// pricing request.
+var rejectableErrors = []error{
+	ErrInvalidCode,
+	ErrNotFound,
+	ErrMalformedCode,
+	ErrInvalidToken,
+	ErrTokenExpired,
+	ErrTokensDisabled,
+}
+
+// WithPromotions enables auto promotions, which PriceBasket applies to every
+// qualifying basket.
+func WithPromotions(promotions PromotionRepository) Option {
+	return func(s *Service) {
+		s.promotions = promotions
+	}
+}
+
+func (s Service) CreatePromotion(ctx context.Context, promotion domain.Promotion) (*domain.Promotion, error) {
+	if s.promotions == nil {
+		return nil, ErrPromotionsDisabled
+	}
+
+	// Usage limits count the redemptions of a code, which promotions do not
+	// have.
+	promotion.Name = strings.TrimSpace(promotion.Name)
+	if promotion.Name == "" || promotion.Code != "" || promotion.RequiresActivation || len(promotion.UsageLimits) > 0 {
+		return nil, ErrInvalidPromotion
+	}
+
+	if err := s.validateCoupon(promotion.Coupon); err != nil {
+		return nil, err
+	}
+
+	promotion.ID = uuid.NewString()
+
+	if err := s.promotions.Save(ctx, promotion); err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (s Service) GetPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	if s.promotions == nil {
+		return nil, ErrPromotionsDisabled
+	}
+
+	return s.promotions.FindAll(ctx)
+}
+
+// PriceBasket applies every qualifying auto promotion and then every entered
+// code to the basket. Each rule is evaluated against the original basket, and
+// discounts stack as long as they fit into the remaining value. Codes that
+// cannot be applied are reported instead of failing the request.
+func (s Service) PriceBasket(ctx context.Context, basket domain.Basket, codes []string) (*domain.Pricing, error) {
+	if basket.Value <= 0 {
+		return nil, ErrInvalidBasketValue
+	}
+
+	if !validTimeZone(basket.TimeZone) {
+		return nil, ErrInvalidTimeZone
+	}
+
+	if !validLines(basket.Lines) {
+		return nil, ErrInvalidBasketLine
+	}
+
+	pricing := &domain.Pricing{
+		Value:     basket.Value,
+		Discounts: []domain.AppliedDiscount{},
+		Rejected:  []domain.RejectedCode{},
+	}
+
+	if s.promotions != nil {
+		promotions, err := s.promotions.FindAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, promotion := range promotions {
+			if !promotion.Active {
+				continue
+			}
+
+			discount, err := s.evaluate(ctx, &promotion.Coupon, basket)
+			if err != nil {
+				if isRuleError(err) {
+					continue
+				}
+				return nil, err
+			}
+
+			if discount > pricing.Value {
+				continue
+			}
+
+			pricing.Add(domain.AppliedDiscount{
+				PromotionID: promotion.ID,
+				Name:        promotion.Name,
+				Discount:    discount,
+			})
+		}
+	}
+
+	applied := make(map[string]struct{}, len(codes))
+	for _, entered := range codes {
+		code := s.normalize(entered)
+
+		reject := func(err error) {
+			pricing.Rejected = append(pricing.Rejected, domain.RejectedCode{Code: entered, Reason: err.Error()})
+		}
+
+		if code == "" {
+			reject(ErrInvalidCode)
+			continue
+		}
+
+		if _, ok := applied[code]; ok {
+			reject(ErrAlreadyApplied)
+			continue
+		}
+
+		coupon, err := s.findCoupon(ctx, code)
+		if err != nil {
+			if isRejectable(err) {
+				reject(err)
+				continue
+			}
+			return nil, err
+		}
+
+		discount, err := s.evaluate(ctx, coupon, basket)
+		if err != nil {
+			if isRuleError(err) {
+				reject(err)
+				continue
+			}
+			return nil, err
+		}
+
+		if discount > pricing.Value {
+			reject(ErrDiscountExceeded)
+			continue
+		}
+
+		applied[code] = struct{}{}
+		pricing.Add(domain.AppliedDiscount{Code: code, Discount: discount})
+	}
+
+	return pricing, nil
+}
+
+func isRejectable(err error) bool {
+	for _, rejectableErr := range rejectableErrors {
+		if errors.Is(err, rejectableErr) {
+			return true
+		}
+	}
+	return false
+}
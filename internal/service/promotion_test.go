@@ -0,0 +1,287 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreatePromotion in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		promotion   domain.Promotion
+		setupMocks  func(*mocks.PromotionRepository)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:      "Successful creation",
+			promotion: domain.Promotion{Name: "summer", Active: true, Coupon: domain.Coupon{Discount: 10, MinBasketValue: 50}},
+			setupMocks: func(promotions *mocks.PromotionRepository) {
+				promotions.On("Save", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(p domain.Promotion) bool {
+					return p.ID != "" && p.Name == "summer" && p.Discount == 10
+				})).
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:        "Missing name",
+			promotion:   domain.Promotion{Name: " ", Coupon: domain.Coupon{Discount: 10}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidPromotion,
+		},
+		{
+			name:        "Promotion with code",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Code: "summer", Discount: 10}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidPromotion,
+		},
+		{
+			name:        "Invalid discount",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 101}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidDiscount,
+		},
+		{
+			name:        "Invalid min basket value",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, MinBasketValue: -1}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidMinBasketValue,
+		},
+		{
+			name:        "Invalid assignment",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, Assignment: domain.Assignment{Segments: []string{""}}}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidAssignment,
+		},
+		{
+			name:        "Invalid schedule",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, Schedule: domain.Schedule{Weekdays: []time.Weekday{7}}}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidSchedule,
+		},
+		{
+			name:        "Invalid eligibility",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, Eligibility: domain.Eligibility{Channels: domain.Restriction{Allow: []string{" "}}}}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidEligibility,
+		},
+		{
+			name:        "Invalid condition",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, Conditions: []domain.Condition{{Type: domain.ConditionMinValue}}}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidCondition,
+		},
+		{
+			name:        "Expressions not configured",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, Expression: `customer.tier == "gold"`}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrExpressionsDisabled,
+		},
+		{
+			name:        "Promotion with usage limits",
+			promotion:   domain.Promotion{Name: "summer", Coupon: domain.Coupon{Discount: 10, UsageLimits: []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay}}}},
+			setupMocks:  func(promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidPromotion,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			promotions := mocks.NewPromotionRepository(t)
+			tc.setupMocks(promotions)
+
+			srv := service.New(repo, service.WithPromotions(promotions))
+
+			got, err := srv.CreatePromotion(context.Background(), tc.promotion)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.NotEmpty(t, got.ID)
+		})
+	}
+
+	t.Run("Promotions disabled", func(t *testing.T) {
+		srv := service.New(mocks.NewRepository(t))
+
+		_, err := srv.CreatePromotion(context.Background(), domain.Promotion{Name: "summer"})
+		assert.ErrorIs(t, err, service.ErrPromotionsDisabled)
+	})
+}
+
+func TestPriceBasket(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPriceBasket in long mode.")
+	}
+
+	summer := domain.Promotion{Name: "summer", Active: true,
+		Coupon: domain.Coupon{ID: "p1", Discount: 10, MinBasketValue: 50}}
+	gold := domain.Promotion{Name: "gold", Active: true,
+		Coupon: domain.Coupon{ID: "p2", Discount: 20, Assignment: domain.Assignment{Segments: []string{"gold"}}}}
+	inactive := domain.Promotion{Name: "inactive", Active: false,
+		Coupon: domain.Coupon{ID: "p3", Discount: 30}}
+	online := domain.Promotion{Name: "online", Active: true,
+		Coupon: domain.Coupon{ID: "p4", Discount: 5, Eligibility: domain.Eligibility{Channels: domain.Restriction{Allow: []string{"online"}}}}}
+	welcome := domain.Coupon{ID: "id1", Code: "welcome", Discount: 15}
+	big := domain.Coupon{ID: "id2", Code: "big", Discount: 30, MinBasketValue: 200}
+
+	type testCase struct {
+		name        string
+		basket      domain.Basket
+		codes       []string
+		setupMocks  func(*mocks.Repository, *mocks.PromotionRepository)
+		want        *domain.Pricing
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Promotions and codes stack",
+			basket: domain.Basket{Value: 100, Customer: domain.Customer{ID: "c1", Segments: []string{"gold"}}},
+			codes:  []string{"welcome"},
+			setupMocks: func(repo *mocks.Repository, promotions *mocks.PromotionRepository) {
+				promotions.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Promotion{gold, inactive, summer}, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "welcome").
+					Return(&welcome, nil).
+					Once()
+			},
+			want: &domain.Pricing{
+				Value:           55,
+				AppliedDiscount: 45,
+				Discounts: []domain.AppliedDiscount{
+					{PromotionID: "p2", Name: "gold", Discount: 20},
+					{PromotionID: "p1", Name: "summer", Discount: 10},
+					{Code: "welcome", Discount: 15},
+				},
+				Rejected: []domain.RejectedCode{},
+			},
+		},
+		{
+			name:   "Non qualifying promotions and codes",
+			basket: domain.Basket{Value: 40},
+			codes:  []string{"big", "unknown", "big", ""},
+			setupMocks: func(repo *mocks.Repository, promotions *mocks.PromotionRepository) {
+				promotions.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Promotion{gold, summer}, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "big").
+					Return(&big, nil).
+					Twice()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "unknown").
+					Return(nil, repository.ErrNotFound).
+					Once()
+			},
+			want: &domain.Pricing{
+				Value:     40,
+				Discounts: []domain.AppliedDiscount{},
+				Rejected: []domain.RejectedCode{
+					{Code: "big", Reason: service.ErrMinBasketValue.Error()},
+					{Code: "unknown", Reason: service.ErrNotFound.Error()},
+					{Code: "big", Reason: service.ErrMinBasketValue.Error()},
+					{Code: "", Reason: service.ErrInvalidCode.Error()},
+				},
+			},
+		},
+		{
+			name:   "Promotion limited to another channel",
+			basket: domain.Basket{Value: 100, Channel: "store"},
+			setupMocks: func(repo *mocks.Repository, promotions *mocks.PromotionRepository) {
+				promotions.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Promotion{online, summer}, nil).
+					Once()
+			},
+			want: &domain.Pricing{
+				Value:           90,
+				AppliedDiscount: 10,
+				Discounts:       []domain.AppliedDiscount{{PromotionID: "p1", Name: "summer", Discount: 10}},
+				Rejected:        []domain.RejectedCode{},
+			},
+		},
+		{
+			name:   "Duplicate and exceeding codes",
+			basket: domain.Basket{Value: 20},
+			codes:  []string{"welcome", "welcome", "other"},
+			setupMocks: func(repo *mocks.Repository, promotions *mocks.PromotionRepository) {
+				promotions.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Promotion{}, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "welcome").
+					Return(&welcome, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "other").
+					Return(&domain.Coupon{Code: "other", Discount: 10}, nil).
+					Once()
+			},
+			want: &domain.Pricing{
+				Value:           5,
+				AppliedDiscount: 15,
+				Discounts:       []domain.AppliedDiscount{{Code: "welcome", Discount: 15}},
+				Rejected: []domain.RejectedCode{
+					{Code: "welcome", Reason: service.ErrAlreadyApplied.Error()},
+					{Code: "other", Reason: service.ErrDiscountExceeded.Error()},
+				},
+			},
+		},
+		{
+			name:        "Invalid basket value",
+			basket:      domain.Basket{Value: 0},
+			setupMocks:  func(repo *mocks.Repository, promotions *mocks.PromotionRepository) {},
+			expectedErr: service.ErrInvalidBasketValue,
+		},
+		{
+			name:   "Repository error",
+			basket: domain.Basket{Value: 100},
+			codes:  []string{"welcome"},
+			setupMocks: func(repo *mocks.Repository, promotions *mocks.PromotionRepository) {
+				promotions.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Promotion{}, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "welcome").
+					Return(nil, errors.New("fatal error")).
+					Once()
+			},
+			expectedErr: errors.New("fatal error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			promotions := mocks.NewPromotionRepository(t)
+			tc.setupMocks(repo, promotions)
+
+			srv := service.New(repo, service.WithPromotions(promotions))
+
+			got, err := srv.PriceBasket(context.Background(), tc.basket, tc.codes)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
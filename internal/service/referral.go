@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+var (
+	ErrReferralsDisabled = errors.New("referrals not configured")
+	ErrReferralNotFound  = errors.New("referral code not found")
+	ErrSelfReferral      = errors.New("customer cannot redeem their own referral code")
+	ErrAlreadyReferred   = errors.New("customer already referred")
+	ErrExistingCustomer  = errors.New("only new customers can be referred")
+)
+
+// WithReferrals enables the referral program, whose codes CheckReferrals
+// validates.
+func WithReferrals(referrals ReferralRepository, program domain.ReferralProgram) Option {
+	return func(s *Service) {
+		s.referrals = referrals
+		s.referralProgram = program
+	}
+}
+
+// CheckReferrals reports whether referral codes and the coupons of the
+// referral program can be generated under the code policy and alphabet, so
+// that a program that cannot fails on start rather than on first use.
+func (s Service) CheckReferrals() error {
+	if s.referrals == nil {
+		return nil
+	}
+
+	templates := []domain.CouponTemplate{
+		{Prefix: s.referralProgram.CodePrefix},
+		s.referralProgram.Welcome,
+		s.referralProgram.Reward,
+	}
+	for _, template := range templates {
+		if _, err := s.templateGenerator(template); err != nil {
+			return fmt.Errorf("referral program: prefix %q: %w", template.Prefix, err)
+		}
+	}
+	return nil
+}
+
+// GetReferralCode returns the referral code of the customer, creating one
+// on first use.
+func (s Service) GetReferralCode(ctx context.Context, customer domain.Customer) (string, error) {
+	if customer.ID == "" {
+		return "", ErrInvalidCustomer
+	}
+
+	if s.referrals == nil {
+		return "", ErrReferralsDisabled
+	}
+
+	code, err := s.referrals.FindCode(ctx, customer.ID)
+	if err == nil {
+		return code, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return "", err
+	}
+
+	gen, err := s.templateGenerator(domain.CouponTemplate{Prefix: s.referralProgram.CodePrefix})
+	if err != nil {
+		return "", err
+	}
+
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		code, err := gen.Generate()
+		if err != nil {
+			return "", err
+		}
+
+		_, err = s.referrals.FindReferrer(ctx, code)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return "", err
+		}
+
+		return s.referrals.SaveCode(ctx, customer.ID, code)
+	}
+
+	return "", ErrCodeSpaceExhausted
+}
+
+// RedeemReferral refers the customer by the owner of the code and issues
+// them a personal welcome coupon. A customer can be referred only once, and
+// only before completing an order or redeeming a coupon.
+func (s Service) RedeemReferral(ctx context.Context, customer domain.Customer, code string) (*domain.Coupon, error) {
+	if customer.ID == "" {
+		return nil, ErrInvalidCustomer
+	}
+
+	if s.referrals == nil {
+		return nil, ErrReferralsDisabled
+	}
+
+	code = s.policy.Normalize(code)
+
+	referrerID, err := s.referrals.FindReferrer(ctx, code)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReferralNotFound
+		}
+		return nil, err
+	}
+
+	if referrerID == customer.ID {
+		return nil, ErrSelfReferral
+	}
+
+	existing, err := s.existingCustomer(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing {
+		return nil, ErrExistingCustomer
+	}
+
+	gen, err := s.templateGenerator(s.referralProgram.Welcome)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := s.referrals.Add(ctx, domain.Referral{
+		Code:       code,
+		ReferrerID: referrerID,
+		RefereeID:  customer.ID,
+		Status:     domain.ReferralPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !added {
+		return nil, ErrAlreadyReferred
+	}
+
+	var coupon *domain.Coupon
+	_, err = s.insertUnused(gen, nil, func(code string) error {
+		coupon, err = s.insertIssued(ctx, s.referralCoupon(s.referralProgram.Welcome, code, customer.ID))
+		return err
+	})
+	if err != nil {
+		if removeErr := s.referrals.Remove(ctx, customer.ID); removeErr != nil {
+			return nil, errors.Join(err, removeErr)
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s Service) GetReferralStats(ctx context.Context, customer domain.Customer) (*domain.ReferralStats, error) {
+	if customer.ID == "" {
+		return nil, ErrInvalidCustomer
+	}
+
+	if s.referrals == nil {
+		return nil, ErrReferralsDisabled
+	}
+
+	code, err := s.referrals.FindCode(ctx, customer.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrReferralNotFound
+		}
+		return nil, err
+	}
+
+	referrals, err := s.referrals.FindByReferrer(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.ReferralStats{
+		ReferrerID: customer.ID,
+		Code:       code,
+		Referrals:  len(referrals),
+		MaxRewards: s.referralProgram.MaxRewards,
+	}
+	for _, referral := range referrals {
+		switch referral.Status {
+		case domain.ReferralPending:
+			stats.Pending++
+		case domain.ReferralRewarded:
+			stats.Rewarded++
+		case domain.ReferralCapped:
+			stats.Capped++
+		}
+	}
+
+	return stats, nil
+}
+
+// existingCustomer reports whether the customer completed an order or
+// redeemed a coupon. Orders are known from the order events handled since
+// the referral program is enabled.
+func (s Service) existingCustomer(ctx context.Context, customerID string) (bool, error) {
+	_, err := s.referrals.FindFirstOrder(ctx, customerID)
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return false, err
+	}
+
+	if s.redemptions == nil {
+		return false, nil
+	}
+	return s.redemptions.HasRedeemed(ctx, customerID)
+}
+
+// rewardReferrer records the first order of the customer and settles their
+// referral with it. Only the first order counts: later ones find the referral
+// settled, and a referral made after the first order is never settled.
+// Replaying the first order retries a reward that failed to be issued.
+func (s Service) rewardReferrer(ctx context.Context, event domain.OrderEvent) (*domain.Coupon, error) {
+	firstOrder, err := s.referrals.SaveFirstOrder(ctx, event.Customer.ID, event.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if firstOrder != event.OrderID {
+		return nil, nil
+	}
+
+	gen, err := s.templateGenerator(s.referralProgram.Reward)
+	if err != nil {
+		return nil, err
+	}
+
+	// The reward is recorded under the drawn code before the coupon is
+	// saved, and reopened again if the code turns out to be taken.
+	var coupon *domain.Coupon
+	_, err = s.insertUnused(gen, nil, func(code string) error {
+		referral, rewarded, err := s.referrals.Complete(ctx, event.Customer.ID, code, s.referralProgram.MaxRewards)
+		if errors.Is(err, repository.ErrNotFound) || err == nil && !rewarded {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		coupon, err = s.insertIssued(ctx, s.referralCoupon(s.referralProgram.Reward, code, referral.ReferrerID))
+		if err != nil {
+			return rollback(err, func() error { return s.referrals.Reopen(ctx, event.Customer.ID) })
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s Service) referralCoupon(template domain.CouponTemplate, code, customerID string) domain.Coupon {
+	return domain.Coupon{
+		ID:                 uuid.NewString(),
+		Code:               code,
+		Discount:           template.Discount,
+		MinBasketValue:     template.MinBasketValue,
+		Assignment:         domain.Assignment{CustomerIDs: []string{customerID}},
+		RequiresActivation: template.RequiresActivation,
+	}
+}
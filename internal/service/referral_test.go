@@ -0,0 +1,286 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+var referralProgram = domain.ReferralProgram{
+	CodePrefix: "REF",
+	Welcome:    domain.CouponTemplate{Prefix: "HEY", Discount: 10},
+	Reward:     domain.CouponTemplate{Prefix: "THX", Discount: 15},
+	MaxRewards: 1,
+}
+
+func TestReferralProgram(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReferralProgram in long mode.")
+	}
+
+	ctx := context.Background()
+	srv := service.New(memory.New(),
+		service.WithReferrals(memory.NewReferrals(), referralProgram),
+		service.WithRedemptions(memory.NewRedemptions()))
+
+	referrer := domain.Customer{ID: "alice"}
+	code, err := srv.GetReferralCode(ctx, referrer)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(code, "REF"), "expected referral code prefix, got %q", code)
+
+	again, err := srv.GetReferralCode(ctx, referrer)
+	require.NoError(t, err)
+	assert.Equal(t, code, again)
+
+	t.Run("Self referral", func(t *testing.T) {
+		_, err := srv.RedeemReferral(ctx, referrer, code)
+		assert.ErrorIs(t, err, service.ErrSelfReferral)
+	})
+
+	t.Run("Unknown code", func(t *testing.T) {
+		_, err := srv.RedeemReferral(ctx, domain.Customer{ID: "bob"}, "REFUNKNOWN")
+		assert.ErrorIs(t, err, service.ErrReferralNotFound)
+	})
+
+	t.Run("Welcome coupon for referee", func(t *testing.T) {
+		welcome, err := srv.RedeemReferral(ctx, domain.Customer{ID: "bob"}, code)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(welcome.Code, "HEY"))
+		assert.Equal(t, 10, welcome.Discount)
+
+		assigned, err := srv.GetCustomerCoupons(ctx, domain.Customer{ID: "bob"})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Coupon{*welcome}, assigned)
+	})
+
+	t.Run("Referee referred only once", func(t *testing.T) {
+		_, err := srv.RedeemReferral(ctx, domain.Customer{ID: "bob"}, code)
+		assert.ErrorIs(t, err, service.ErrAlreadyReferred)
+	})
+
+	t.Run("Referrer rewarded for first order only", func(t *testing.T) {
+		event := domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o1", Customer: domain.Customer{ID: "bob"}, Value: 30}
+
+		issued, err := srv.HandleEvent(ctx, event)
+		require.NoError(t, err)
+		require.Len(t, issued, 1)
+		assert.True(t, issued[0].Assignment.AssignedTo(referrer))
+		assert.Equal(t, 15, issued[0].Discount)
+
+		event.OrderID = "o2"
+		issued, err = srv.HandleEvent(ctx, event)
+		require.NoError(t, err)
+		assert.Empty(t, issued)
+	})
+
+	t.Run("Rewards capped per referrer", func(t *testing.T) {
+		_, err := srv.RedeemReferral(ctx, domain.Customer{ID: "carol"}, code)
+		require.NoError(t, err)
+
+		issued, err := srv.HandleEvent(ctx, domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o3",
+			Customer: domain.Customer{ID: "carol"}, Value: 30})
+		require.NoError(t, err)
+		assert.Empty(t, issued)
+	})
+
+	t.Run("Referral stats", func(t *testing.T) {
+		stats, err := srv.GetReferralStats(ctx, referrer)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ReferralStats{
+			ReferrerID: "alice",
+			Code:       code,
+			Referrals:  2,
+			Rewarded:   1,
+			Capped:     1,
+			MaxRewards: 1,
+		}, stats)
+	})
+
+	t.Run("Customer with an order not referred", func(t *testing.T) {
+		_, err := srv.HandleEvent(ctx, domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o4",
+			Customer: domain.Customer{ID: "dave"}, Value: 30})
+		require.NoError(t, err)
+
+		_, err = srv.RedeemReferral(ctx, domain.Customer{ID: "dave"}, code)
+		assert.ErrorIs(t, err, service.ErrExistingCustomer)
+	})
+
+	t.Run("Customer with a redemption not referred", func(t *testing.T) {
+		require.NoError(t, srv.CreateCoupon(ctx, domain.Coupon{Code: "SPRING", Discount: 10}))
+		_, err := srv.RedeemCoupon(ctx, domain.Basket{Value: 100, Customer: domain.Customer{ID: "erin"}}, "SPRING", "o5")
+		require.NoError(t, err)
+
+		_, err = srv.RedeemReferral(ctx, domain.Customer{ID: "erin"}, code)
+		assert.ErrorIs(t, err, service.ErrExistingCustomer)
+	})
+}
+
+func TestReferralRewardedForFirstOrder(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReferralRewardedForFirstOrder in long mode.")
+	}
+
+	ctx := context.Background()
+	referrals := memory.NewReferrals()
+	srv := service.New(memory.New(), service.WithReferrals(referrals, referralProgram))
+
+	// The first order completes while the referral is being redeemed.
+	_, err := referrals.SaveFirstOrder(ctx, "bob", "o1")
+	require.NoError(t, err)
+	_, err = referrals.Add(ctx, domain.Referral{Code: "REFCODE", ReferrerID: "alice", RefereeID: "bob", Status: domain.ReferralPending})
+	require.NoError(t, err)
+
+	issued, err := srv.HandleEvent(ctx, domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o2",
+		Customer: domain.Customer{ID: "bob"}, Value: 30})
+	require.NoError(t, err)
+	assert.Empty(t, issued, "expected no reward for an order after the first")
+}
+
+func TestRedeemReferralSaveFailure(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedeemReferralSaveFailure in long mode.")
+	}
+
+	repo := mocks.NewRepository(t)
+	referrals := mocks.NewReferralRepository(t)
+
+	referrals.On("FindReferrer", mock.MatchedBy(func(ctx context.Context) bool { return true }), "REFCODE").
+		Return("alice", nil).
+		Once()
+	referrals.On("FindFirstOrder", mock.MatchedBy(func(ctx context.Context) bool { return true }), "bob").
+		Return("", repository.ErrNotFound).
+		Once()
+	referrals.On("Add", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(referral domain.Referral) bool {
+		return referral.ReferrerID == "alice" && referral.RefereeID == "bob" && referral.Status == domain.ReferralPending
+	})).
+		Return(true, nil).
+		Once()
+	repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		Return(errors.New("fatal error")).
+		Once()
+	referrals.On("Remove", mock.MatchedBy(func(ctx context.Context) bool { return true }), "bob").
+		Return(nil).
+		Once()
+
+	srv := service.New(repo, service.WithReferrals(referrals, referralProgram))
+
+	_, err := srv.RedeemReferral(context.Background(), domain.Customer{ID: "bob"}, "REFCODE")
+	assert.EqualError(t, err, "fatal error")
+}
+
+func TestRedeemReferralTakenCode(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedeemReferralTakenCode in long mode.")
+	}
+
+	repo := mocks.NewRepository(t)
+	referrals := mocks.NewReferralRepository(t)
+
+	referrals.On("FindReferrer", mock.MatchedBy(func(ctx context.Context) bool { return true }), "REFCODE").
+		Return("alice", nil).
+		Once()
+	referrals.On("FindFirstOrder", mock.MatchedBy(func(ctx context.Context) bool { return true }), "bob").
+		Return("", repository.ErrNotFound).
+		Once()
+	referrals.On("Add", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		Return(true, nil).
+		Once()
+
+	var taken string
+	repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		Run(func(args mock.Arguments) { taken = args.Get(1).(domain.Coupon).Code }).
+		Return(repository.ErrAlreadyExists).
+		Once()
+	repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+		Return(nil).
+		Once()
+
+	srv := service.New(repo, service.WithReferrals(referrals, referralProgram))
+
+	coupon, err := srv.RedeemReferral(context.Background(), domain.Customer{ID: "bob"}, "REFCODE")
+	require.NoError(t, err)
+	assert.NotEqual(t, taken, coupon.Code, "expected a taken code to be drawn again")
+}
+
+func TestCheckReferrals(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCheckReferrals in long mode.")
+	}
+
+	digits, err := couponcode.NewLuhn("23456789")
+	require.NoError(t, err)
+
+	type testCase struct {
+		name        string
+		opts        []service.Option
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Prefix within alphabet",
+			opts: []service.Option{service.WithReferrals(memory.NewReferrals(), referralProgram)},
+		},
+		{
+			name:        "Prefix outside check digit alphabet",
+			opts:        []service.Option{service.WithReferrals(memory.NewReferrals(), referralProgram), service.WithCheckDigit(digits)},
+			expectedErr: service.ErrInvalidPrefix,
+		},
+		{
+			name: "Prefix changed by case folding",
+			opts: []service.Option{
+				service.WithReferrals(memory.NewReferrals(), domain.ReferralProgram{CodePrefix: "ref"}),
+				service.WithCodePolicy(couponcode.Policy{FoldCase: true}),
+			},
+			expectedErr: service.ErrInvalidPrefix,
+		},
+		{
+			name: "Referrals disabled",
+			opts: []service.Option{service.WithCheckDigit(digits)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := service.New(memory.New(), tc.opts...)
+
+			err := srv.CheckReferrals()
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
+
+func TestReferralsDisabled(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestReferralsDisabled in long mode.")
+	}
+
+	srv := service.New(mocks.NewRepository(t))
+	customer := domain.Customer{ID: "alice"}
+
+	_, err := srv.GetReferralCode(context.Background(), customer)
+	assert.ErrorIs(t, err, service.ErrReferralsDisabled)
+
+	_, err = srv.RedeemReferral(context.Background(), customer, "REFCODE")
+	assert.ErrorIs(t, err, service.ErrReferralsDisabled)
+
+	_, err = srv.GetReferralStats(context.Background(), customer)
+	assert.ErrorIs(t, err, service.ErrReferralsDisabled)
+}
@@ -2,11 +2,109 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
 )
 
+// Repository stores coupons. Implementations report missing coupons with
+// repository.ErrNotFound, writes contradicting the stored state with
+// repository.ErrConflict, and storage failures with repository.ErrUnavailable.
 type Repository interface {
 	FindByCode(context.Context, string) (*domain.Coupon, error)
+	// FindByCodes returns the coupons with the given codes in the order of
+	// the codes, leaving out codes no coupon has.
+	FindByCodes(context.Context, []string) ([]domain.Coupon, error)
+	// Save stores the coupon whatever is stored under its code, as the next
+	// version of the coupon it replaces or as version 1.
 	Save(context.Context, domain.Coupon) error
+	// Insert stores the coupon as version 1 unless its code is taken, checked
+	// atomically with storing it, and fails with repository.ErrAlreadyExists,
+	// a conflict, otherwise.
+	Insert(context.Context, domain.Coupon) error
+	// Update replaces the coupon with the same code if its stored version is
+	// still coupon.Version, and stores it as the next version. It fails with
+	// repository.ErrNotFound if there is no such coupon, and with
+	// repository.ErrVersionMismatch, a conflict, if the version differs.
+	Update(context.Context, domain.Coupon) error
+	// Delete removes the coupon with the code if its stored version is still
+	// the given one, failing like Update otherwise.
+	Delete(context.Context, string, int) error
+	// FindByCustomer returns the coupons assigned to the customer, either
+	// directly or through one of their segments.
+	FindByCustomer(context.Context, domain.Customer) ([]domain.Coupon, error)
+	// FindActivatable returns the coupons assigned to nobody that require
+	// activation, which every customer can add to their wallet.
+	FindActivatable(context.Context) ([]domain.Coupon, error)
+}
+
+// WalletRepository stores which coupons a customer activated, keyed by
+// customer ID and coupon code.
+type WalletRepository interface {
+	Activate(context.Context, string, string) error
+	Deactivate(context.Context, string, string) error
+	IsActive(context.Context, string, string) (bool, error)
+	FindActive(context.Context, string) ([]string, error)
+}
+
+type PromotionRepository interface {
+	FindAll(context.Context) ([]domain.Promotion, error)
+	Save(context.Context, domain.Promotion) error
+}
+
+type TriggerRepository interface {
+	FindAll(context.Context) ([]domain.Trigger, error)
+	Save(context.Context, domain.Trigger) error
+	Reserve(context.Context, domain.Issuance) (domain.Issuance, bool, error)
+	Release(context.Context, domain.Issuance) error
+}
+
+// ReferralRepository stores the referral code of every referrer and the
+// referrals made with it, keyed by referee ID.
+type ReferralRepository interface {
+	// SaveCode stores the code for the referrer unless they have one
+	// already, and returns the code in effect.
+	SaveCode(context.Context, string, string) (string, error)
+	FindCode(context.Context, string) (string, error)
+	FindReferrer(context.Context, string) (string, error)
+	// Add records the referral unless the referee was referred before.
+	Add(context.Context, domain.Referral) (bool, error)
+	Remove(context.Context, string) error
+	FindByReferrer(context.Context, string) ([]domain.Referral, error)
+	// Complete settles the pending referral of the referee. It is rewarded
+	// with the given code while the referrer has fewer than the maximum
+	// rewards, and capped otherwise.
+	Complete(context.Context, string, string, int) (domain.Referral, bool, error)
+	// Reopen returns a rewarded referral to pending.
+	Reopen(context.Context, string) error
+	// SaveFirstOrder stores the order as the first completed order of the
+	// customer unless they have one already, and returns the order in
+	// effect.
+	SaveFirstOrder(context.Context, string, string) (string, error)
+	FindFirstOrder(context.Context, string) (string, error)
+}
+
+// RedemptionRepository stores the redemption history of customers.
+type RedemptionRepository interface {
+	// Count returns how often the customer redeemed the code since the
+	// given time.
+	Count(context.Context, string, string, time.Time) (int, error)
+	// Record stores the redemption unless it would exceed one of the
+	// windows, which is checked atomically with storing it.
+	Record(context.Context, domain.Redemption, []domain.UsageWindow) (bool, error)
+	// HasRedeemed reports whether the customer redeemed any coupon.
+	HasRedeemed(context.Context, string) (bool, error)
+}
+
+// BackupRepository copies the database holding the coupons while it is in
+// use.
+type BackupRepository interface {
+	Backup(context.Context, io.Writer) error
+}
+
+// CacheRepository reports how well a caching coupon repository answers
+// lookups itself.
+type CacheRepository interface {
+	Stats() domain.CacheStats
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+// ruleErrors are returned when a coupon does not qualify for a basket, as
+// opposed to the coupon being unusable or the lookup failing.
+var ruleErrors = []error{
+	ErrNotAssigned,
+	ErrNotActivated,
+	ErrInvalidBasketValue,
+	ErrDiscountNotApplicable,
+	ErrMinBasketValue,
+	ErrOutsideSchedule,
+	ErrChannelNotAllowed,
+	ErrStoreNotAllowed,
+	ErrRegionNotAllowed,
+	ErrUsageLimitReached,
+	ErrCustomerRequired,
+	ErrConditionsNotMet,
+	ErrExpressionNotMet,
+	ErrExpressionFailed,
+}
+
+func isRuleError(err error) bool {
+	for _, ruleErr := range ruleErrors {
+		if errors.Is(err, ruleErr) {
+			return true
+		}
+	}
+	return false
+}
+
+type rule struct {
+	name  string
+	check func(context.Context, *domain.Coupon, domain.Basket) error
+}
+
+// rules lists the checks a coupon has to pass for a basket, in the order
+// they are evaluated.
+func (s Service) rules() []rule {
+	return []rule{
+		{name: "assignment", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			if !coupon.Assignment.Allows(basket.Customer) {
+				return ErrNotAssigned
+			}
+			return nil
+		}},
+		{name: "activation", check: func(ctx context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			return s.checkActivated(ctx, basket.Customer, coupon)
+		}},
+		{name: "schedule", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			return s.checkSchedule(coupon, basket)
+		}},
+		{name: "channel", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			if !coupon.Eligibility.Channels.Allows(basket.Channel) {
+				return ErrChannelNotAllowed
+			}
+			return nil
+		}},
+		{name: "store", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			if !coupon.Eligibility.Stores.Allows(basket.StoreID) {
+				return ErrStoreNotAllowed
+			}
+			return nil
+		}},
+		{name: "region", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			if !coupon.Eligibility.Regions.Allows(basket.Region) {
+				return ErrRegionNotAllowed
+			}
+			return nil
+		}},
+		{name: "basketValue", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			_, err := s.calculate(coupon, basket)
+			return err
+		}},
+		{name: "minBasketValue", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			if basket.Value < coupon.MinBasketValue {
+				return ErrMinBasketValue
+			}
+			return nil
+		}},
+		{name: "conditions", check: func(_ context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+			return checkConditions(coupon, basket)
+		}},
+		{name: "expression", check: s.checkExpression},
+		{name: "usage", check: s.checkUsage},
+	}
+}
+
+// evaluate checks every rule of the coupon against the basket and returns
+// the discount it grants. Coupons and auto promotions share these rules.
+func (s Service) evaluate(ctx context.Context, coupon *domain.Coupon, basket domain.Basket) (int, error) {
+	for _, rule := range s.rules() {
+		if err := rule.check(ctx, coupon, basket); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.calculate(coupon, basket)
+}
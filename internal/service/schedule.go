@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+var (
+	ErrInvalidTimeZone = errors.New("invalid time zone")
+	ErrOutsideSchedule = errors.New("coupon not valid at this time")
+)
+
+func validSchedule(schedule domain.Schedule) bool {
+	for _, weekday := range schedule.Weekdays {
+		if weekday < time.Sunday || weekday > time.Saturday {
+			return false
+		}
+	}
+
+	for _, window := range schedule.Windows {
+		if !window.Valid() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validTimeZone reports whether name is empty, as baskets only need a time
+// zone for coupons evaluated at a local time, or an IANA time zone. "Local"
+// is rejected, as it would depend on where the service runs.
+func validTimeZone(name string) bool {
+	if name == "" {
+		return true
+	}
+	_, err := basketLocation(name)
+	return err == nil
+}
+
+// basketLocation loads the time zone of a basket, which has to be given
+// for the coupons evaluated at a local time. An empty name would otherwise
+// load UTC and "Local" the zone of the server.
+func basketLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	return location, nil
+}
+
+// checkSchedule evaluates the schedule of the coupon at the current time in
+// the time zone of the basket.
+func (s Service) checkSchedule(coupon *domain.Coupon, basket domain.Basket) error {
+	if !coupon.Schedule.Restricted() {
+		return nil
+	}
+
+	location, err := basketLocation(basket.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	if !coupon.Schedule.Allows(s.now().In(location)) {
+		return ErrOutsideSchedule
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestApplyCouponSchedule(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyCouponSchedule in long mode.")
+	}
+
+	happyHour := domain.Schedule{Windows: []domain.TimeWindow{{Start: 17 * 60, End: 19 * 60}}}
+	weekend := domain.Schedule{Weekdays: []time.Weekday{time.Saturday, time.Sunday}}
+	lateFriday := domain.Schedule{
+		Weekdays: []time.Weekday{time.Friday},
+		Windows:  []domain.TimeWindow{{Start: 22 * 60, End: 2 * 60}},
+	}
+	nightOfSpringForward := domain.Schedule{Windows: []domain.TimeWindow{{Start: 60, End: 3 * 60}}}
+
+	type testCase struct {
+		name        string
+		schedule    domain.Schedule
+		now         time.Time
+		timeZone    string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Happy hour in winter time",
+			schedule: happyHour,
+			now:      time.Date(2024, 1, 15, 17, 30, 0, 0, time.UTC),
+			timeZone: "Europe/Berlin",
+		},
+		{
+			name:        "Same UTC time is after happy hour in summer time",
+			schedule:    happyHour,
+			now:         time.Date(2024, 7, 15, 17, 30, 0, 0, time.UTC),
+			timeZone:    "Europe/Berlin",
+			expectedErr: service.ErrOutsideSchedule,
+		},
+		{
+			name:     "Happy hour in summer time",
+			schedule: happyHour,
+			now:      time.Date(2024, 7, 15, 16, 30, 0, 0, time.UTC),
+			timeZone: "Europe/Berlin",
+		},
+		{
+			name:        "Happy hour ends exclusive",
+			schedule:    happyHour,
+			now:         time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC),
+			timeZone:    "Europe/Berlin",
+			expectedErr: service.ErrOutsideSchedule,
+		},
+		{
+			name:        "Missing time zone",
+			schedule:    happyHour,
+			now:         time.Date(2024, 1, 15, 17, 30, 0, 0, time.UTC),
+			expectedErr: service.ErrInvalidTimeZone,
+		},
+		{
+			name:        "Time zone of the server",
+			schedule:    happyHour,
+			now:         time.Date(2024, 1, 15, 17, 30, 0, 0, time.UTC),
+			timeZone:    "Local",
+			expectedErr: service.ErrInvalidTimeZone,
+		},
+		{
+			name:     "Weekend in store time zone",
+			schedule: weekend,
+			now:      time.Date(2024, 1, 19, 23, 30, 0, 0, time.UTC),
+			timeZone: "Europe/Berlin",
+		},
+		{
+			name:        "Friday in store time zone",
+			schedule:    weekend,
+			now:         time.Date(2024, 1, 19, 22, 30, 0, 0, time.UTC),
+			timeZone:    "Europe/Berlin",
+			expectedErr: service.ErrOutsideSchedule,
+		},
+		{
+			name:     "Window past midnight belongs to starting day",
+			schedule: lateFriday,
+			now:      time.Date(2024, 1, 20, 0, 30, 0, 0, time.UTC),
+			timeZone: "Europe/Berlin",
+		},
+		{
+			name:        "Window past midnight on other day",
+			schedule:    lateFriday,
+			now:         time.Date(2024, 1, 21, 0, 30, 0, 0, time.UTC),
+			timeZone:    "Europe/Berlin",
+			expectedErr: service.ErrOutsideSchedule,
+		},
+		{
+			name:     "Last minute before clocks spring forward",
+			schedule: nightOfSpringForward,
+			now:      time.Date(2024, 3, 31, 0, 59, 0, 0, time.UTC),
+			timeZone: "Europe/Berlin",
+		},
+		{
+			name:        "Skipped hour when clocks spring forward",
+			schedule:    nightOfSpringForward,
+			now:         time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC),
+			timeZone:    "Europe/Berlin",
+			expectedErr: service.ErrOutsideSchedule,
+		},
+		{
+			name:     "Repeated hour when clocks fall back",
+			schedule: domain.Schedule{Windows: []domain.TimeWindow{{Start: 2 * 60, End: 3 * 60}}},
+			now:      time.Date(2024, 10, 27, 1, 30, 0, 0, time.UTC),
+			timeZone: "Europe/Berlin",
+		},
+		{
+			name:        "Unknown time zone",
+			schedule:    happyHour,
+			now:         time.Date(2024, 1, 15, 17, 30, 0, 0, time.UTC),
+			timeZone:    "Europe/Atlantis",
+			expectedErr: service.ErrInvalidTimeZone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			// Invalid time zones are rejected before the lookup, while a
+			// missing one is only rejected for coupons with a schedule.
+			if tc.expectedErr != service.ErrInvalidTimeZone || tc.timeZone == "" {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "happy").
+					Return(&domain.Coupon{Code: "happy", Discount: 10, Schedule: tc.schedule}, nil).
+					Once()
+			}
+
+			srv := service.New(repo, service.WithClock(func() time.Time { return tc.now }))
+
+			basket, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100, TimeZone: tc.timeZone}, "happy")
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, 10, basket.AppliedDiscount)
+		})
+	}
+}
+
+func TestCreateCouponSchedule(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateCouponSchedule in long mode.")
+	}
+
+	invalid := []domain.Schedule{
+		{Weekdays: []time.Weekday{7}},
+		{Windows: []domain.TimeWindow{{Start: 60, End: 60}}},
+		{Windows: []domain.TimeWindow{{Start: -1, End: 60}}},
+		{Windows: []domain.TimeWindow{{Start: 60, End: domain.MinutesPerDay + 1}}},
+	}
+
+	for _, schedule := range invalid {
+		srv := service.New(mocks.NewRepository(t))
+
+		err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "happy", Discount: 10, Schedule: schedule})
+		assert.ErrorIs(t, err, service.ErrInvalidSchedule, "expected schedule %v to be invalid", schedule)
+	}
+}
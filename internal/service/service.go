@@ -3,112 +3,339 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
-	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/expression"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/discount"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
 )
 
 var (
 	ErrInvalidCode           = errors.New("invalid code")
 	ErrNotFound              = errors.New("coupon not found")
+	ErrCouponExists          = errors.New("coupon already exists")
+	ErrVersionMismatch       = errors.New("coupon was changed in the meantime")
 	ErrInvalidDiscount       = errors.New("invalid discount")
 	ErrInvalidMinBasketValue = errors.New("invalid min basket")
 	ErrInvalidBasketValue    = errors.New("invalid basket value")
 	ErrMinBasketValue        = errors.New("not sufficient basket value")
+	ErrMalformedCode         = errors.New("malformed code")
+	ErrInvalidCodeFormat     = errors.New("invalid code format")
+	ErrInvalidSchedule       = errors.New("invalid schedule")
 )
 
+// ErrUnavailable wraps failures of the storage itself, which are worth
+// retrying, unlike everything the service rejects.
+var ErrUnavailable = repository.ErrUnavailable
+
 type Service struct {
-	repo Repository
+	repo        Repository
+	jobs        *jobStore
+	checkDigit  couponcode.CheckDigit
+	policy      couponcode.Policy
+	signer      token.Signer
+	keyring     *token.Keyring
+	wallet      WalletRepository
+	promotions  PromotionRepository
+	triggers    TriggerRepository
+	referrals   ReferralRepository
+	redemptions RedemptionRepository
+	expressions *expression.Engine
+	discounts   *discount.Registry
+	backups     BackupRepository
+	cache       CacheRepository
+
+	referralProgram domain.ReferralProgram
+	now             func() time.Time
 }
 
-func New(repo Repository) Service {
-	return Service{
-		repo: repo,
+type Option func(*Service)
+
+// WithCodePolicy normalizes every code before it is stored or looked up and
+// rejects new codes that do not match the policy format.
+func WithCodePolicy(policy couponcode.Policy) Option {
+	return func(s *Service) {
+		s.policy = policy
 	}
 }
 
-func (s Service) CreateCoupon(ctx context.Context, discount int, code string, minBasketValue int) error {
-	if code == "" {
-		return ErrInvalidCode
+// WithCheckDigit makes every coupon code carry a check character. Codes
+// failing the check are rejected without a repository lookup.
+func WithCheckDigit(checkDigit couponcode.CheckDigit) Option {
+	return func(s *Service) {
+		s.checkDigit = checkDigit
 	}
+}
 
-	if discount < 0 || discount > 100 {
-		return ErrInvalidDiscount
+// WithClock replaces the wall clock that schedules and token expiries are
+// checked against.
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		s.now = now
 	}
+}
 
-	if minBasketValue < 0 {
-		return ErrInvalidMinBasketValue
+func New(repo Repository, opts ...Option) Service {
+	s := Service{
+		repo:      repo,
+		jobs:      newJobStore(),
+		discounts: discount.NewRegistry(),
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(&s)
 	}
 
-	if _, err := s.repo.FindByCode(ctx, code); err == nil || !errors.Is(err, memory.ErrNotFound) {
+	return s
+}
+
+func (s Service) CreateCoupon(ctx context.Context, coupon domain.Coupon) error {
+	code := s.policy.Normalize(coupon.Code)
+
+	if code == "" || token.IsToken(code) {
 		return ErrInvalidCode
 	}
 
-	coupon := domain.Coupon{
-		ID:             uuid.NewString(),
-		Code:           code,
-		Discount:       discount,
-		MinBasketValue: minBasketValue,
+	if err := s.policy.Validate(code); err != nil {
+		return ErrInvalidCodeFormat
 	}
 
-	if err := s.repo.Save(ctx, coupon); err != nil {
+	if !s.wellFormed(code) {
+		return ErrMalformedCode
+	}
+
+	if err := s.validateCoupon(coupon); err != nil {
+		return err
+	}
+
+	coupon.ID = uuid.NewString()
+	coupon.Code = code
+
+	if err := s.repo.Insert(ctx, coupon); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrCouponExists
+		}
 		return err
 	}
 	return nil
 }
 
-func (s Service) GetCoupons(ctx context.Context, codes []string) ([]domain.Coupon, error) {
-	coupons := make([]domain.Coupon, 0, len(codes))
+// GetCoupon returns the coupon with the code. Signed tokens are not stored
+// and cannot be looked up.
+func (s Service) GetCoupon(ctx context.Context, code string) (*domain.Coupon, error) {
+	code, err := s.storedCode(code)
+	if err != nil {
+		return nil, err
+	}
 
+	coupon, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return coupon, nil
+}
+
+// UpdateCoupon applies the patch to the coupon with the code, provided it is
+// still at the given version, and returns the coupon as stored.
+func (s Service) UpdateCoupon(ctx context.Context, code string, version int, patch domain.CouponPatch) (*domain.Coupon, error) {
+	coupon, err := s.GetCoupon(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if coupon.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
+	updated := patch.Apply(*coupon)
+	if err := s.validateCoupon(updated); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, updated); err != nil {
+		return nil, versionError(err)
+	}
+	updated.Version++
+	return &updated, nil
+}
+
+// DeleteCoupon removes the coupon with the code, provided it is still at
+// the given version.
+func (s Service) DeleteCoupon(ctx context.Context, code string, version int) error {
+	code, err := s.storedCode(code)
+	if err != nil {
+		return err
+	}
+
+	return versionError(s.repo.Delete(ctx, code, version))
+}
+
+// storedCode normalizes a code addressing a stored coupon.
+func (s Service) storedCode(code string) (string, error) {
+	code = s.policy.Normalize(code)
+	if code == "" || token.IsToken(code) {
+		return "", ErrInvalidCode
+	}
+	if !s.wellFormed(code) {
+		return "", ErrMalformedCode
+	}
+	return code, nil
+}
+
+// versionError maps the errors of writes conditional on the version.
+func versionError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrNotFound
+	case errors.Is(err, repository.ErrVersionMismatch):
+		return ErrVersionMismatch
+	default:
+		return err
+	}
+}
+
+// GetCoupons returns the coupons with the given codes, looked up at once.
+// Codes that are equal once normalized are looked up only once.
+func (s Service) GetCoupons(ctx context.Context, codes []string) ([]domain.Coupon, error) {
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
 	for _, code := range codes {
-		coupon, err := s.repo.FindByCode(ctx, code)
-		if err != nil {
-			if errors.Is(err, memory.ErrNotFound) {
-				continue
-			}
-			return nil, err
+		code = s.policy.Normalize(code)
+		if !s.wellFormed(code) {
+			return nil, ErrMalformedCode
+		}
+		if seen[code] {
+			continue
 		}
-		coupons = append(coupons, *coupon)
+		seen[code] = true
+		normalized = append(normalized, code)
 	}
 
-	return coupons, nil
+	return s.repo.FindByCodes(ctx, normalized)
 }
 
 func (s Service) ApplyCoupon(ctx context.Context, basket domain.Basket, code string) (*domain.Basket, error) {
-	var (
-		err error
-	)
+	_, discount, err := s.apply(ctx, basket, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Basket{
+		Value:           basket.Value - discount,
+		AppliedDiscount: discount,
+	}, nil
+}
+
+func (s Service) apply(ctx context.Context, basket domain.Basket, code string) (*domain.Coupon, int, error) {
+	code = s.normalize(code)
 
 	if code == "" {
-		return nil, ErrInvalidCode
+		return nil, 0, ErrInvalidCode
 	}
 
 	if basket.Value <= 0 {
-		return nil, ErrInvalidBasketValue
+		return nil, 0, ErrInvalidBasketValue
+	}
+
+	if !validTimeZone(basket.TimeZone) {
+		return nil, 0, ErrInvalidTimeZone
+	}
+
+	if !validLines(basket.Lines) {
+		return nil, 0, ErrInvalidBasketLine
+	}
+
+	coupon, err := s.findCoupon(ctx, code)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	discount, err := s.evaluate(ctx, coupon, basket)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return coupon, discount, nil
+}
+
+// findCoupon resolves a normalized code or a signed token to its coupon.
+// Tokens are verified offline and never hit the repository.
+func (s Service) findCoupon(ctx context.Context, code string) (*domain.Coupon, error) {
+	if token.IsToken(code) {
+		return s.tokenCoupon(ctx, code)
+	}
+
+	if !s.wellFormed(code) {
+		return nil, ErrMalformedCode
 	}
 
 	coupon, err := s.repo.FindByCode(ctx, code)
 	if err != nil {
-		switch err {
-		case memory.ErrNotFound:
+		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrNotFound
-		default:
-			return nil, err
 		}
+		return nil, err
 	}
 
-	if basket.Value < coupon.Discount {
-		return nil, ErrInvalidBasketValue
+	return coupon, nil
+}
+
+// validateCoupon checks the terms of a coupon, leaving its code to the
+// caller.
+func (s Service) validateCoupon(coupon domain.Coupon) error {
+	if coupon.MinBasketValue < 0 {
+		return ErrInvalidMinBasketValue
 	}
 
-	if basket.Value < coupon.MinBasketValue {
-		return nil, ErrMinBasketValue
+	if err := s.validateDiscount(coupon); err != nil {
+		return err
 	}
 
-	return &domain.Basket{
-		Value:           basket.Value - coupon.Discount,
-		AppliedDiscount: coupon.Discount,
-	}, nil
+	if !validAssignment(coupon.Assignment) {
+		return ErrInvalidAssignment
+	}
+
+	if !validSchedule(coupon.Schedule) {
+		return ErrInvalidSchedule
+	}
+
+	if !validEligibility(coupon.Eligibility) {
+		return ErrInvalidEligibility
+	}
+
+	if !validUsageLimits(coupon.UsageLimits) {
+		return ErrInvalidUsageLimit
+	}
+
+	if !validConditions(coupon.Conditions, 1) {
+		return ErrInvalidCondition
+	}
+
+	if len(coupon.UsageLimits) > 0 && s.redemptions == nil {
+		return ErrRedemptionsDisabled
+	}
+
+	return s.compileExpression(coupon.Expression)
+}
+
+// normalize applies the code policy. Signed tokens are case sensitive and
+// only have surrounding whitespace removed.
+func (s Service) normalize(code string) string {
+	if trimmed := strings.TrimSpace(code); token.IsToken(trimmed) {
+		return trimmed
+	}
+	return s.policy.Normalize(code)
+}
+
+func (s Service) wellFormed(code string) bool {
+	return s.checkDigit == nil || s.checkDigit.Valid(code)
 }
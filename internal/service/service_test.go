@@ -4,13 +4,16 @@ import (
 	"context"
 	"errors"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
-	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
 )
@@ -24,6 +27,7 @@ func TestCreateCoupon(t *testing.T) {
 		code           string
 		discount       int
 		minBasketValue int
+		assignment     domain.Assignment
 	}
 
 	type testCase struct {
@@ -38,12 +42,7 @@ func TestCreateCoupon(t *testing.T) {
 			name: "Successful coupon creation",
 			args: args{code: "test", discount: 10, minBasketValue: 5},
 			setupMocks: func(repo *mocks.Repository, args args) {
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
-					return true
-				}), args.code).
-					Return(nil, memory.ErrNotFound).
-					Once()
-				repo.On("Save", mock.MatchedBy(func(ctx context.Context) bool {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool {
 					return true
 				}), mock.MatchedBy(func(coupon domain.Coupon) bool {
 					return coupon.ID != "" &&
@@ -64,13 +63,25 @@ func TestCreateCoupon(t *testing.T) {
 			name: "Duplicated coupon code",
 			args: args{code: "test", discount: 10, minBasketValue: 5},
 			setupMocks: func(repo *mocks.Repository, args args) {
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool {
 					return true
-				}), args.code).
-					Return(&domain.Coupon{}, nil).
+				}), mock.MatchedBy(func(coupon domain.Coupon) bool {
+					return coupon.Code == args.code
+				})).
+					Return(repository.ErrAlreadyExists).
 					Once()
 			},
-			expectedErr: service.ErrInvalidCode,
+			expectedErr: service.ErrCouponExists,
+		},
+		{
+			name: "Storage unavailable",
+			args: args{code: "test", discount: 10, minBasketValue: 5},
+			setupMocks: func(repo *mocks.Repository, args args) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(repository.Unavailable(errors.New("connection refused"))).
+					Once()
+			},
+			expectedErr: service.ErrUnavailable,
 		},
 		{
 			name:        "Negative discount value",
@@ -90,6 +101,26 @@ func TestCreateCoupon(t *testing.T) {
 			setupMocks:  func(repo *mocks.Repository, args args) {},
 			expectedErr: service.ErrInvalidMinBasketValue,
 		},
+		{
+			name: "Successful personal coupon creation",
+			args: args{code: "test", discount: 10, assignment: domain.Assignment{CustomerIDs: []string{"c1"}}},
+			setupMocks: func(repo *mocks.Repository, args args) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }),
+					mock.MatchedBy(func(coupon domain.Coupon) bool {
+						return coupon.Code == args.code &&
+							reflect.DeepEqual(coupon.Assignment, args.assignment)
+					})).
+					Return(nil).
+					Once()
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "Empty customer ID in assignment",
+			args:        args{code: "test", discount: 10, assignment: domain.Assignment{CustomerIDs: []string{""}}},
+			setupMocks:  func(repo *mocks.Repository, args args) {},
+			expectedErr: service.ErrInvalidAssignment,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -101,10 +132,14 @@ func TestCreateCoupon(t *testing.T) {
 			srv := service.New(repo)
 			ctx := context.Background()
 
-			err := srv.CreateCoupon(ctx, tc.args.discount, tc.args.code, tc.args.minBasketValue)
+			err := srv.CreateCoupon(ctx, domain.Coupon{
+				Code:           tc.args.code,
+				Discount:       tc.args.discount,
+				MinBasketValue: tc.args.minBasketValue,
+				Assignment:     tc.args.assignment,
+			})
 			if tc.expectedErr != nil {
-				assert.Error(t, err, "expected error to be %v, got: %v", tc.expectedErr, err)
-				assert.IsType(t, tc.expectedErr, err, "expected error %T, got: %T", tc.expectedErr, err)
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error to be %v, got: %v", tc.expectedErr, err)
 				return
 			}
 
@@ -132,15 +167,13 @@ func TestGetCoupon(t *testing.T) {
 			name:  "Successful coupons retrieval",
 			codes: []string{"test1", "test2"},
 			setupMocks: func(repo *mocks.Repository) {
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
-					return true
-				}), "test1").
-					Return(&domain.Coupon{ID: "id1", Code: "test1", Discount: 10, MinBasketValue: 0}, nil).
-					Once()
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
+				repo.On("FindByCodes", mock.MatchedBy(func(ctx context.Context) bool {
 					return true
-				}), "test2").
-					Return(&domain.Coupon{ID: "id2", Code: "test2", Discount: 10, MinBasketValue: 0}, nil).
+				}), []string{"test1", "test2"}).
+					Return([]domain.Coupon{
+						{ID: "id1", Code: "test1", Discount: 10, MinBasketValue: 0},
+						{ID: "id2", Code: "test2", Discount: 10, MinBasketValue: 0},
+					}, nil).
 					Once()
 			},
 			want: []domain.Coupon{
@@ -153,15 +186,29 @@ func TestGetCoupon(t *testing.T) {
 			name:  "Successful coupons retrieval with no existing coupon",
 			codes: []string{"test1", "test2"},
 			setupMocks: func(repo *mocks.Repository) {
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
+				repo.On("FindByCodes", mock.MatchedBy(func(ctx context.Context) bool {
 					return true
-				}), "test1").
-					Return(&domain.Coupon{ID: "id1", Code: "test1", Discount: 10, MinBasketValue: 0}, nil).
+				}), []string{"test1", "test2"}).
+					Return([]domain.Coupon{
+						{ID: "id1", Code: "test1", Discount: 10, MinBasketValue: 0},
+					}, nil).
 					Once()
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
+			},
+			want: []domain.Coupon{
+				{ID: "id1", Code: "test1", Discount: 10, MinBasketValue: 0},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "Duplicated codes looked up once",
+			codes: []string{"test1", "test2", "test1"},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCodes", mock.MatchedBy(func(ctx context.Context) bool {
 					return true
-				}), "test2").
-					Return(nil, memory.ErrNotFound).Once().
+				}), []string{"test1", "test2"}).
+					Return([]domain.Coupon{
+						{ID: "id1", Code: "test1", Discount: 10, MinBasketValue: 0},
+					}, nil).
 					Once()
 			},
 			want: []domain.Coupon{
@@ -173,9 +220,9 @@ func TestGetCoupon(t *testing.T) {
 			name:  "Error during coupon retrieval",
 			codes: []string{"test1", "test2"},
 			setupMocks: func(repo *mocks.Repository) {
-				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool {
+				repo.On("FindByCodes", mock.MatchedBy(func(ctx context.Context) bool {
 					return true
-				}), "test1").
+				}), []string{"test1", "test2"}).
 					Return(nil, errors.New("fatal error")).
 					Once()
 			},
@@ -311,7 +358,7 @@ func TestApplyCoupon(t *testing.T) {
 			},
 			setupMocks: func(repo *mocks.Repository, code string) {
 				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), code).
-					Return(nil, memory.ErrNotFound).
+					Return(nil, repository.ErrNotFound).
 					Once()
 			},
 			want:        nil,
@@ -352,3 +399,452 @@ func TestApplyCoupon(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestUpdateCoupon in long mode.")
+	}
+
+	discount := 20
+	invalid := 200
+	channels := domain.Restriction{Allow: []string{"web"}}
+	limits := []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay}}
+	invalidCondition := []domain.Condition{{Type: "unknown"}}
+	strategy := "unknown"
+	stored := domain.Coupon{ID: "id1", Code: "test", Discount: 10, MinBasketValue: 5, Version: 3}
+
+	type testCase struct {
+		name        string
+		code        string
+		version     int
+		patch       domain.CouponPatch
+		setupMocks  func(*mocks.Repository)
+		want        *domain.Coupon
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Successful update",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Discount: &discount},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+				repo.On("Update", mock.MatchedBy(func(ctx context.Context) bool { return true }),
+					domain.Coupon{ID: "id1", Code: "test", Discount: 20, MinBasketValue: 5, Version: 3}).
+					Return(nil).
+					Once()
+			},
+			want: &domain.Coupon{ID: "id1", Code: "test", Discount: 20, MinBasketValue: 5, Version: 4},
+		},
+		{
+			name:    "Stale version",
+			code:    "test",
+			version: 2,
+			patch:   domain.CouponPatch{Discount: &discount},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+			},
+			expectedErr: service.ErrVersionMismatch,
+		},
+		{
+			name:    "Changed between lookup and update",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Discount: &discount},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+				repo.On("Update", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(repository.ErrVersionMismatch).
+					Once()
+			},
+			expectedErr: service.ErrVersionMismatch,
+		},
+		{
+			name:    "Deleted between lookup and update",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Discount: &discount},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+				repo.On("Update", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(repository.ErrNotFound).
+					Once()
+			},
+			expectedErr: service.ErrNotFound,
+		},
+		{
+			name:    "Missing coupon",
+			code:    "test",
+			version: 3,
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(nil, repository.ErrNotFound).
+					Once()
+			},
+			expectedErr: service.ErrNotFound,
+		},
+		{
+			name:    "Invalid patched discount",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Discount: &invalid},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+			},
+			expectedErr: service.ErrInvalidDiscount,
+		},
+		{
+			name:    "Patched eligibility",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Channels: &channels},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+				repo.On("Update", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(nil).
+					Once()
+			},
+			want: &domain.Coupon{ID: "id1", Code: "test", Discount: 10, MinBasketValue: 5,
+				Eligibility: domain.Eligibility{Channels: channels}, Version: 4},
+		},
+		{
+			name:    "Patched usage limits without redemptions",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{UsageLimits: &limits},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+			},
+			expectedErr: service.ErrRedemptionsDisabled,
+		},
+		{
+			name:    "Invalid patched condition",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Conditions: &invalidCondition},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+			},
+			expectedErr: service.ErrInvalidCondition,
+		},
+		{
+			name:    "Unknown patched strategy",
+			code:    "test",
+			version: 3,
+			patch:   domain.CouponPatch{Strategy: &strategy},
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "test").
+					Return(&stored, nil).
+					Once()
+			},
+			expectedErr: service.ErrInvalidStrategy,
+		},
+		{
+			name:        "Empty code",
+			code:        "",
+			version:     3,
+			setupMocks:  func(repo *mocks.Repository) {},
+			expectedErr: service.ErrInvalidCode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo)
+			defer repo.AssertExpectations(t)
+
+			srv := service.New(repo)
+
+			got, err := srv.UpdateCoupon(context.Background(), tc.code, tc.version, tc.patch)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error to be %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDeleteCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDeleteCoupon in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		code        string
+		repoErr     error
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "Successful deletion", code: "test"},
+		{name: "Stale version", code: "test", repoErr: repository.ErrVersionMismatch, expectedErr: service.ErrVersionMismatch},
+		{name: "Missing coupon", code: "test", repoErr: repository.ErrNotFound, expectedErr: service.ErrNotFound},
+		{name: "Storage unavailable", code: "test", repoErr: repository.Unavailable(errors.New("disk full")),
+			expectedErr: service.ErrUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("Delete", mock.MatchedBy(func(ctx context.Context) bool { return true }), tc.code, 3).
+				Return(tc.repoErr).
+				Once()
+			defer repo.AssertExpectations(t)
+
+			srv := service.New(repo)
+
+			err := srv.DeleteCoupon(context.Background(), tc.code, 3)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error to be %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCheckDigit in long mode.")
+	}
+
+	luhn, err := couponcode.NewLuhn(couponcode.DefaultAlphabet)
+	require.NoError(t, err)
+
+	type testCase struct {
+		name        string
+		code        string
+		setupMocks  func(*mocks.Repository, string)
+		call        func(service.Service, string) error
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:       "Create with typo",
+			code:       "ABCD9",
+			setupMocks: func(repo *mocks.Repository, code string) {},
+			call: func(srv service.Service, code string) error {
+				return srv.CreateCoupon(context.Background(), domain.Coupon{Code: code, Discount: 10})
+			},
+			expectedErr: service.ErrMalformedCode,
+		},
+		{
+			name:       "Get with typo",
+			code:       "ABCD9",
+			setupMocks: func(repo *mocks.Repository, code string) {},
+			call: func(srv service.Service, code string) error {
+				_, err := srv.GetCoupons(context.Background(), []string{"ABCD8", code})
+				return err
+			},
+			expectedErr: service.ErrMalformedCode,
+		},
+		{
+			name:       "Apply with typo",
+			code:       "ABCD9",
+			setupMocks: func(repo *mocks.Repository, code string) {},
+			call: func(srv service.Service, code string) error {
+				_, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100}, code)
+				return err
+			},
+			expectedErr: service.ErrMalformedCode,
+		},
+		{
+			name: "Apply with valid check char",
+			code: "ABCD8",
+			setupMocks: func(repo *mocks.Repository, code string) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), code).
+					Return(&domain.Coupon{ID: "id1", Code: code, Discount: 10}, nil).
+					Once()
+			},
+			call: func(srv service.Service, code string) error {
+				_, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100}, code)
+				return err
+			},
+		},
+		{
+			name:       "Generate with alphabet outside the scheme",
+			setupMocks: func(repo *mocks.Repository, code string) {},
+			call: func(srv service.Service, code string) error {
+				_, err := srv.GenerateCoupons(context.Background(), domain.CodeBatch{Count: 1, Length: 8, Alphabet: "abcdefgh"})
+				return err
+			},
+			expectedErr: service.ErrInvalidAlphabet,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo, tc.code)
+			defer repo.AssertExpectations(t)
+
+			srv := service.New(repo, service.WithCheckDigit(luhn))
+
+			err := tc.call(srv, tc.code)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
+
+func TestCodePolicy(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCodePolicy in long mode.")
+	}
+
+	policy := couponcode.Policy{
+		Trim:       true,
+		FoldCase:   true,
+		Separators: "-",
+		Charset:    "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789",
+		MinLength:  4,
+		MaxLength:  12,
+	}
+
+	type testCase struct {
+		name        string
+		setupMocks  func(*mocks.Repository)
+		call        func(service.Service) error
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Create stores the normalized code",
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }),
+					mock.MatchedBy(func(coupon domain.Coupon) bool { return coupon.Code == "SUMMER10" })).
+					Return(nil).
+					Once()
+			},
+			call: func(srv service.Service) error {
+				return srv.CreateCoupon(context.Background(), domain.Coupon{Code: " summer-10 ", Discount: 10})
+			},
+		},
+		{
+			name:       "Create with character outside charset",
+			setupMocks: func(repo *mocks.Repository) {},
+			call: func(srv service.Service) error {
+				return srv.CreateCoupon(context.Background(), domain.Coupon{Code: "summer_10", Discount: 10})
+			},
+			expectedErr: service.ErrInvalidCodeFormat,
+		},
+		{
+			name:       "Create with too short code",
+			setupMocks: func(repo *mocks.Repository) {},
+			call: func(srv service.Service) error {
+				return srv.CreateCoupon(context.Background(), domain.Coupon{Code: "ab-c", Discount: 10})
+			},
+			expectedErr: service.ErrInvalidCodeFormat,
+		},
+		{
+			name:       "Create with separators only",
+			setupMocks: func(repo *mocks.Repository) {},
+			call: func(srv service.Service) error {
+				return srv.CreateCoupon(context.Background(), domain.Coupon{Code: " -- ", Discount: 10})
+			},
+			expectedErr: service.ErrInvalidCode,
+		},
+		{
+			name: "Get looks up the normalized code",
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCodes", mock.MatchedBy(func(ctx context.Context) bool { return true }), []string{"SUMMER10"}).
+					Return([]domain.Coupon{{ID: "id1", Code: "SUMMER10", Discount: 10}}, nil).
+					Once()
+			},
+			call: func(srv service.Service) error {
+				_, err := srv.GetCoupons(context.Background(), []string{" summer10 "})
+				return err
+			},
+		},
+		{
+			name: "Get looks up codes equal once normalized once",
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCodes", mock.MatchedBy(func(ctx context.Context) bool { return true }), []string{"SUMMER10"}).
+					Return([]domain.Coupon{{ID: "id1", Code: "SUMMER10", Discount: 10}}, nil).
+					Once()
+			},
+			call: func(srv service.Service) error {
+				_, err := srv.GetCoupons(context.Background(), []string{"summer10", "Summer-10", "SUMMER10"})
+				return err
+			},
+		},
+		{
+			name: "Apply looks up the normalized code",
+			setupMocks: func(repo *mocks.Repository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "SUMMER10").
+					Return(&domain.Coupon{ID: "id1", Code: "SUMMER10", Discount: 10}, nil).
+					Once()
+			},
+			call: func(srv service.Service) error {
+				_, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100}, "Summer-10")
+				return err
+			},
+		},
+		{
+			name:       "Generate with alphabet changed by normalization",
+			setupMocks: func(repo *mocks.Repository) {},
+			call: func(srv service.Service) error {
+				_, err := srv.GenerateCoupons(context.Background(), domain.CodeBatch{Count: 1, Length: 8, Alphabet: "abcdefgh"})
+				return err
+			},
+			expectedErr: service.ErrInvalidAlphabet,
+		},
+		{
+			name:       "Generate codes longer than the policy allows",
+			setupMocks: func(repo *mocks.Repository) {},
+			call: func(srv service.Service) error {
+				_, err := srv.GenerateCoupons(context.Background(), domain.CodeBatch{Count: 1, Length: 16})
+				return err
+			},
+			expectedErr: service.ErrInvalidCodeFormat,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			tc.setupMocks(repo)
+			defer repo.AssertExpectations(t)
+
+			srv := service.New(repo, service.WithCodePolicy(policy))
+
+			err := tc.call(srv)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
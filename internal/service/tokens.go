@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
+)
+
+var (
+	ErrTokensDisabled = errors.New("signed tokens not configured")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrTokenExpired   = errors.New("token expired")
+	ErrInvalidExpiry  = errors.New("invalid expiry")
+)
+
+// WithTokens enables signed coupon tokens. New tokens are signed with
+// signer; any key of the keyring is accepted when verifying.
+func WithTokens(signer token.Signer, keyring *token.Keyring) Option {
+	return func(s *Service) {
+		s.signer = signer
+		s.keyring = keyring
+	}
+}
+
+func (s Service) IssueToken(_ context.Context, discount int, minBasketValue int, expiresAt time.Time) (string, error) {
+	if s.signer == nil {
+		return "", ErrTokensDisabled
+	}
+
+	if discount < 0 || discount > 100 {
+		return "", ErrInvalidDiscount
+	}
+
+	if minBasketValue < 0 {
+		return "", ErrInvalidMinBasketValue
+	}
+
+	now := s.now()
+	if !expiresAt.After(now) {
+		return "", ErrInvalidExpiry
+	}
+
+	return token.Issue(s.signer, token.Claims{
+		ID:             uuid.NewString(),
+		Discount:       discount,
+		MinBasketValue: minBasketValue,
+		IssuedAt:       now.Unix(),
+		ExpiresAt:      expiresAt.Unix(),
+	})
+}
+
+func (s Service) VerifyToken(_ context.Context, tok string) (*token.Claims, error) {
+	if s.keyring == nil {
+		return nil, ErrTokensDisabled
+	}
+
+	claims, err := s.keyring.Verify(strings.TrimSpace(tok), s.now())
+	if err != nil {
+		switch err {
+		case token.ErrExpired:
+			return nil, ErrTokenExpired
+		default:
+			return nil, ErrInvalidToken
+		}
+	}
+
+	return claims, nil
+}
+
+func (s Service) tokenCoupon(ctx context.Context, tok string) (*domain.Coupon, error) {
+	claims, err := s.VerifyToken(ctx, tok)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Coupon{
+		ID:             claims.ID,
+		Code:           tok,
+		Discount:       claims.Discount,
+		MinBasketValue: claims.MinBasketValue,
+	}, nil
+}
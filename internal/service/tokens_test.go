@@ -0,0 +1,180 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
+)
+
+func newTokenService(t *testing.T) (service.Service, token.Signer) {
+	t.Helper()
+
+	signer, err := token.NewHMACKey("k1", []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	repo := mocks.NewRepository(t)
+
+	return service.New(repo, service.WithTokens(signer, token.NewKeyring(signer))), signer
+}
+
+func TestIssueToken(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestIssueToken in long mode.")
+	}
+
+	type args struct {
+		discount       int
+		minBasketValue int
+		expiresAt      time.Time
+	}
+
+	type testCase struct {
+		name        string
+		args        args
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "Successful issuing",
+			args: args{discount: 10, minBasketValue: 50, expiresAt: time.Now().Add(time.Hour)},
+		},
+		{
+			name:        "Expiry in the past",
+			args:        args{discount: 10, minBasketValue: 50, expiresAt: time.Now().Add(-time.Hour)},
+			expectedErr: service.ErrInvalidExpiry,
+		},
+		{
+			name:        "Missing expiry",
+			args:        args{discount: 10, minBasketValue: 50},
+			expectedErr: service.ErrInvalidExpiry,
+		},
+		{
+			name:        "Invalid discount",
+			args:        args{discount: 101, minBasketValue: 50, expiresAt: time.Now().Add(time.Hour)},
+			expectedErr: service.ErrInvalidDiscount,
+		},
+		{
+			name:        "Negative minimum basket value",
+			args:        args{discount: 10, minBasketValue: -1, expiresAt: time.Now().Add(time.Hour)},
+			expectedErr: service.ErrInvalidMinBasketValue,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv, _ := newTokenService(t)
+			ctx := context.Background()
+
+			tok, err := srv.IssueToken(ctx, tc.args.discount, tc.args.minBasketValue, tc.args.expiresAt)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+			require.NoError(t, err, "expected error nil, got: %v", err)
+
+			claims, err := srv.VerifyToken(ctx, tok)
+			require.NoError(t, err)
+			assert.Equal(t, tc.args.discount, claims.Discount)
+			assert.Equal(t, tc.args.minBasketValue, claims.MinBasketValue)
+			assert.Equal(t, tc.args.expiresAt.Unix(), claims.ExpiresAt)
+		})
+	}
+}
+
+func TestApplyToken(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyToken in long mode.")
+	}
+
+	srv, signer := newTokenService(t)
+	other, err := token.NewHMACKey("k2", []byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+
+	issue := func(signer token.Signer, claims token.Claims) string {
+		tok, err := token.Issue(signer, claims)
+		require.NoError(t, err)
+		return tok
+	}
+	hour := time.Now().Add(time.Hour).Unix()
+
+	type testCase struct {
+		name        string
+		token       string
+		basket      domain.Basket
+		want        *domain.Basket
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Valid token",
+			token:  issue(signer, token.Claims{ID: "id1", Discount: 10, MinBasketValue: 20, ExpiresAt: hour}),
+			basket: domain.Basket{Value: 50},
+			want:   &domain.Basket{Value: 40, AppliedDiscount: 10},
+		},
+		{
+			name:   "Surrounding whitespace",
+			token:  " " + issue(signer, token.Claims{ID: "id1", Discount: 10, ExpiresAt: hour}) + "\n",
+			basket: domain.Basket{Value: 50},
+			want:   &domain.Basket{Value: 40, AppliedDiscount: 10},
+		},
+		{
+			name:        "Basket below minimum",
+			token:       issue(signer, token.Claims{ID: "id1", Discount: 10, MinBasketValue: 100, ExpiresAt: hour}),
+			basket:      domain.Basket{Value: 50},
+			expectedErr: service.ErrMinBasketValue,
+		},
+		{
+			name:        "Expired token",
+			token:       issue(signer, token.Claims{ID: "id1", Discount: 10, ExpiresAt: time.Now().Add(-time.Minute).Unix()}),
+			basket:      domain.Basket{Value: 50},
+			expectedErr: service.ErrTokenExpired,
+		},
+		{
+			name:        "Token signed with unknown key",
+			token:       issue(other, token.Claims{ID: "id1", Discount: 10, ExpiresAt: hour}),
+			basket:      domain.Basket{Value: 50},
+			expectedErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := srv.ApplyCoupon(context.Background(), tc.basket, tc.token)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestTokensDisabled(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestTokensDisabled in long mode.")
+	}
+
+	srv := service.New(mocks.NewRepository(t))
+	ctx := context.Background()
+
+	_, err := srv.IssueToken(ctx, 10, 0, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, service.ErrTokensDisabled)
+
+	_, err = srv.ApplyCoupon(ctx, domain.Basket{Value: 50}, "ct1.k1.e30.c2ln")
+	assert.ErrorIs(t, err, service.ErrTokensDisabled)
+
+	err = srv.CreateCoupon(ctx, domain.Coupon{Code: "ct1.k1.e30.c2ln", Discount: 10})
+	assert.ErrorIs(t, err, service.ErrInvalidCode)
+}
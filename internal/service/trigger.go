@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/couponcode"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+)
+
+const issuedCodeLength = 10
+
+var (
+	ErrTriggersDisabled = errors.New("triggers not configured")
+	ErrInvalidTrigger   = errors.New("invalid trigger")
+	ErrInvalidEvent     = errors.New("invalid event")
+	ErrIssuancePending  = errors.New("coupon for event still being issued")
+)
+
+// WithTriggers enables earn-and-burn triggers, which issue personal coupons
+// for qualifying order events.
+func WithTriggers(triggers TriggerRepository) Option {
+	return func(s *Service) {
+		s.triggers = triggers
+	}
+}
+
+func (s Service) CreateTrigger(ctx context.Context, trigger domain.Trigger) (*domain.Trigger, error) {
+	if s.triggers == nil {
+		return nil, ErrTriggersDisabled
+	}
+
+	trigger.Name = strings.TrimSpace(trigger.Name)
+	if trigger.Name == "" || !validEvent(trigger.Event) || trigger.MinOrderValue < 0 {
+		return nil, ErrInvalidTrigger
+	}
+
+	if trigger.Code != "" {
+		if trigger.Event != domain.EventRedemption {
+			return nil, ErrInvalidTrigger
+		}
+		trigger.Code = s.normalize(trigger.Code)
+	}
+
+	if trigger.Template.Discount < 0 || trigger.Template.Discount > 100 {
+		return nil, ErrInvalidDiscount
+	}
+
+	if trigger.Template.MinBasketValue < 0 {
+		return nil, ErrInvalidMinBasketValue
+	}
+
+	if _, err := s.templateGenerator(trigger.Template); err != nil {
+		return nil, err
+	}
+
+	trigger.ID = uuid.NewString()
+
+	if err := s.triggers.Save(ctx, trigger); err != nil {
+		return nil, err
+	}
+	return &trigger, nil
+}
+
+func (s Service) GetTriggers(ctx context.Context) ([]domain.Trigger, error) {
+	if s.triggers == nil {
+		return nil, ErrTriggersDisabled
+	}
+
+	return s.triggers.FindAll(ctx)
+}
+
+// HandleEvent issues a coupon for every trigger the event qualifies for, and
+// rewards the referrer of a customer completing their first order. Replaying
+// an event for the same order returns the trigger coupons issued the first
+// time instead of issuing new ones, and fails with ErrIssuancePending while
+// they are still being issued.
+func (s Service) HandleEvent(ctx context.Context, event domain.OrderEvent) ([]domain.Coupon, error) {
+	if s.triggers == nil && s.referrals == nil {
+		return nil, ErrTriggersDisabled
+	}
+
+	if !validEvent(event.Type) || event.OrderID == "" || event.Customer.ID == "" || event.Value < 0 {
+		return nil, ErrInvalidEvent
+	}
+
+	coupons := make([]domain.Coupon, 0)
+
+	if s.triggers != nil {
+		triggers, err := s.triggers.FindAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, trigger := range triggers {
+			if !s.qualifies(trigger, event) {
+				continue
+			}
+
+			coupon, err := s.issue(ctx, trigger, event)
+			if err != nil {
+				return nil, err
+			}
+			if coupon != nil {
+				coupons = append(coupons, *coupon)
+			}
+		}
+	}
+
+	if s.referrals != nil && event.Type == domain.EventOrderCompleted {
+		coupon, err := s.rewardReferrer(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		if coupon != nil {
+			coupons = append(coupons, *coupon)
+		}
+	}
+
+	return coupons, nil
+}
+
+func (s Service) qualifies(trigger domain.Trigger, event domain.OrderEvent) bool {
+	if trigger.Event != event.Type || event.Value < trigger.MinOrderValue {
+		return false
+	}
+
+	return trigger.Code == "" || trigger.Code == s.normalize(event.Code)
+}
+
+// issue creates the coupon of the trigger for the order once. The issuance
+// is reserved under the drawn code before the coupon is saved so that
+// concurrent deliveries of the same event cannot both issue one. A code
+// taken in the meantime releases the reservation and is drawn again.
+func (s Service) issue(ctx context.Context, trigger domain.Trigger, event domain.OrderEvent) (*domain.Coupon, error) {
+	gen, err := s.templateGenerator(trigger.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	var issued *domain.Coupon
+	_, err = s.insertUnused(gen, nil, func(code string) error {
+		issuance, reserved, err := s.triggers.Reserve(ctx, domain.Issuance{
+			TriggerID: trigger.ID,
+			OrderID:   event.OrderID,
+			Code:      code,
+		})
+		if err != nil {
+			return err
+		}
+
+		// A concurrent delivery reserved the issuance but has not saved
+		// its coupon yet, which the caller has to retry for.
+		if !reserved {
+			issued, err = s.repo.FindByCode(ctx, issuance.Code)
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrIssuancePending
+			}
+			return err
+		}
+
+		issued, err = s.insertIssued(ctx, domain.Coupon{
+			ID:                 uuid.NewString(),
+			Code:               code,
+			Discount:           trigger.Template.Discount,
+			MinBasketValue:     trigger.Template.MinBasketValue,
+			Assignment:         domain.Assignment{CustomerIDs: []string{event.Customer.ID}},
+			RequiresActivation: trigger.Template.RequiresActivation,
+		})
+		if err != nil {
+			return rollback(err, func() error { return s.triggers.Release(ctx, issuance) })
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+// insertIssued inserts a coupon issued to a customer and returns it as
+// stored, at version 1.
+func (s Service) insertIssued(ctx context.Context, coupon domain.Coupon) (*domain.Coupon, error) {
+	if err := s.repo.Insert(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	coupon.Version = 1
+	return &coupon, nil
+}
+
+func (s Service) templateGenerator(template domain.CouponTemplate) (*couponcode.Generator, error) {
+	return s.batchGenerator(&domain.CodeBatch{
+		Count:  1,
+		Length: issuedCodeLength,
+		Prefix: template.Prefix,
+	})
+}
+
+func validEvent(event domain.TriggerEvent) bool {
+	return event == domain.EventRedemption || event == domain.EventOrderCompleted
+}
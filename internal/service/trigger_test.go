@@ -0,0 +1,308 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestCreateTrigger(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateTrigger in long mode.")
+	}
+
+	template := domain.CouponTemplate{Prefix: "NEXT", Discount: 5}
+
+	type testCase struct {
+		name        string
+		trigger     domain.Trigger
+		expectSave  bool
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:       "Order completed trigger",
+			trigger:    domain.Trigger{Name: "spend 50", Event: domain.EventOrderCompleted, MinOrderValue: 50, Template: template},
+			expectSave: true,
+		},
+		{
+			name:       "Redemption trigger for code",
+			trigger:    domain.Trigger{Name: "welcome back", Event: domain.EventRedemption, Code: "welcome", Template: template},
+			expectSave: true,
+		},
+		{
+			name:        "Missing name",
+			trigger:     domain.Trigger{Event: domain.EventOrderCompleted, Template: template},
+			expectedErr: service.ErrInvalidTrigger,
+		},
+		{
+			name:        "Unknown event",
+			trigger:     domain.Trigger{Name: "spend 50", Event: "refund", Template: template},
+			expectedErr: service.ErrInvalidTrigger,
+		},
+		{
+			name:        "Code on order completed trigger",
+			trigger:     domain.Trigger{Name: "spend 50", Event: domain.EventOrderCompleted, Code: "welcome", Template: template},
+			expectedErr: service.ErrInvalidTrigger,
+		},
+		{
+			name:        "Negative min order value",
+			trigger:     domain.Trigger{Name: "spend 50", Event: domain.EventOrderCompleted, MinOrderValue: -1, Template: template},
+			expectedErr: service.ErrInvalidTrigger,
+		},
+		{
+			name:        "Invalid template discount",
+			trigger:     domain.Trigger{Name: "spend 50", Event: domain.EventOrderCompleted, Template: domain.CouponTemplate{Discount: 101}},
+			expectedErr: service.ErrInvalidDiscount,
+		},
+		{
+			name:        "Invalid template prefix",
+			trigger:     domain.Trigger{Name: "spend 50", Event: domain.EventOrderCompleted, Template: domain.CouponTemplate{Prefix: "next", Discount: 5}},
+			expectedErr: service.ErrInvalidPrefix,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			triggers := mocks.NewTriggerRepository(t)
+			if tc.expectSave {
+				triggers.On("Save", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(trigger domain.Trigger) bool {
+					return trigger.ID != "" && trigger.Name == tc.trigger.Name
+				})).
+					Return(nil).
+					Once()
+			}
+
+			srv := service.New(repo, service.WithTriggers(triggers))
+
+			got, err := srv.CreateTrigger(context.Background(), tc.trigger)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.NotEmpty(t, got.ID)
+		})
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestHandleEvent in long mode.")
+	}
+
+	spend := domain.Trigger{ID: "t1", Name: "spend 50", Event: domain.EventOrderCompleted, MinOrderValue: 50,
+		Template: domain.CouponTemplate{Prefix: "NEXT", Discount: 5, MinBasketValue: 20}}
+	welcome := domain.Trigger{ID: "t2", Name: "welcome back", Event: domain.EventRedemption, Code: "welcome",
+		Template: domain.CouponTemplate{Discount: 10}}
+	customer := domain.Customer{ID: "c1"}
+	issuedCode := func(code string) bool { return strings.HasPrefix(code, "NEXT") && len(code) == 14 }
+
+	type testCase struct {
+		name        string
+		event       domain.OrderEvent
+		setupMocks  func(*mocks.Repository, *mocks.TriggerRepository)
+		want        []domain.Coupon
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "Qualifying order issues personal coupon",
+			event: domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o1", Customer: customer, Value: 60},
+			setupMocks: func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {
+				triggers.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Trigger{spend, welcome}, nil).
+					Once()
+				triggers.On("Reserve", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(issuance domain.Issuance) bool {
+					return issuance.TriggerID == "t1" && issuance.OrderID == "o1" && issuedCode(issuance.Code)
+				})).
+					Return(func(_ context.Context, issuance domain.Issuance) (domain.Issuance, bool, error) {
+						return issuance, true, nil
+					}).
+					Once()
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(coupon domain.Coupon) bool {
+					return issuedCode(coupon.Code) && coupon.Discount == 5 && coupon.MinBasketValue == 20 &&
+						coupon.Assignment.AssignedTo(customer)
+				})).
+					Return(nil).
+					Once()
+			},
+			want: []domain.Coupon{{Discount: 5, MinBasketValue: 20, Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}, Version: 1}},
+		},
+		{
+			name:  "Replayed order returns issued coupon",
+			event: domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o1", Customer: customer, Value: 60},
+			setupMocks: func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {
+				triggers.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Trigger{spend}, nil).
+					Once()
+				triggers.On("Reserve", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(domain.Issuance{TriggerID: "t1", OrderID: "o1", Code: "NEXTISSUED"}, false, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "NEXTISSUED").
+					Return(&domain.Coupon{Code: "NEXTISSUED", Discount: 5}, nil).
+					Once()
+			},
+			want: []domain.Coupon{{Code: "NEXTISSUED", Discount: 5}},
+		},
+		{
+			name:  "Replay during first delivery is retried",
+			event: domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o1", Customer: customer, Value: 60},
+			setupMocks: func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {
+				triggers.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Trigger{spend}, nil).
+					Once()
+				triggers.On("Reserve", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(domain.Issuance{TriggerID: "t1", OrderID: "o1", Code: "NEXTPENDING"}, false, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "NEXTPENDING").
+					Return(nil, repository.ErrNotFound).
+					Once()
+			},
+			expectedErr: service.ErrIssuancePending,
+		},
+		{
+			name:  "Order below minimum and other code redeemed",
+			event: domain.OrderEvent{Type: domain.EventRedemption, OrderID: "o2", Customer: customer, Value: 40, Code: "other"},
+			setupMocks: func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {
+				triggers.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Trigger{spend, welcome}, nil).
+					Once()
+			},
+			want: []domain.Coupon{},
+		},
+		{
+			name:  "Failed save releases issuance",
+			event: domain.OrderEvent{Type: domain.EventRedemption, OrderID: "o3", Customer: customer, Value: 40, Code: "welcome"},
+			setupMocks: func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {
+				triggers.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Trigger{welcome}, nil).
+					Once()
+				triggers.On("Reserve", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(func(_ context.Context, issuance domain.Issuance) (domain.Issuance, bool, error) {
+						return issuance, true, nil
+					}).
+					Once()
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(errors.New("fatal error")).
+					Once()
+				triggers.On("Release", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(issuance domain.Issuance) bool {
+					return issuance.TriggerID == "t2" && issuance.OrderID == "o3"
+				})).
+					Return(nil).
+					Once()
+			},
+			expectedErr: errors.New("fatal error"),
+		},
+		{
+			name:  "Taken code releases issuance and is drawn again",
+			event: domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o4", Customer: customer, Value: 60},
+			setupMocks: func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {
+				triggers.On("FindAll", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Trigger{spend}, nil).
+					Once()
+				triggers.On("Reserve", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(func(_ context.Context, issuance domain.Issuance) (domain.Issuance, bool, error) {
+						return issuance, true, nil
+					}).
+					Twice()
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(repository.ErrAlreadyExists).
+					Once()
+				triggers.On("Release", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.MatchedBy(func(issuance domain.Issuance) bool {
+					return issuance.TriggerID == "t1" && issuance.OrderID == "o4" && issuedCode(issuance.Code)
+				})).
+					Return(nil).
+					Once()
+				repo.On("Insert", mock.MatchedBy(func(ctx context.Context) bool { return true }), mock.Anything).
+					Return(nil).
+					Once()
+			},
+			want: []domain.Coupon{{Discount: 5, MinBasketValue: 20, Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}, Version: 1}},
+		},
+		{
+			name:        "Missing order ID",
+			event:       domain.OrderEvent{Type: domain.EventOrderCompleted, Customer: customer, Value: 60},
+			setupMocks:  func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {},
+			expectedErr: service.ErrInvalidEvent,
+		},
+		{
+			name:        "Missing customer",
+			event:       domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o1", Value: 60},
+			setupMocks:  func(repo *mocks.Repository, triggers *mocks.TriggerRepository) {},
+			expectedErr: service.ErrInvalidEvent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			triggers := mocks.NewTriggerRepository(t)
+			tc.setupMocks(repo, triggers)
+
+			srv := service.New(repo, service.WithTriggers(triggers))
+
+			got, err := srv.HandleEvent(context.Background(), tc.event)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			require.Len(t, got, len(tc.want))
+			for i := range got {
+				if tc.want[i].Code == "" {
+					got[i].ID, got[i].Code = "", ""
+				}
+				assert.Equal(t, tc.want[i], got[i])
+			}
+		})
+	}
+}
+
+func TestHandleEventIdempotent(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestHandleEventIdempotent in long mode.")
+	}
+
+	ctx := context.Background()
+	srv := service.New(memory.New(), service.WithTriggers(memory.NewTriggers()))
+
+	_, err := srv.CreateTrigger(ctx, domain.Trigger{
+		Name:          "spend 50",
+		Event:         domain.EventOrderCompleted,
+		MinOrderValue: 50,
+		Template:      domain.CouponTemplate{Discount: 5},
+	})
+	require.NoError(t, err)
+
+	event := domain.OrderEvent{Type: domain.EventOrderCompleted, OrderID: "o1", Customer: domain.Customer{ID: "c1"}, Value: 50}
+
+	first, err := srv.HandleEvent(ctx, event)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	second, err := srv.HandleEvent(ctx, event)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	assigned, err := srv.GetCustomerCoupons(ctx, domain.Customer{ID: "c1"})
+	require.NoError(t, err)
+	assert.Equal(t, first, assigned)
+}
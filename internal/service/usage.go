@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+)
+
+var (
+	ErrRedemptionsDisabled = errors.New("redemptions not configured")
+	ErrInvalidUsageLimit   = errors.New("invalid usage limit")
+	ErrUsageLimitReached   = errors.New("coupon usage limit reached")
+	ErrCustomerRequired    = errors.New("coupon requires a customer")
+)
+
+// WithRedemptions enables redeeming coupons, which records the redemption
+// history that usage limits are enforced with.
+func WithRedemptions(redemptions RedemptionRepository) Option {
+	return func(s *Service) {
+		s.redemptions = redemptions
+	}
+}
+
+// RedeemCoupon applies the coupon like ApplyCoupon and records the
+// redemption for the customer. The usage limits of the coupon are checked
+// again when recording, so concurrent redemptions cannot exceed them.
+func (s Service) RedeemCoupon(ctx context.Context, basket domain.Basket, code, orderID string) (*domain.Basket, error) {
+	if s.redemptions == nil {
+		return nil, ErrRedemptionsDisabled
+	}
+
+	coupon, discount, err := s.apply(ctx, basket, code)
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := s.usageWindows(coupon, basket)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, err := s.redemptions.Record(ctx, domain.Redemption{
+		Code:       coupon.Code,
+		CustomerID: basket.Customer.ID,
+		OrderID:    orderID,
+		RedeemedAt: s.now(),
+	}, windows)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrUsageLimitReached
+	}
+
+	return &domain.Basket{
+		Value:           basket.Value - discount,
+		AppliedDiscount: discount,
+	}, nil
+}
+
+func validUsageLimits(limits []domain.UsageLimit) bool {
+	for _, limit := range limits {
+		if limit.Count <= 0 {
+			return false
+		}
+		if limit.Period != domain.UsagePerDay && limit.Period != domain.UsagePerWeek {
+			return false
+		}
+	}
+	return true
+}
+
+// checkUsage counts the redemptions of the customer within every usage
+// limit of the coupon.
+func (s Service) checkUsage(ctx context.Context, coupon *domain.Coupon, basket domain.Basket) error {
+	windows, err := s.usageWindows(coupon, basket)
+	if err != nil {
+		return err
+	}
+
+	for _, window := range windows {
+		count, err := s.redemptions.Count(ctx, basket.Customer.ID, coupon.Code, window.Since)
+		if err != nil {
+			return err
+		}
+		if count >= window.Max {
+			return ErrUsageLimitReached
+		}
+	}
+
+	return nil
+}
+
+// usageWindows resolves the usage limits of the coupon at the current time
+// in the time zone of the basket.
+func (s Service) usageWindows(coupon *domain.Coupon, basket domain.Basket) ([]domain.UsageWindow, error) {
+	if len(coupon.UsageLimits) == 0 {
+		return nil, nil
+	}
+
+	if s.redemptions == nil {
+		return nil, ErrRedemptionsDisabled
+	}
+
+	if basket.Customer.ID == "" {
+		return nil, ErrCustomerRequired
+	}
+
+	location, err := basketLocation(basket.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().In(location)
+	windows := make([]domain.UsageWindow, 0, len(coupon.UsageLimits))
+	for _, limit := range coupon.UsageLimits {
+		windows = append(windows, domain.UsageWindow{Since: limit.Since(now), Max: limit.Count})
+	}
+
+	return windows, nil
+}
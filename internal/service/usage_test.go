@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestRedeemCouponUsageLimits(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRedeemCouponUsageLimits in long mode.")
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	type redemption struct {
+		at          time.Time
+		customerID  string
+		expectedErr error
+	}
+
+	type testCase struct {
+		name        string
+		limits      []domain.UsageLimit
+		redemptions []redemption
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Once per calendar day",
+			limits: []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay}},
+			redemptions: []redemption{
+				{at: time.Date(2024, 3, 4, 10, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 4, 23, 59, 0, 0, berlin), customerID: "c1", expectedErr: service.ErrUsageLimitReached},
+				{at: time.Date(2024, 3, 4, 23, 59, 0, 0, berlin), customerID: "c2"},
+				{at: time.Date(2024, 3, 5, 0, 0, 0, 0, berlin), customerID: "c1"},
+			},
+		},
+		{
+			name:   "Once per rolling day",
+			limits: []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay, Rolling: true}},
+			redemptions: []redemption{
+				{at: time.Date(2024, 3, 4, 23, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 5, 0, 30, 0, 0, berlin), customerID: "c1", expectedErr: service.ErrUsageLimitReached},
+				{at: time.Date(2024, 3, 5, 22, 59, 0, 0, berlin), customerID: "c1", expectedErr: service.ErrUsageLimitReached},
+				{at: time.Date(2024, 3, 5, 23, 1, 0, 0, berlin), customerID: "c1"},
+			},
+		},
+		{
+			name:   "Three times per calendar week",
+			limits: []domain.UsageLimit{{Count: 3, Period: domain.UsagePerWeek}},
+			redemptions: []redemption{
+				{at: time.Date(2024, 3, 4, 0, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 6, 12, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 8, 12, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 10, 23, 59, 0, 0, berlin), customerID: "c1", expectedErr: service.ErrUsageLimitReached},
+				{at: time.Date(2024, 3, 11, 0, 0, 0, 0, berlin), customerID: "c1"},
+			},
+		},
+		{
+			name: "Calendar week across the switch to summer time",
+			limits: []domain.UsageLimit{
+				{Count: 1, Period: domain.UsagePerDay},
+				{Count: 2, Period: domain.UsagePerWeek},
+			},
+			redemptions: []redemption{
+				{at: time.Date(2024, 3, 25, 0, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 25, 8, 0, 0, 0, berlin), customerID: "c1", expectedErr: service.ErrUsageLimitReached},
+				{at: time.Date(2024, 3, 31, 8, 0, 0, 0, berlin), customerID: "c1"},
+				{at: time.Date(2024, 3, 31, 23, 59, 0, 0, berlin), customerID: "c1", expectedErr: service.ErrUsageLimitReached},
+				{at: time.Date(2024, 4, 1, 0, 0, 0, 0, berlin), customerID: "c1"},
+			},
+		},
+		{
+			name:   "Limited coupon without customer",
+			limits: []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay}},
+			redemptions: []redemption{
+				{at: time.Date(2024, 3, 4, 10, 0, 0, 0, berlin), expectedErr: service.ErrCustomerRequired},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Time{}
+
+			srv := service.New(memory.New(),
+				service.WithRedemptions(memory.NewRedemptions()),
+				service.WithClock(func() time.Time { return now }))
+
+			err := srv.CreateCoupon(ctx, domain.Coupon{Code: "daily", Discount: 10, UsageLimits: tc.limits})
+			require.NoError(t, err)
+
+			for i, r := range tc.redemptions {
+				now = r.at.UTC()
+				basket := domain.Basket{Value: 100, Customer: domain.Customer{ID: r.customerID}, TimeZone: "Europe/Berlin"}
+
+				_, applyErr := srv.ApplyCoupon(ctx, basket, "daily")
+				_, err := srv.RedeemCoupon(ctx, basket, "daily", "")
+				if r.expectedErr != nil {
+					assert.ErrorIs(t, applyErr, r.expectedErr, "redemption %d: expected apply error %v, got: %v", i, r.expectedErr, applyErr)
+					assert.ErrorIs(t, err, r.expectedErr, "redemption %d: expected error %v, got: %v", i, r.expectedErr, err)
+					continue
+				}
+
+				assert.NoError(t, applyErr, "redemption %d: expected apply error nil, got: %v", i, applyErr)
+				assert.NoError(t, err, "redemption %d: expected error nil, got: %v", i, err)
+			}
+		})
+	}
+}
+
+func TestUsageLimitsRequireTimeZone(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestUsageLimitsRequireTimeZone in long mode.")
+	}
+
+	ctx := context.Background()
+	srv := service.New(memory.New(), service.WithRedemptions(memory.NewRedemptions()))
+
+	err := srv.CreateCoupon(ctx, domain.Coupon{Code: "daily", Discount: 10,
+		UsageLimits: []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay}}})
+	require.NoError(t, err)
+
+	for _, timeZone := range []string{"", "Local"} {
+		basket := domain.Basket{Value: 100, Customer: domain.Customer{ID: "c1"}, TimeZone: timeZone}
+
+		_, err := srv.RedeemCoupon(ctx, basket, "daily", "")
+		assert.ErrorIs(t, err, service.ErrInvalidTimeZone, "expected time zone %q to be rejected", timeZone)
+	}
+}
+
+func TestCreateCouponUsageLimits(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestCreateCouponUsageLimits in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		limits      []domain.UsageLimit
+		opts        []service.Option
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:        "Zero count",
+			limits:      []domain.UsageLimit{{Count: 0, Period: domain.UsagePerDay}},
+			opts:        []service.Option{service.WithRedemptions(memory.NewRedemptions())},
+			expectedErr: service.ErrInvalidUsageLimit,
+		},
+		{
+			name:        "Unknown period",
+			limits:      []domain.UsageLimit{{Count: 1, Period: "month"}},
+			opts:        []service.Option{service.WithRedemptions(memory.NewRedemptions())},
+			expectedErr: service.ErrInvalidUsageLimit,
+		},
+		{
+			name:        "Redemptions not configured",
+			limits:      []domain.UsageLimit{{Count: 1, Period: domain.UsagePerDay}},
+			expectedErr: service.ErrRedemptionsDisabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := service.New(mocks.NewRepository(t), tc.opts...)
+
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "daily", Discount: 10, UsageLimits: tc.limits})
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
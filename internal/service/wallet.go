@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
+)
+
+var (
+	ErrWalletDisabled = errors.New("wallet not configured")
+	ErrNotActivated   = errors.New("coupon not activated")
+)
+
+// WithWallet enables the customer wallet, which coupons requiring
+// activation are checked against.
+func WithWallet(wallet WalletRepository) Option {
+	return func(s *Service) {
+		s.wallet = wallet
+	}
+}
+
+// GetWallet lists the coupons assigned to the customer and the unassigned
+// ones requiring activation, which they can activate, together with any
+// other coupon they activated.
+func (s Service) GetWallet(ctx context.Context, customer domain.Customer) ([]domain.WalletEntry, error) {
+	if customer.ID == "" {
+		return nil, ErrInvalidCustomer
+	}
+
+	if s.wallet == nil {
+		return nil, ErrWalletDisabled
+	}
+
+	assigned, err := s.repo.FindByCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	activatable, err := s.repo.FindActivatable(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	activeCodes, err := s.wallet.FindActive(ctx, customer.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[string]struct{}, len(activeCodes))
+	for _, code := range activeCodes {
+		active[code] = struct{}{}
+	}
+
+	entries := make([]domain.WalletEntry, 0, len(assigned)+len(activatable)+len(activeCodes))
+	for _, coupon := range slices.Concat(assigned, activatable) {
+		_, activated := active[coupon.Code]
+		delete(active, coupon.Code)
+		entries = append(entries, domain.WalletEntry{Coupon: coupon, Activated: activated})
+	}
+
+	for _, code := range activeCodes {
+		if _, ok := active[code]; !ok {
+			continue
+		}
+
+		coupon, err := s.repo.FindByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		entries = append(entries, domain.WalletEntry{Coupon: *coupon, Activated: true})
+	}
+
+	return entries, nil
+}
+
+func (s Service) ActivateCoupon(ctx context.Context, customer domain.Customer, code string) error {
+	if customer.ID == "" {
+		return ErrInvalidCustomer
+	}
+
+	if s.wallet == nil {
+		return ErrWalletDisabled
+	}
+
+	code = s.policy.Normalize(code)
+	if code == "" || token.IsToken(code) {
+		return ErrInvalidCode
+	}
+
+	coupon, err := s.findCoupon(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if !coupon.Assignment.Allows(customer) {
+		return ErrNotAssigned
+	}
+
+	return s.wallet.Activate(ctx, customer.ID, coupon.Code)
+}
+
+func (s Service) DeactivateCoupon(ctx context.Context, customer domain.Customer, code string) error {
+	if customer.ID == "" {
+		return ErrInvalidCustomer
+	}
+
+	if s.wallet == nil {
+		return ErrWalletDisabled
+	}
+
+	code = s.policy.Normalize(code)
+	if code == "" {
+		return ErrInvalidCode
+	}
+
+	return s.wallet.Deactivate(ctx, customer.ID, code)
+}
+
+func (s Service) checkActivated(ctx context.Context, customer domain.Customer, coupon *domain.Coupon) error {
+	if !coupon.RequiresActivation {
+		return nil
+	}
+
+	if s.wallet == nil || customer.ID == "" {
+		return ErrNotActivated
+	}
+
+	active, err := s.wallet.IsActive(ctx, customer.ID, coupon.Code)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrNotActivated
+	}
+
+	return nil
+}
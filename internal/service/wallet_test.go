@@ -0,0 +1,279 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service/internal/mocks"
+)
+
+func TestGetWallet(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestGetWallet in long mode.")
+	}
+
+	customer := domain.Customer{ID: "c1", Segments: []string{"gold"}}
+	personal := domain.Coupon{ID: "id1", Code: "personal", RequiresActivation: true,
+		Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}}
+	segment := domain.Coupon{ID: "id2", Code: "segment", RequiresActivation: true,
+		Assignment: domain.Assignment{Segments: []string{"gold"}}}
+	public := domain.Coupon{ID: "id3", Code: "public", RequiresActivation: true}
+	open := domain.Coupon{ID: "id4", Code: "open", RequiresActivation: true}
+	other := domain.Coupon{ID: "id5", Code: "other"}
+
+	type testCase struct {
+		name        string
+		customer    domain.Customer
+		setupMocks  func(*mocks.Repository, *mocks.WalletRepository)
+		want        []domain.WalletEntry
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Assigned and activated coupons",
+			customer: customer,
+			setupMocks: func(repo *mocks.Repository, wallet *mocks.WalletRepository) {
+				repo.On("FindByCustomer", mock.MatchedBy(func(ctx context.Context) bool { return true }), customer).
+					Return([]domain.Coupon{personal, segment}, nil).
+					Once()
+				repo.On("FindActivatable", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Coupon{open, public}, nil).
+					Once()
+				wallet.On("FindActive", mock.MatchedBy(func(ctx context.Context) bool { return true }), customer.ID).
+					Return([]string{"deleted", "other", "public", "segment"}, nil).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "deleted").
+					Return(nil, repository.ErrNotFound).
+					Once()
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "other").
+					Return(&other, nil).
+					Once()
+			},
+			want: []domain.WalletEntry{
+				{Coupon: personal, Activated: false},
+				{Coupon: segment, Activated: true},
+				{Coupon: open, Activated: false},
+				{Coupon: public, Activated: true},
+				{Coupon: other, Activated: true},
+			},
+		},
+		{
+			name:        "Missing customer ID",
+			customer:    domain.Customer{},
+			setupMocks:  func(repo *mocks.Repository, wallet *mocks.WalletRepository) {},
+			expectedErr: service.ErrInvalidCustomer,
+		},
+		{
+			name:     "Wallet error",
+			customer: customer,
+			setupMocks: func(repo *mocks.Repository, wallet *mocks.WalletRepository) {
+				repo.On("FindByCustomer", mock.MatchedBy(func(ctx context.Context) bool { return true }), customer).
+					Return([]domain.Coupon{}, nil).
+					Once()
+				repo.On("FindActivatable", mock.MatchedBy(func(ctx context.Context) bool { return true })).
+					Return([]domain.Coupon{}, nil).
+					Once()
+				wallet.On("FindActive", mock.MatchedBy(func(ctx context.Context) bool { return true }), customer.ID).
+					Return(nil, errors.New("fatal error")).
+					Once()
+			},
+			expectedErr: errors.New("fatal error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			wallet := mocks.NewWalletRepository(t)
+			tc.setupMocks(repo, wallet)
+
+			srv := service.New(repo, service.WithWallet(wallet))
+
+			got, err := srv.GetWallet(context.Background(), tc.customer)
+			if tc.expectedErr != nil {
+				assert.EqualError(t, err, tc.expectedErr.Error())
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestActivateCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestActivateCoupon in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		customer    domain.Customer
+		code        string
+		setupMocks  func(*mocks.Repository, *mocks.WalletRepository)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Successful activation",
+			customer: domain.Customer{ID: "c1"},
+			code:     "personal",
+			setupMocks: func(repo *mocks.Repository, wallet *mocks.WalletRepository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "personal").
+					Return(&domain.Coupon{Code: "personal", Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}}, nil).
+					Once()
+				wallet.On("Activate", mock.MatchedBy(func(ctx context.Context) bool { return true }), "c1", "personal").
+					Return(nil).
+					Once()
+			},
+		},
+		{
+			name:     "Coupon assigned to another customer",
+			customer: domain.Customer{ID: "c2"},
+			code:     "personal",
+			setupMocks: func(repo *mocks.Repository, wallet *mocks.WalletRepository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "personal").
+					Return(&domain.Coupon{Code: "personal", Assignment: domain.Assignment{CustomerIDs: []string{"c1"}}}, nil).
+					Once()
+			},
+			expectedErr: service.ErrNotAssigned,
+		},
+		{
+			name:     "Unknown coupon",
+			customer: domain.Customer{ID: "c1"},
+			code:     "unknown",
+			setupMocks: func(repo *mocks.Repository, wallet *mocks.WalletRepository) {
+				repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), "unknown").
+					Return(nil, repository.ErrNotFound).
+					Once()
+			},
+			expectedErr: service.ErrNotFound,
+		},
+		{
+			name:        "Empty code",
+			customer:    domain.Customer{ID: "c1"},
+			code:        "",
+			setupMocks:  func(repo *mocks.Repository, wallet *mocks.WalletRepository) {},
+			expectedErr: service.ErrInvalidCode,
+		},
+		{
+			name:        "Missing customer ID",
+			customer:    domain.Customer{},
+			code:        "personal",
+			setupMocks:  func(repo *mocks.Repository, wallet *mocks.WalletRepository) {},
+			expectedErr: service.ErrInvalidCustomer,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			wallet := mocks.NewWalletRepository(t)
+			tc.setupMocks(repo, wallet)
+
+			srv := service.New(repo, service.WithWallet(wallet))
+
+			err := srv.ActivateCoupon(context.Background(), tc.customer, tc.code)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
+
+func TestDeactivateCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDeactivateCoupon in long mode.")
+	}
+
+	repo := mocks.NewRepository(t)
+	wallet := mocks.NewWalletRepository(t)
+	wallet.On("Deactivate", mock.MatchedBy(func(ctx context.Context) bool { return true }), "c1", "personal").
+		Return(nil).
+		Once()
+
+	srv := service.New(repo, service.WithWallet(wallet))
+
+	err := srv.DeactivateCoupon(context.Background(), domain.Customer{ID: "c1"}, "personal")
+	assert.NoError(t, err, "expected error nil, got: %v", err)
+
+	err = service.New(repo).DeactivateCoupon(context.Background(), domain.Customer{ID: "c1"}, "personal")
+	assert.ErrorIs(t, err, service.ErrWalletDisabled)
+}
+
+func TestApplyActivatedCoupon(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestApplyActivatedCoupon in long mode.")
+	}
+
+	coupon := &domain.Coupon{ID: "id1", Code: "clip10", Discount: 10, RequiresActivation: true}
+
+	type testCase struct {
+		name        string
+		customer    domain.Customer
+		setupMocks  func(*mocks.WalletRepository)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "Activated coupon",
+			customer: domain.Customer{ID: "c1"},
+			setupMocks: func(wallet *mocks.WalletRepository) {
+				wallet.On("IsActive", mock.MatchedBy(func(ctx context.Context) bool { return true }), "c1", coupon.Code).
+					Return(true, nil).
+					Once()
+			},
+		},
+		{
+			name:     "Coupon not activated",
+			customer: domain.Customer{ID: "c1"},
+			setupMocks: func(wallet *mocks.WalletRepository) {
+				wallet.On("IsActive", mock.MatchedBy(func(ctx context.Context) bool { return true }), "c1", coupon.Code).
+					Return(false, nil).
+					Once()
+			},
+			expectedErr: service.ErrNotActivated,
+		},
+		{
+			name:        "Anonymous basket",
+			customer:    domain.Customer{},
+			setupMocks:  func(wallet *mocks.WalletRepository) {},
+			expectedErr: service.ErrNotActivated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindByCode", mock.MatchedBy(func(ctx context.Context) bool { return true }), coupon.Code).
+				Return(coupon, nil).
+				Once()
+			wallet := mocks.NewWalletRepository(t)
+			tc.setupMocks(wallet)
+
+			srv := service.New(repo, service.WithWallet(wallet))
+
+			_, err := srv.ApplyCoupon(context.Background(), domain.Basket{Value: 100, Customer: tc.customer}, coupon.Code)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Package discount defines how coupons calculate their discount. Custom
+// discount types implement Strategy and are registered in a Registry that
+// is passed to the coupon service, or with Register in the default registry
+// the coupon service binary uses.
+package discount
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const (
+	Fixed   = "fixed"
+	Percent = "percent"
+)
+
+var (
+	// ErrInvalidDiscount is returned by Validate for a discount out of the
+	// range of the strategy.
+	ErrInvalidDiscount = errors.New("invalid discount")
+	// ErrInvalidParams is returned by Validate for unusable parameters.
+	ErrInvalidParams = errors.New("invalid discount parameters")
+	// ErrNotApplicable is returned by Calculate when the coupon grants no
+	// discount for the basket.
+	ErrNotApplicable = errors.New("discount not applicable")
+)
+
+// Strategy calculates the discount of a coupon. Implementations must be
+// safe for concurrent use.
+type Strategy interface {
+	// Validate checks the coupon when it is created.
+	Validate(Coupon) error
+	// Calculate returns the discount the coupon grants for the basket. The
+	// service rejects discounts above the basket value.
+	Calculate(Coupon, Basket) (int, error)
+}
+
+// Coupon is the part of a coupon a strategy sees. Params are opaque to the
+// service and stored as given.
+type Coupon struct {
+	Discount       int
+	MinBasketValue int
+	Params         json.RawMessage
+}
+
+type Basket struct {
+	Value      int
+	CustomerID string
+	Lines      []Line
+}
+
+type Line struct {
+	SKU       string
+	Category  string
+	Quantity  int
+	UnitPrice int
+	Deposit   bool
+}
+
+// FixedAmount takes Discount, at most 100, off the basket value. It is the
+// default for coupons without a strategy.
+type FixedAmount struct{}
+
+func (FixedAmount) Validate(coupon Coupon) error {
+	if coupon.Discount < 0 || coupon.Discount > 100 {
+		return ErrInvalidDiscount
+	}
+	if len(coupon.Params) > 0 {
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+func (FixedAmount) Calculate(coupon Coupon, _ Basket) (int, error) {
+	return coupon.Discount, nil
+}
+
+// Percentage takes Discount percent off the basket value, rounded down and
+// capped at the optional "max" parameter.
+type Percentage struct{}
+
+type percentageParams struct {
+	Max int `json:"max"`
+}
+
+func (Percentage) Validate(coupon Coupon) error {
+	if coupon.Discount < 0 || coupon.Discount > 100 {
+		return ErrInvalidDiscount
+	}
+	_, err := parsePercentageParams(coupon.Params)
+	return err
+}
+
+func (Percentage) Calculate(coupon Coupon, basket Basket) (int, error) {
+	params, err := parsePercentageParams(coupon.Params)
+	if err != nil {
+		return 0, err
+	}
+
+	amount := basket.Value * coupon.Discount / 100
+	if params.Max > 0 && amount > params.Max {
+		amount = params.Max
+	}
+	return amount, nil
+}
+
+func parsePercentageParams(raw json.RawMessage) (percentageParams, error) {
+	var params percentageParams
+	if len(raw) == 0 {
+		return params, nil
+	}
+
+	if err := json.Unmarshal(raw, &params); err != nil || params.Max < 0 {
+		return params, ErrInvalidParams
+	}
+	return params, nil
+}
@@ -0,0 +1,104 @@
+package discount_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/discount"
+)
+
+func TestPercentage(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPercentage in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		coupon      discount.Coupon
+		basket      discount.Basket
+		want        int
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "Rounded down",
+			coupon: discount.Coupon{Discount: 15},
+			basket: discount.Basket{Value: 999},
+			want:   149,
+		},
+		{
+			name:   "Capped",
+			coupon: discount.Coupon{Discount: 50, Params: json.RawMessage(`{"max":300}`)},
+			basket: discount.Basket{Value: 1000},
+			want:   300,
+		},
+		{
+			name:   "Below cap",
+			coupon: discount.Coupon{Discount: 10, Params: json.RawMessage(`{"max":300}`)},
+			basket: discount.Basket{Value: 1000},
+			want:   100,
+		},
+		{
+			name:        "Negative cap",
+			coupon:      discount.Coupon{Discount: 10, Params: json.RawMessage(`{"max":-1}`)},
+			basket:      discount.Basket{Value: 1000},
+			expectedErr: discount.ErrInvalidParams,
+		},
+		{
+			name:        "Malformed parameters",
+			coupon:      discount.Coupon{Discount: 10, Params: json.RawMessage(`[300]`)},
+			basket:      discount.Basket{Value: 1000},
+			expectedErr: discount.ErrInvalidParams,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			strategy := discount.Percentage{}
+
+			got, err := strategy.Calculate(tc.coupon, tc.basket)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				assert.ErrorIs(t, strategy.Validate(tc.coupon), tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err, "expected error nil, got: %v", err)
+			assert.NoError(t, strategy.Validate(tc.coupon))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPercentageRange(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestPercentageRange in long mode.")
+	}
+
+	strategy := discount.Percentage{}
+	assert.NoError(t, strategy.Validate(discount.Coupon{Discount: 100}))
+	assert.ErrorIs(t, strategy.Validate(discount.Coupon{Discount: 101}), discount.ErrInvalidDiscount)
+	assert.ErrorIs(t, strategy.Validate(discount.Coupon{Discount: -1}), discount.ErrInvalidDiscount)
+}
+
+func TestFixedAmount(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestFixedAmount in long mode.")
+	}
+
+	strategy := discount.FixedAmount{}
+
+	got, err := strategy.Calculate(discount.Coupon{Discount: 10}, discount.Basket{Value: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, got)
+
+	err = strategy.Validate(discount.Coupon{Discount: 10, Params: json.RawMessage(`{"max":5}`)})
+	assert.ErrorIs(t, err, discount.ErrInvalidParams)
+
+	err = strategy.Validate(discount.Coupon{Discount: -1})
+	assert.ErrorIs(t, err, discount.ErrInvalidDiscount)
+}
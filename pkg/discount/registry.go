@@ -0,0 +1,70 @@
+package discount
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidName = errors.New("invalid strategy name")
+	ErrDuplicate   = errors.New("strategy already registered")
+)
+
+// Registry maps strategy names to strategies. A new registry holds the
+// built-in fixed and percent strategies.
+type Registry struct {
+	mu         *sync.RWMutex
+	strategies map[string]Strategy
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		mu: &sync.RWMutex{},
+		strategies: map[string]Strategy{
+			Fixed:   FixedAmount{},
+			Percent: Percentage{},
+		},
+	}
+}
+
+// Register adds a strategy under name. Names are case sensitive and cannot
+// be registered twice.
+func (r *Registry) Register(name string, strategy Strategy) error {
+	if name == "" || strings.TrimSpace(name) != name || strategy == nil {
+		return ErrInvalidName
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.strategies[name]; ok {
+		return ErrDuplicate
+	}
+	r.strategies[name] = strategy
+	return nil
+}
+
+// defaultRegistry is the registry the coupon service binary uses.
+var defaultRegistry = NewRegistry()
+
+// Default returns the registry the coupon service binary looks strategies up
+// in, holding the built-in strategies and the ones added with Register.
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// Register adds a strategy to the default registry. Packages providing
+// strategies call it from an init function, so that importing them into the
+// coupon service binary is enough to make their strategies available.
+func Register(name string, strategy Strategy) error {
+	return defaultRegistry.Register(name, strategy)
+}
+
+func (r *Registry) Lookup(name string) (Strategy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	strategy, ok := r.strategies[name]
+	return strategy, ok
+}
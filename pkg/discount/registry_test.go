@@ -0,0 +1,61 @@
+package discount_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/discount"
+)
+
+type freeShipping struct{}
+
+func (freeShipping) Validate(discount.Coupon) error { return nil }
+
+func (freeShipping) Calculate(discount.Coupon, discount.Basket) (int, error) { return 495, nil }
+
+func TestDefaultRegistry(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestDefaultRegistry in long mode.")
+	}
+
+	_, ok := discount.Default().Lookup(discount.Percent)
+	assert.True(t, ok, "expected built-in strategy %q", discount.Percent)
+
+	require.NoError(t, discount.Register("defaultFreeShipping", freeShipping{}))
+	strategy, ok := discount.Default().Lookup("defaultFreeShipping")
+	require.True(t, ok)
+	assert.Equal(t, freeShipping{}, strategy)
+
+	assert.ErrorIs(t, discount.Register("defaultFreeShipping", freeShipping{}), discount.ErrDuplicate)
+}
+
+func TestRegistry(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestRegistry in long mode.")
+	}
+
+	registry := discount.NewRegistry()
+
+	for _, name := range []string{discount.Fixed, discount.Percent} {
+		_, ok := registry.Lookup(name)
+		assert.True(t, ok, "expected built-in strategy %q", name)
+	}
+
+	require.NoError(t, registry.Register("freeShipping", freeShipping{}))
+
+	strategy, ok := registry.Lookup("freeShipping")
+	require.True(t, ok)
+	assert.Equal(t, freeShipping{}, strategy)
+
+	_, ok = registry.Lookup("FreeShipping")
+	assert.False(t, ok)
+
+	assert.ErrorIs(t, registry.Register("freeShipping", freeShipping{}), discount.ErrDuplicate)
+	assert.ErrorIs(t, registry.Register(discount.Fixed, freeShipping{}), discount.ErrDuplicate)
+	assert.ErrorIs(t, registry.Register("", freeShipping{}), discount.ErrInvalidName)
+	assert.ErrorIs(t, registry.Register(" bogo", freeShipping{}), discount.ErrInvalidName)
+	assert.ErrorIs(t, registry.Register("bogo", nil), discount.ErrInvalidName)
+}
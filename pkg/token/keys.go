@@ -0,0 +1,161 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNoPrivateKey = errors.New("key cannot sign")
+	ErrInvalidKey   = errors.New("invalid key")
+)
+
+// Key verifies signatures made with the key identified by ID.
+type Key interface {
+	ID() string
+	Verify(message []byte, signature []byte) bool
+}
+
+// Signer is a Key that can also sign new tokens.
+type Signer interface {
+	Key
+	Sign(message []byte) ([]byte, error)
+}
+
+type HMACKey struct {
+	id     string
+	secret []byte
+}
+
+func NewHMACKey(id string, secret []byte) (*HMACKey, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	return &HMACKey{id: id, secret: secret}, nil
+}
+
+func (k *HMACKey) ID() string {
+	return k.id
+}
+
+func (k *HMACKey) Sign(message []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+func (k *HMACKey) Verify(message []byte, signature []byte) bool {
+	expected, _ := k.Sign(message)
+	return hmac.Equal(expected, signature)
+}
+
+// Ed25519Key holds a public key and, on the issuing side, the matching
+// private key. Tills only need the public part.
+type Ed25519Key struct {
+	id      string
+	public  ed25519.PublicKey
+	private ed25519.PrivateKey
+}
+
+func NewEd25519Key(id string, private ed25519.PrivateKey) (*Ed25519Key, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	return &Ed25519Key{
+		id:      id,
+		public:  private.Public().(ed25519.PublicKey),
+		private: private,
+	}, nil
+}
+
+func NewEd25519PublicKey(id string, public ed25519.PublicKey) (*Ed25519Key, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	return &Ed25519Key{id: id, public: public}, nil
+}
+
+func (k *Ed25519Key) ID() string {
+	return k.id
+}
+
+func (k *Ed25519Key) PublicKey() ed25519.PublicKey {
+	return k.public
+}
+
+func (k *Ed25519Key) Sign(message []byte) ([]byte, error) {
+	if k.private == nil {
+		return nil, ErrNoPrivateKey
+	}
+	return ed25519.Sign(k.private, message), nil
+}
+
+func (k *Ed25519Key) Verify(message []byte, signature []byte) bool {
+	return ed25519.Verify(k.public, message, signature)
+}
+
+// validateID rejects key IDs that cannot be told apart from the separators
+// of tokens and key specs.
+func validateID(id string) error {
+	if id == "" || strings.ContainsAny(id, ".:,") {
+		return fmt.Errorf("%w: %q: ID must not be empty or contain '.', ':' or ','", ErrInvalidKey, id)
+	}
+	return nil
+}
+
+// ParseKeys parses a comma separated list of keys in the form
+// id:algorithm:base64. The algorithm is one of hs256 (shared secret),
+// ed25519 (32 byte private seed) or ed25519-pub (public key only).
+func ParseKeys(spec string) ([]Key, error) {
+	var keys []Key
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKey, entry)
+		}
+
+		id, algorithm := parts[0], parts[1]
+		material, err := base64.StdEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidKey, id, err)
+		}
+
+		var key Key
+		switch algorithm {
+		case "hs256":
+			if len(material) < 32 {
+				return nil, fmt.Errorf("%w: %s: secret shorter than 32 bytes", ErrInvalidKey, id)
+			}
+			key, err = NewHMACKey(id, material)
+		case "ed25519":
+			if len(material) != ed25519.SeedSize {
+				return nil, fmt.Errorf("%w: %s: seed must be %d bytes", ErrInvalidKey, id, ed25519.SeedSize)
+			}
+			key, err = NewEd25519Key(id, ed25519.NewKeyFromSeed(material))
+		case "ed25519-pub":
+			if len(material) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("%w: %s: public key must be %d bytes", ErrInvalidKey, id, ed25519.PublicKeySize)
+			}
+			key, err = NewEd25519PublicKey(id, material)
+		default:
+			return nil, fmt.Errorf("%w: %s: unknown algorithm %q", ErrInvalidKey, id, algorithm)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
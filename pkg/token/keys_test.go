@@ -0,0 +1,106 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
+)
+
+func TestParseKeys(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestParseKeys in long mode.")
+	}
+
+	type testCase struct {
+		name        string
+		spec        string
+		wantIDs     []string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:    "Empty spec",
+			spec:    "",
+			wantIDs: nil,
+		},
+		{
+			name: "All algorithms",
+			spec: "k1:hs256:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=," +
+				"k2:ed25519:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=," +
+				"k3:ed25519-pub:O2onvM62pC1io6jQKm8Nc2UyFXcd4kOmOsBIoYtZ2ik=",
+			wantIDs: []string{"k1", "k2", "k3"},
+		},
+		{
+			name:        "Short HMAC secret",
+			spec:        "k1:hs256:c2hvcnQ=",
+			expectedErr: token.ErrInvalidKey,
+		},
+		{
+			name:        "Unknown algorithm",
+			spec:        "k1:rs256:c2hvcnQ=",
+			expectedErr: token.ErrInvalidKey,
+		},
+		{
+			name:        "Missing key material",
+			spec:        "k1:hs256",
+			expectedErr: token.ErrInvalidKey,
+		},
+		{
+			name:        "Missing ID",
+			spec:        ":hs256:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+			expectedErr: token.ErrInvalidKey,
+		},
+		{
+			name:        "ID with token separator",
+			spec:        "k.1:hs256:MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+			expectedErr: token.ErrInvalidKey,
+		},
+		{
+			name:        "Invalid base64",
+			spec:        "k1:hs256:not base64",
+			expectedErr: token.ErrInvalidKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := token.ParseKeys(tc.spec)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err, "expected error nil, got: %v", err)
+			var ids []string
+			for _, key := range keys {
+				ids = append(ids, key.ID())
+			}
+			assert.Equal(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func TestKeyIDs(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestKeyIDs in long mode.")
+	}
+
+	seed := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+	for _, id := range []string{"", "k.1", "k:1", "k,1"} {
+		_, err := token.NewHMACKey(id, []byte("0123456789abcdef0123456789abcdef"))
+		assert.ErrorIs(t, err, token.ErrInvalidKey, "expected HMAC key ID %q to be rejected", id)
+
+		_, err = token.NewEd25519Key(id, seed)
+		assert.ErrorIs(t, err, token.ErrInvalidKey, "expected Ed25519 key ID %q to be rejected", id)
+
+		_, err = token.NewEd25519PublicKey(id, seed.Public().(ed25519.PublicKey))
+		assert.ErrorIs(t, err, token.ErrInvalidKey, "expected Ed25519 public key ID %q to be rejected", id)
+	}
+}
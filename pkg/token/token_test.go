@@ -0,0 +1,141 @@
+package token_test
+
+import (
+	"crypto/ed25519"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/pkg/token"
+)
+
+func TestVerify(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestVerify in long mode.")
+	}
+
+	now := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	hmacKey, err := token.NewHMACKey("k1", []byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	rotatedKey, err := token.NewHMACKey("k2", []byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+	edKey, err := token.NewEd25519Key("k3", ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+	require.NoError(t, err)
+	edPublicKey, err := token.NewEd25519PublicKey("k3", edKey.PublicKey())
+	require.NoError(t, err)
+	unknownKey, err := token.NewHMACKey("k4", []byte("unknown-unknown-unknown-unknown!"))
+	require.NoError(t, err)
+
+	keyring := token.NewKeyring(hmacKey, rotatedKey, edPublicKey)
+	claims := token.Claims{ID: "id1", Discount: 10, MinBasketValue: 50, IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()}
+
+	issue := func(signer token.Signer, claims token.Claims) string {
+		tok, err := token.Issue(signer, claims)
+		require.NoError(t, err)
+		return tok
+	}
+
+	type testCase struct {
+		name        string
+		token       string
+		now         time.Time
+		want        *token.Claims
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "HMAC token",
+			token: issue(hmacKey, claims),
+			now:   now,
+			want:  &claims,
+		},
+		{
+			name:  "Token signed with rotated key",
+			token: issue(rotatedKey, claims),
+			now:   now,
+			want:  &claims,
+		},
+		{
+			name:  "Ed25519 token",
+			token: issue(edKey, claims),
+			now:   now,
+			want:  &claims,
+		},
+		{
+			name:  "Token without expiry",
+			token: issue(hmacKey, token.Claims{ID: "id2", Discount: 5}),
+			now:   now.Add(24 * 365 * time.Hour),
+			want:  &token.Claims{ID: "id2", Discount: 5},
+		},
+		{
+			name:        "Expired token",
+			token:       issue(hmacKey, claims),
+			now:         now.Add(time.Hour),
+			expectedErr: token.ErrExpired,
+		},
+		{
+			name:        "Unknown key",
+			token:       issue(unknownKey, claims),
+			now:         now,
+			expectedErr: token.ErrUnknownKey,
+		},
+		{
+			name:        "Tampered payload",
+			token:       tamper(issue(hmacKey, claims), issue(hmacKey, token.Claims{ID: "id1", Discount: 90})),
+			now:         now,
+			expectedErr: token.ErrInvalidSignature,
+		},
+		{
+			name:        "Plain coupon code",
+			token:       "SUMMER10",
+			now:         now,
+			expectedErr: token.ErrMalformed,
+		},
+		{
+			name:        "Missing signature",
+			token:       "ct1.k1.eyJpZCI6ImlkMSJ9",
+			now:         now,
+			expectedErr: token.ErrMalformed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := keyring.Verify(tc.token, tc.now)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr, "expected error %v, got: %v", tc.expectedErr, err)
+				return
+			}
+
+			require.NoError(t, err, "expected error nil, got: %v", err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// tamper swaps the payload of original with the payload of other while
+// keeping the signature of original.
+func tamper(original string, other string) string {
+	o := strings.Split(original, ".")
+	p := strings.Split(other, ".")
+	o[2] = p[2]
+	return strings.Join(o, ".")
+}
+
+func TestIssueWithPublicKey(t *testing.T) {
+	if os.Getenv("LONG") != "" {
+		t.Skip("Skipping TestIssueWithPublicKey in long mode.")
+	}
+
+	private := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	key, err := token.NewEd25519PublicKey("k1", private.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+
+	_, err = token.Issue(key, token.Claims{ID: "id1"})
+	assert.ErrorIs(t, err, token.ErrNoPrivateKey)
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/api"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/config"
+	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/domain"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/repository/memory"
 	"github.com/Yousef-Hammar/go-code-review/coupon_service/internal/service"
 )
@@ -39,7 +41,7 @@ var _ = Describe("Coupon Service API", func() {
 		logger := zap.NewNop().Sugar()
 
 		repo := memory.New()
-		srv = service.New(repo)
+		srv = service.New(repo, service.WithPromotions(memory.NewPromotions()))
 		app = api.New(cfg, logger, srv)
 
 		router = app.Mount(gin.TestMode)
@@ -111,11 +113,32 @@ var _ = Describe("Coupon Service API", func() {
 				Expect(w.Code).To(Equal(http.StatusBadRequest))
 			})
 		})
+
+		Context("with an existing code", func() {
+			It("should return 409", func() {
+				err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "taken", Discount: 10, MinBasketValue: 100})
+				Expect(err).NotTo(HaveOccurred())
+
+				body := api.CreateCouponReq{
+					Code:           "taken",
+					Discount:       20,
+					MinBasketValue: 100,
+				}
+				jsonBody, _ := json.Marshal(body)
+				req, _ := http.NewRequest(http.MethodPost, "/v1/coupons", bytes.NewBuffer(jsonBody))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				Expect(w.Code).To(Equal(http.StatusConflict))
+				Expect(w.Body.String()).To(ContainSubstring("coupon already exists"))
+			})
+		})
 	})
 
 	Describe("Getting coupons", func() {
 		BeforeEach(func() {
-			err := srv.CreateCoupon(nil, 10, "test", 100)
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "test", Discount: 10, MinBasketValue: 100})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -126,7 +149,7 @@ var _ = Describe("Coupon Service API", func() {
 				router.ServeHTTP(w, req)
 
 				Expect(w.Code).To(Equal(http.StatusOK))
-				expectedBody := `{"data":[{"code":"test","discount":10,"minBasketValue":100}]}`
+				expectedBody := `{"data":[{"code":"test","discount":10,"minBasketValue":100,"version":1}]}`
 				Expect(w.Body.String()).To(MatchJSON(expectedBody))
 			})
 		})
@@ -153,7 +176,7 @@ var _ = Describe("Coupon Service API", func() {
 
 		Context("with multiple codes", func() {
 			BeforeEach(func() {
-				err := srv.CreateCoupon(nil, 20, "test2", 200)
+				err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "test2", Discount: 20, MinBasketValue: 200})
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -163,7 +186,7 @@ var _ = Describe("Coupon Service API", func() {
 				router.ServeHTTP(w, req)
 
 				Expect(w.Code).To(Equal(http.StatusOK))
-				expectedBody := `{"data":[{"code":"test","discount":10,"minBasketValue":100},{"code":"test2","discount":20,"minBasketValue":200}]}`
+				expectedBody := `{"data":[{"code":"test","discount":10,"minBasketValue":100,"version":1},{"code":"test2","discount":20,"minBasketValue":200,"version":1}]}`
 				Expect(w.Body.String()).To(MatchJSON(expectedBody))
 			})
 		})
@@ -171,7 +194,7 @@ var _ = Describe("Coupon Service API", func() {
 
 	Describe("Applying a coupon", func() {
 		BeforeEach(func() {
-			err := srv.CreateCoupon(nil, 10, "test", 100)
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "test", Discount: 10, MinBasketValue: 100})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -225,4 +248,142 @@ var _ = Describe("Coupon Service API", func() {
 			})
 		})
 	})
+
+	Describe("Updating and deleting a coupon", func() {
+		BeforeEach(func() {
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{Code: "test", Discount: 10, MinBasketValue: 100})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		patch := func(ifMatch string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest(http.MethodPatch, "/v1/coupons/test", bytes.NewBufferString(`{"discount":20}`))
+			req.Header.Set("Content-Type", "application/json")
+			if ifMatch != "" {
+				req.Header.Set("If-Match", ifMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		It("should tag the coupon with its version", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/v1/coupons/test", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("ETag")).To(Equal(`"1"`))
+		})
+
+		It("should update the coupon it was read at and reject stale writes", func() {
+			w := patch(`"1"`)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Header().Get("ETag")).To(Equal(`"2"`))
+
+			w = patch(`"1"`)
+			Expect(w.Code).To(Equal(http.StatusPreconditionFailed))
+		})
+
+		It("should require If-Match", func() {
+			w := patch("")
+			Expect(w.Code).To(Equal(http.StatusPreconditionRequired))
+		})
+
+		It("should delete the coupon at its current version", func() {
+			Expect(patch(`"1"`).Code).To(Equal(http.StatusOK))
+
+			req, _ := http.NewRequest(http.MethodDelete, "/v1/coupons/test", nil)
+			req.Header.Set("If-Match", `"2"`)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNoContent))
+
+			req, _ = http.NewRequest(http.MethodGet, "/v1/coupons/test", nil)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Personal coupons", func() {
+		BeforeEach(func() {
+			err := srv.CreateCoupon(context.Background(), domain.Coupon{
+				Code:           "loyal",
+				Discount:       10,
+				MinBasketValue: 100,
+				Assignment:     domain.Assignment{CustomerIDs: []string{"c1"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should list the coupon for the assigned customer", func() {
+			req, _ := http.NewRequest(http.MethodGet, "/v1/customers/c1/coupons", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			expectedBody := `{"data":[{"code":"loyal","discount":10,"minBasketValue":100,"customerIds":["c1"],"version":1}]}`
+			Expect(w.Body.String()).To(MatchJSON(expectedBody))
+		})
+
+		It("should apply the coupon for the assigned customer", func() {
+			body := api.ApplyReq{
+				Basket:   api.Basket{Value: 200},
+				Code:     "loyal",
+				Customer: &api.Customer{ID: "c1"},
+			}
+			jsonBody, _ := json.Marshal(body)
+			req, _ := http.NewRequest(http.MethodPost, "/v1/coupons/basket", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+		})
+
+		It("should return 403 for another customer", func() {
+			body := api.ApplyReq{
+				Basket:   api.Basket{Value: 200},
+				Code:     "loyal",
+				Customer: &api.Customer{ID: "c2"},
+			}
+			jsonBody, _ := json.Marshal(body)
+			req, _ := http.NewRequest(http.MethodPost, "/v1/coupons/basket", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusForbidden))
+		})
+	})
+	Describe("Pricing a basket", func() {
+		BeforeEach(func() {
+			_, err := srv.CreatePromotion(context.Background(), domain.Promotion{
+				Coupon: domain.Coupon{Discount: 5, MinBasketValue: 50},
+				Name:   "summer",
+				Active: true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = srv.CreateCoupon(context.Background(), domain.Coupon{Code: "test", Discount: 10, MinBasketValue: 100})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should apply the promotion together with the entered codes", func() {
+			body := api.PriceReq{
+				Basket: api.Basket{Value: 200},
+				Codes:  []string{"test", "unknown"},
+			}
+			jsonBody, _ := json.Marshal(body)
+			req, _ := http.NewRequest(http.MethodPost, "/v1/pricing", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(w.Body.String()).To(ContainSubstring(`"value":185`))
+			Expect(w.Body.String()).To(ContainSubstring(`"name":"summer"`))
+			Expect(w.Body.String()).To(ContainSubstring(`{"code":"unknown","error":"coupon not found"}`))
+		})
+	})
 })